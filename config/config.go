@@ -0,0 +1,155 @@
+// Package config loads the default loan product from a JSON file so
+// deployments can change terms without recompiling
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/shopspring/decimal"
+)
+
+// MaxDurationWeeks is the longest loan a config may describe (10 years)
+const MaxDurationWeeks = 520
+
+// ErrInvalidConfig is returned, wrapped with the offending field, when a
+// config fails to parse or is out of range
+var ErrInvalidConfig = errors.New("invalid terms config")
+
+// TermsConfig is the on-disk form of the default loan terms. Fields left out
+// of the file keep the values from Default
+type TermsConfig struct {
+	DurationWeeks          int             `json:"duration_weeks"`
+	AnnualInterestRate     decimal.Decimal `json:"annual_interest_rate"` // e.g. 0.10 for 10%, within domain.DefaultRateBounds
+	InterestModel          string          `json:"interest_model"`       // "flat", "revolving" or "compound_weekly"
+	PaymentFrequency       string          `json:"payment_frequency"`    // "weekly", "biweekly" or "monthly"; duration_weeks counts installments
+	Currency               domain.Currency `json:"currency"`
+	OriginationFee         decimal.Decimal `json:"origination_fee"`
+	DelinquencyThreshold   int             `json:"delinquency_threshold"`
+	GracePeriodDays        int             `json:"grace_period_days"`
+	FirstPaymentDelayWeeks int             `json:"first_payment_delay_weeks"`
+
+	// Late-fee defaults for the product. The engine doesn't charge late fees,
+	// so they aren't part of Terms; callers that do can read them here
+	LateFee          decimal.Decimal `json:"late_fee"`            // Flat fee per late installment, in the configured currency
+	LateFeeAfterDays int             `json:"late_fee_after_days"` // Days past the due date before LateFee applies
+}
+
+// Default returns the config matching domain.DefaultTerms
+func Default() TermsConfig {
+	terms := domain.DefaultTerms()
+	return TermsConfig{
+		DurationWeeks:        terms.DurationWeeks,
+		AnnualInterestRate:   terms.AnnualInterestRate,
+		InterestModel:        terms.InterestModel.String(),
+		PaymentFrequency:     terms.PaymentFrequency.String(),
+		Currency:             domain.DefaultCurrency,
+		OriginationFee:       terms.OriginationFee.Amount(),
+		DelinquencyThreshold: terms.DelinquencyThreshold,
+		LateFee:              decimal.Zero,
+	}
+}
+
+// Load reads a JSON config from r on top of Default and validates it.
+// Unknown fields are rejected so typos don't silently fall back to defaults
+func Load(r io.Reader) (TermsConfig, error) {
+	cfg := Default()
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return TermsConfig{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return TermsConfig{}, err
+	}
+	return cfg, nil
+}
+
+// LoadFile reads the config at path, see Load
+func LoadFile(path string) (TermsConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return TermsConfig{}, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Validate checks every field is within its allowed range
+func (c TermsConfig) Validate() error {
+	if c.DurationWeeks < 1 || c.DurationWeeks > MaxDurationWeeks {
+		return fmt.Errorf("%w: duration_weeks must be between 1 and %d", ErrInvalidConfig, MaxDurationWeeks)
+	}
+
+	if bounds := domain.DefaultRateBounds; !bounds.Contains(c.AnnualInterestRate) {
+		return fmt.Errorf("%w: annual_interest_rate must be between %s and %s", ErrInvalidConfig, bounds.Min, bounds.Max)
+	}
+
+	if _, ok := domain.ParseInterestModel(c.InterestModel); !ok {
+		return fmt.Errorf("%w: unknown interest_model %q", ErrInvalidConfig, c.InterestModel)
+	}
+
+	if _, ok := domain.ParsePaymentFrequency(c.PaymentFrequency); !ok {
+		return fmt.Errorf("%w: unknown payment_frequency %q", ErrInvalidConfig, c.PaymentFrequency)
+	}
+
+	switch c.Currency {
+	case domain.IDR, domain.USD:
+	default:
+		return fmt.Errorf("%w: unsupported currency %q", ErrInvalidConfig, c.Currency)
+	}
+
+	if c.OriginationFee.IsNegative() {
+		return fmt.Errorf("%w: origination_fee must not be negative", ErrInvalidConfig)
+	}
+
+	if c.DelinquencyThreshold < 1 {
+		return fmt.Errorf("%w: delinquency_threshold must be at least 1", ErrInvalidConfig)
+	}
+
+	if c.GracePeriodDays < 0 {
+		return fmt.Errorf("%w: grace_period_days must not be negative", ErrInvalidConfig)
+	}
+
+	if c.FirstPaymentDelayWeeks < 0 {
+		return fmt.Errorf("%w: first_payment_delay_weeks must not be negative", ErrInvalidConfig)
+	}
+
+	if c.LateFee.IsNegative() {
+		return fmt.Errorf("%w: late_fee must not be negative", ErrInvalidConfig)
+	}
+
+	if c.LateFeeAfterDays < 0 {
+		return fmt.Errorf("%w: late_fee_after_days must not be negative", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// Terms converts the config to the LoanTerms passed to CreateLoan
+func (c TermsConfig) Terms() domain.LoanTerms {
+	model, _ := domain.ParseInterestModel(c.InterestModel)
+	frequency, _ := domain.ParsePaymentFrequency(c.PaymentFrequency)
+	return domain.LoanTerms{
+		DurationWeeks:          c.DurationWeeks,
+		AnnualInterestRate:     c.AnnualInterestRate,
+		InterestModel:          model,
+		PaymentFrequency:       frequency,
+		OriginationFee:         c.Money(c.OriginationFee),
+		DelinquencyThreshold:   c.DelinquencyThreshold,
+		GracePeriodDays:        c.GracePeriodDays,
+		FirstPaymentDelayWeeks: c.FirstPaymentDelayWeeks,
+	}
+}
+
+// Money returns amount in the configured currency, for building principals
+// that match the terms
+func (c TermsConfig) Money(amount decimal.Decimal) domain.Money {
+	return domain.NewMoneyFromDecimalWithCurrency(amount, c.Currency)
+}
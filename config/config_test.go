@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/rendikr/billing-engine/service"
+	"github.com/shopspring/decimal"
+)
+
+func TestLoadFile(t *testing.T) {
+	cfg, err := LoadFile("testdata/terms.json")
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	svc := service.NewBillingService(service.NewInMemoryRepository())
+	loan, err := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", cfg.Money(decimal.NewFromInt(10000)), cfg.Terms())
+	if err != nil {
+		t.Fatalf("CreateLoan failed: %v", err)
+	}
+
+	if loan.DurationWeeks != 25 || loan.DelinquencyThreshold != 3 || loan.GracePeriodDays != 2 {
+		t.Errorf("Expected 25 weeks, threshold 3, grace 2 days, got %d, %d, %d",
+			loan.DurationWeeks, loan.DelinquencyThreshold, loan.GracePeriodDays)
+	}
+	if !loan.InterestRate.Equal(decimal.NewFromFloat(0.12)) {
+		t.Errorf("Expected rate 0.12, got %s", loan.InterestRate)
+	}
+	if !loan.OriginationFee.Equals(domain.NewMoneyWithCurrency(50, domain.USD)) {
+		t.Errorf("Expected fee USD 50, got %s", loan.OriginationFee)
+	}
+	if loan.TotalAmount.Currency() != domain.USD {
+		t.Errorf("Expected a USD schedule, got %s", loan.TotalAmount.Currency())
+	}
+	if !cfg.Money(cfg.LateFee).Equals(domain.NewMoneyWithCurrency(5, domain.USD)) || cfg.LateFeeAfterDays != 3 {
+		t.Errorf("Expected a USD 5 late fee after 3 days, got %s after %d", cfg.Money(cfg.LateFee), cfg.LateFeeAfterDays)
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(strings.NewReader(`{"duration_weeks": 10}`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := Default()
+	want.DurationWeeks = 10
+	terms, wantTerms := cfg.Terms(), want.Terms()
+	if terms.DurationWeeks != 10 || !terms.AnnualInterestRate.Equal(wantTerms.AnnualInterestRate) ||
+		terms.InterestModel != wantTerms.InterestModel || terms.PaymentFrequency != domain.Weekly ||
+		terms.DelinquencyThreshold != wantTerms.DelinquencyThreshold ||
+		!terms.OriginationFee.Equals(wantTerms.OriginationFee) {
+		t.Errorf("Expected defaults with 10 weeks, got %+v", terms)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []string{
+		`{"duration_weeks": 0}`,
+		`{"duration_weeks": 521}`,
+		`{"annual_interest_rate": -0.1}`,
+		`{"annual_interest_rate": 1.5}`,
+		`{"interest_model": "simple"}`,
+		`{"payment_frequency": "daily"}`,
+		`{"currency": "EUR"}`,
+		`{"origination_fee": "-1"}`,
+		`{"delinquency_threshold": 0}`,
+		`{"grace_period_days": -1}`,
+		`{"first_payment_delay_weeks": -1}`,
+		`{"duration_weeks": "fifty"}`,
+		`{"late_fee": "-1"}`,
+		`{"late_fee_after_days": -1}`,
+		`{"late_fees": 1000}`,
+		`not json`,
+	}
+
+	for _, input := range tests {
+		if _, err := Load(strings.NewReader(input)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Load(%s): expected ErrInvalidConfig, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package domain
+
+import "fmt"
+
+// PaymentStrategy determines how MakeNextPayment allocates an amount across
+// the unpaid weeks
+type PaymentStrategy int
+
+const (
+	// Explicit applies the amount to the first unpaid week only; it must
+	// equal what is still due for that week
+	Explicit PaymentStrategy = iota
+
+	// OldestFirst settles unpaid weeks in order, oldest first. Whatever is
+	// left after a week is covered rolls into the next one, so an amount
+	// covering 2.5 weeks pays two weeks and half of the third. The amount
+	// may not exceed the outstanding
+	OldestFirst
+)
+
+// OverpaymentPolicy determines what MakePayment does with an amount above
+// what is still due for the week
+type OverpaymentPolicy int
+
+const (
+	// Reject refuses any amount other than what is due with ErrInvalidPaymentAmount
+	Reject OverpaymentPolicy = iota
+
+	// ApplyToFuture pays the week and spreads the excess over the following
+	// unpaid weeks, oldest first, recording one payment per week. The excess
+	// may not exceed what those weeks still owe
+	ApplyToFuture
+
+	// HoldAsCredit pays the week and keeps the excess as a credit balance,
+	// see Loan.CreditBalance. The credit is applied first whenever a week is
+	// paid, so the borrower only owes what it doesn't cover
+	HoldAsCredit
+)
+
+// CreditPaymentMethod is the Method of payments funded from the credit balance
+const CreditPaymentMethod = "credit"
+
+func (p OverpaymentPolicy) String() string {
+	switch p {
+	case Reject:
+		return "reject"
+	case ApplyToFuture:
+		return "apply_to_future"
+	case HoldAsCredit:
+		return "hold_as_credit"
+	default:
+		return "unknown"
+	}
+}
+
+func (s PaymentStrategy) String() string {
+	switch s {
+	case Explicit:
+		return "explicit"
+	case OldestFirst:
+		return "oldest_first"
+	default:
+		return "unknown"
+	}
+}
+
+// allocateOldestFirst spreads amount over the unpaid weeks in order, recording
+// one payment per week it touches. The last week may be left partly paid.
+// A delinquent RequireCatchUp loan only accepts at least the overdue amount
+func (l *Loan) allocateOldestFirst(amount Money) error {
+	if amount.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+	if amount.IsNegative() {
+		return ErrNegativeAmount
+	}
+	if outstanding := l.outstanding(); amount.IsZero() || amount.GreaterThan(outstanding) {
+		return fmt.Errorf("%w: %s is more than the outstanding %s", ErrInvalidPaymentAmount, amount, outstanding)
+	}
+	if l.mustCatchUp() && amount.LessThan(l.overdueAmount()) {
+		return ErrMustCatchUp
+	}
+
+	l.allocate(amount, 0, "", "")
+	return nil
+}
+
+// allocate spreads amount over the unpaid weeks from schedule index from
+// onwards, oldest first, and returns what is left over
+func (l *Loan) allocate(amount Money, from int, method, reference string) Money {
+	left := amount
+	for i := from; i < len(l.Schedule) && !left.IsZero(); i++ {
+		if l.Schedule[i].IsPaid {
+			continue
+		}
+
+		portion := l.Schedule[i].Remaining()
+		if left.LessThan(portion) {
+			portion = left
+		}
+		l.applyPayment(i, portion, method, reference)
+		left = left.Subtract(portion)
+	}
+	return left
+}
+
+// CreditBalance returns the overpayments held under HoldAsCredit that haven't
+// been applied to a week yet
+func (l *Loan) CreditBalance() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.creditBalance()
+}
+
+func (l *Loan) creditBalance() Money {
+	if l.credit.IsZero() {
+		return l.zero()
+	}
+	return l.credit
+}
+
+// amountDue returns what the borrower still owes for the week at index once
+// any credit balance is applied
+func (l *Loan) amountDue(index int) Money {
+	remaining := l.Schedule[index].Remaining()
+	if l.OverpaymentPolicy != HoldAsCredit {
+		return remaining
+	}
+	if credit := l.creditBalance(); credit.LessThan(remaining) {
+		return remaining.Subtract(credit)
+	}
+	return l.zero()
+}
+
+// acceptsAmount reports whether amount can pay the week at index under the
+// loan's overpayment policy
+func (l *Loan) acceptsAmount(index int, amount Money) bool {
+	due := l.amountDue(index)
+	switch {
+	case amount.Equals(due):
+		return true
+	case amount.LessThan(due):
+		return false
+	}
+
+	switch l.excessPolicy() {
+	case ApplyToFuture:
+		later := l.zero()
+		for _, entry := range l.Schedule[index+1:] {
+			later = later.Add(entry.Remaining())
+		}
+		return !amount.Subtract(due).GreaterThan(later)
+	case HoldAsCredit:
+		return true
+	default:
+		return false
+	}
+}
+
+// excessPolicy returns how the excess of an accepted overpayment is handled.
+// AcceptAtLeastWeekly loans apply it to the following weeks under Reject
+func (l *Loan) excessPolicy() OverpaymentPolicy {
+	if l.AcceptAtLeastWeekly && l.OverpaymentPolicy == Reject {
+		return ApplyToFuture
+	}
+	return l.OverpaymentPolicy
+}
+
+// settleWeek pays the week at index with amount, which acceptsAmount has
+// approved, and deals with any excess according to the overpayment policy
+func (l *Loan) settleWeek(index int, amount Money, method, reference string) {
+	remaining := l.Schedule[index].Remaining()
+
+	if l.OverpaymentPolicy == HoldAsCredit {
+		if credit := l.creditBalance(); !credit.IsZero() {
+			used := credit
+			if remaining.LessThan(used) {
+				used = remaining
+			}
+			l.applyPayment(index, used, CreditPaymentMethod, "")
+			l.credit = credit.Subtract(used)
+			remaining = remaining.Subtract(used)
+		}
+	}
+
+	cash := amount
+	if remaining.LessThan(cash) {
+		cash = remaining
+	}
+	if !cash.IsZero() {
+		l.applyPayment(index, cash, method, reference)
+	}
+
+	excess := amount.Subtract(cash)
+	if excess.IsZero() {
+		return
+	}
+	switch l.excessPolicy() {
+	case ApplyToFuture:
+		l.allocate(excess, index+1, method, reference)
+	case HoldAsCredit:
+		l.credit = l.creditBalance().Add(excess)
+		l.record(LogEntry{Kind: CreditHeld, Amount: excess})
+	}
+}
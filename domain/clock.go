@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Clock abstracts the source of the current time so that date-based
+// behaviour (current week, payment timestamps) can be controlled in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the default Clock backed by time.Now
+type SystemClock struct{}
+
+// Now returns the current system time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,75 @@
+package domain
+
+// DeferWeek moves what is still due for week to a new installment after the
+// last scheduled week ("skip a payment"), extending DurationWeeks by one. The
+// deferred week is settled at what was paid towards it, as in Restructure, so
+// it no longer counts as missed and later weeks can be paid. The new
+// installment keeps the deferred amount's principal and interest split, so
+// TotalAmount is unchanged.
+//
+// At most MaxDeferrals weeks can be deferred over the life of the loan; beyond
+// that DeferWeek fails with ErrDeferralLimitReached
+func (l *Loan) DeferWeek(week int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	return l.deferWeek(week)
+}
+
+func (l *Loan) deferWeek(week int) error {
+	if !l.isScheduledWeek(week) {
+		return ErrInvalidWeekNumber
+	}
+
+	entry := &l.Schedule[l.scheduleIndex(week)]
+	if entry.IsPaid {
+		return ErrWeekAlreadyPaid
+	}
+
+	if l.deferrals() >= l.MaxDeferrals {
+		return ErrDeferralLimitReached
+	}
+
+	deferred := *entry
+	entry.resize(entry.PaidAmount)
+	now := l.now()
+	entry.IsPaid = true
+	entry.PaidAt = &now
+	entry.Deferred = true
+
+	interest := deferred.InterestPortion.Subtract(entry.InterestPortion)
+	amount := deferred.Amount.Subtract(entry.Amount)
+	l.DurationWeeks++
+	l.Schedule = append(l.Schedule, ScheduleEntry{
+		WeekNumber:       l.DurationWeeks,
+		Amount:           amount,
+		PrincipalPortion: amount.Subtract(interest),
+		InterestPortion:  interest,
+		PaidAmount:       l.zero(),
+	})
+	l.record(LogEntry{Kind: WeekDeferred, WeekNumber: week})
+
+	return nil
+}
+
+// Deferrals returns how many weeks have been deferred with DeferWeek
+func (l *Loan) Deferrals() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.deferrals()
+}
+
+func (l *Loan) deferrals() int {
+	count := 0
+	for _, entry := range l.Schedule {
+		if entry.Deferred {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// LoanDTO is the external JSON representation of a loan. Amounts encode as
+// decimal strings with their currency, see Money.MarshalJSON
+type LoanDTO struct {
+	ID            string             `json:"id"`
+	BorrowerID    string             `json:"borrower_id"`
+	Currency      Currency           `json:"currency"` // Currency of every amount on the loan
+	Principal     Money              `json:"principal"`
+	TotalAmount   Money              `json:"total_amount"`
+	WeeklyPayment Money              `json:"weekly_payment"`
+	Outstanding   Money              `json:"outstanding"`
+	CurrentWeek   int                `json:"current_week"`
+	NextDueWeek   int                `json:"next_due_week"` // 0 once every week is paid
+	IsDelinquent  bool               `json:"is_delinquent"`
+	Schedule      []ScheduleEntryDTO `json:"schedule"`
+}
+
+// ScheduleEntryDTO is the external JSON representation of a schedule entry
+type ScheduleEntryDTO struct {
+	WeekNumber       int        `json:"week_number"`
+	Amount           Money      `json:"amount"`
+	PrincipalPortion Money      `json:"principal_portion"`
+	InterestPortion  Money      `json:"interest_portion"`
+	PaidAmount       Money      `json:"paid_amount"`
+	IsPaid           bool       `json:"is_paid"`
+	PaidAt           *time.Time `json:"paid_at,omitempty"`
+	Deferred         bool       `json:"deferred,omitempty"`
+}
+
+// ToDTO returns the loan's external representation, including the computed
+// outstanding and delinquency. Every field is read under a single lock
+func (l *Loan) ToDTO() LoanDTO {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	schedule := make([]ScheduleEntryDTO, 0, len(l.Schedule))
+	for _, entry := range copySchedule(l.Schedule) {
+		schedule = append(schedule, ScheduleEntryDTO{
+			WeekNumber:       entry.WeekNumber,
+			Amount:           entry.Amount,
+			PrincipalPortion: entry.PrincipalPortion,
+			InterestPortion:  entry.InterestPortion,
+			PaidAmount:       entry.PaidAmount,
+			IsPaid:           entry.IsPaid,
+			PaidAt:           entry.PaidAt,
+			Deferred:         entry.Deferred,
+		})
+	}
+
+	return LoanDTO{
+		ID:            l.ID,
+		BorrowerID:    l.BorrowerID,
+		Currency:      l.Principal.Currency(),
+		Principal:     l.Principal,
+		TotalAmount:   l.TotalAmount,
+		WeeklyPayment: l.WeeklyPayment,
+		Outstanding:   l.outstanding(),
+		CurrentWeek:   l.CurrentWeek,
+		NextDueWeek:   l.findFirstUnpaidWeek(),
+		IsDelinquent:  l.isDelinquent(),
+		Schedule:      schedule,
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrInvalidPaymentAmount indicates the payment amount doesn't match the expected amount
@@ -20,4 +23,108 @@ var (
 
 	// ErrPaymentOutOfSequence indicates attempting to pay a week out of sequence
 	ErrPaymentOutOfSequence = errors.New("payments must be made in sequence (cannot skip unpaid weeks)")
+
+	// ErrWeekMovedBackward indicates AdvanceToWeek was asked to move the current week backward
+	ErrWeekMovedBackward = errors.New("current week cannot move backward")
+
+	// ErrMustCatchUp indicates a single installment paid on a delinquent
+	// RequireCatchUp loan, which only accepts PayOverdue
+	ErrMustCatchUp = errors.New("loan is delinquent: the full overdue amount must be paid first")
+
+	// ErrInvalidPayoffAmount indicates a payoff amount that doesn't equal the outstanding balance
+	ErrInvalidPayoffAmount = errors.New("invalid payoff amount: must equal the outstanding balance")
+
+	// ErrInvalidPrincipal indicates a zero or negative loan principal, or one
+	// too small to give every week a positive installment
+	ErrInvalidPrincipal = errors.New("principal must be positive")
+
+	// ErrInvalidDuration indicates the loan terms have a non-positive duration
+	ErrInvalidDuration = errors.New("loan duration must be at least 1 week")
+
+	// ErrInvalidInterestRate indicates the loan terms have a negative interest rate
+	ErrInvalidInterestRate = errors.New("interest rate cannot be negative")
+
+	// ErrInterestRateOutOfRange indicates loan terms whose interest rate is outside their RateBounds
+	ErrInterestRateOutOfRange = errors.New("interest rate out of range")
+
+	// ErrInvalidRateBounds indicates loan terms whose RateBounds have a Min above their Max
+	ErrInvalidRateBounds = errors.New("rate bounds minimum cannot exceed the maximum")
+
+	// ErrInvalidDelinquencyThreshold indicates loan terms with a negative delinquency threshold
+	ErrInvalidDelinquencyThreshold = errors.New("delinquency threshold must be at least 1 week")
+
+	// ErrInvalidStartWeek indicates loan terms whose start week is outside the loan duration
+	ErrInvalidStartWeek = errors.New("start week must be within the loan duration")
+
+	// ErrInvalidGracePeriod indicates loan terms with a negative grace period
+	ErrInvalidGracePeriod = errors.New("grace period cannot be negative")
+
+	// ErrInvalidFirstPaymentDelay indicates loan terms with a negative first payment delay
+	ErrInvalidFirstPaymentDelay = errors.New("first payment delay cannot be negative")
+
+	// ErrInvalidPaymentFrequency indicates loan terms with an unknown PaymentFrequency
+	ErrInvalidPaymentFrequency = errors.New("invalid payment frequency")
+
+	// ErrInvalidRebateMethod indicates loan terms with an unknown RebateMethod
+	ErrInvalidRebateMethod = errors.New("invalid rebate method")
+
+	// ErrInvalidPromoFreeWeeks indicates loan terms whose interest-free weeks leave no week to charge interest in
+	ErrInvalidPromoFreeWeeks = errors.New("promo free weeks must be less than the loan duration")
+
+	// ErrInvalidMaxDeferrals indicates loan terms with a negative deferral cap
+	ErrInvalidMaxDeferrals = errors.New("max deferrals cannot be negative")
+
+	// ErrDeferralLimitReached indicates deferring a week after the loan's MaxDeferrals have been used
+	ErrDeferralLimitReached = errors.New("deferral limit reached")
+
+	// ErrInvalidPrepaymentAmount indicates a prepayment that can't be applied in the chosen mode
+	ErrInvalidPrepaymentAmount = errors.New("invalid prepayment amount")
+
+	// ErrInvalidPrepayMode indicates an unknown PrepayMode
+	ErrInvalidPrepayMode = errors.New("invalid prepay mode")
+
+	// ErrWeekNotPaid indicates attempting to reverse a week that has no payments
+	ErrWeekNotPaid = errors.New("this week has not been paid")
+
+	// ErrReversalOutOfSequence indicates reversing a week while a later week is still paid
+	ErrReversalOutOfSequence = errors.New("reversals must start from the latest paid week")
+
+	// ErrLoanArchived indicates attempting to modify an archived loan
+	ErrLoanArchived = errors.New("loan is archived")
+
+	// ErrInvalidMoneyFormat indicates a string that can't be parsed as a money amount
+	ErrInvalidMoneyFormat = errors.New("invalid money amount")
+
+	// ErrInvalidSplit indicates splitting money into fewer than 1 part
+	ErrInvalidSplit = errors.New("money must be split into at least 1 part")
+
+	// ErrCurrencyMismatch indicates combining amounts in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrInvalidLoan indicates a loan whose fields are inconsistent with each other
+	ErrInvalidLoan = errors.New("invalid loan")
+
+	// ErrInvalidLog indicates a PaymentLog that Rebuild can't replay
+	ErrInvalidLog = errors.New("invalid payment log")
 )
+
+// PaymentError reports a rejected payment together with the week it targeted
+// and what that week expected. It unwraps to Code, so errors.Is matches the
+// sentinel, e.g. errors.Is(err, ErrInvalidPaymentAmount)
+type PaymentError struct {
+	Code           error // Sentinel describing the failure
+	Week           int
+	ExpectedAmount Money // Still due for Week; zero when Week isn't on the schedule
+	ProvidedAmount Money
+}
+
+func (e *PaymentError) Error() string {
+	if e.Code == ErrInvalidPaymentAmount {
+		return fmt.Sprintf("week %d expects %s, got %s", e.Week, e.ExpectedAmount, e.ProvidedAmount)
+	}
+	return fmt.Sprintf("week %d: %v", e.Week, e.Code)
+}
+
+func (e *PaymentError) Unwrap() error {
+	return e.Code
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns a one-line summary of the loan for logs, e.g.
+// "loan loan-1 (borrower borrower-1): outstanding IDR 5390000, week 2, active"
+func (l *Loan) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return fmt.Sprintf("loan %s (borrower %s): outstanding %s, week %d, %s",
+		l.ID, l.BorrowerID, l.outstanding(), l.CurrentWeek, l.status())
+}
+
+// Describe returns a multi-line report of the loan's terms, balances and the
+// state of every scheduled week, for debugging
+func (l *Loan) Describe() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	paidWeeks := 0
+	for _, entry := range l.Schedule {
+		if entry.IsPaid {
+			paidWeeks++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Loan %s (borrower %s)\n", l.ID, l.BorrowerID)
+	fmt.Fprintf(&b, "  Principal:    %s at %s%% %s, %d %s installments\n",
+		l.Principal, l.InterestRate.Shift(2), l.InterestModel, l.DurationWeeks, l.PaymentFrequency)
+	fmt.Fprintf(&b, "  Total amount: %s\n", l.TotalAmount)
+	fmt.Fprintf(&b, "  Paid:         %s (%d of %d weeks)\n", l.totalPaid(), paidWeeks, len(l.Schedule))
+	fmt.Fprintf(&b, "  Outstanding:  %s\n", l.outstanding())
+	fmt.Fprintf(&b, "  Current week: %d, %d behind\n", l.CurrentWeek, max(0, l.weeksBehind()))
+	fmt.Fprintf(&b, "  Status:       %s\n", l.status())
+	b.WriteString("  Schedule:\n")
+	for _, entry := range l.Schedule {
+		state := "due"
+		switch {
+		case entry.IsPaid:
+			state = "paid"
+		case !entry.PaidAmount.IsZero():
+			state = fmt.Sprintf("partly paid, %s left", entry.Remaining())
+		case entry.WeekNumber < l.CurrentWeek:
+			state = "missed"
+		case entry.WeekNumber > l.CurrentWeek:
+			state = "upcoming"
+		}
+		fmt.Fprintf(&b, "    W%d: %s %s\n", entry.WeekNumber, entry.Amount, state)
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentFrequency is how often installments fall due. Week numbers,
+// DurationWeeks, CurrentWeek and weeks behind all count installment periods,
+// so on a Monthly loan "week 3" is the third monthly installment
+type PaymentFrequency int
+
+const (
+	// Weekly installments are due every 7 days
+	Weekly PaymentFrequency = iota
+
+	// Biweekly installments are due every 14 days
+	Biweekly
+
+	// Monthly installments are due on the same day of each calendar month as
+	// the first one. Days a month doesn't have roll over into the next month,
+	// as with time.AddDate
+	Monthly
+)
+
+func (f PaymentFrequency) String() string {
+	switch f {
+	case Weekly:
+		return "weekly"
+	case Biweekly:
+		return "biweekly"
+	case Monthly:
+		return "monthly"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePaymentFrequency returns the PaymentFrequency whose String() is name
+func ParsePaymentFrequency(name string) (PaymentFrequency, bool) {
+	for _, frequency := range []PaymentFrequency{Weekly, Biweekly, Monthly} {
+		if frequency.String() == name {
+			return frequency, true
+		}
+	}
+	return Weekly, false
+}
+
+// PeriodsPerYear returns how many installments fall due in a year; annual
+// interest rates are divided by it to get the periodic rate
+func (f PaymentFrequency) PeriodsPerYear() int {
+	switch f {
+	case Biweekly:
+		return WeeksPerYear / 2
+	case Monthly:
+		return 12
+	default:
+		return WeeksPerYear
+	}
+}
+
+// periodicRate returns the interest rate per installment period for annualRate
+func (f PaymentFrequency) periodicRate(annualRate decimal.Decimal) decimal.Decimal {
+	return annualRate.Div(decimal.NewFromInt(int64(f.PeriodsPerYear())))
+}
+
+// addPeriods returns t moved forward by periods installment periods, or back
+// when periods is negative
+func (f PaymentFrequency) addPeriods(t time.Time, periods int) time.Time {
+	switch f {
+	case Biweekly:
+		return t.Add(time.Duration(periods) * 2 * weekDuration)
+	case Monthly:
+		return t.AddDate(0, periods, 0)
+	default:
+		return t.Add(time.Duration(periods) * weekDuration)
+	}
+}
+
+// periodsBetween returns how many whole periods have elapsed from start to
+// now, 0 when now is before start
+func (f PaymentFrequency) periodsBetween(start, now time.Time) int {
+	if now.Before(start) {
+		return 0
+	}
+
+	switch f {
+	case Monthly:
+		months := (now.Year()-start.Year())*12 + int(now.Month()-start.Month())
+		if f.addPeriods(start, months).After(now) {
+			months--
+		}
+		return months
+	case Biweekly:
+		return int(now.Sub(start) / (2 * weekDuration))
+	default:
+		return int(now.Sub(start) / weekDuration)
+	}
+}
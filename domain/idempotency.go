@@ -0,0 +1,50 @@
+package domain
+
+// MaxIdempotencyKeys is how many idempotency keys a loan remembers. Once full,
+// the oldest key is forgotten and a retry using it is applied again
+const MaxIdempotencyKeys = 1000
+
+// idempotencyCache remembers the outcome of the most recent keyed payments
+// Keys are evicted in insertion order so memory stays bounded
+type idempotencyCache struct {
+	results map[string]error
+	order   []string // Ring buffer of keys, oldest at next once full
+	next    int
+}
+
+// record stores the outcome for key, evicting the oldest key when full
+func (c *idempotencyCache) record(key string, err error) {
+	if c.results == nil {
+		c.results = make(map[string]error)
+	}
+
+	if len(c.order) < MaxIdempotencyKeys {
+		c.order = append(c.order, key)
+	} else {
+		delete(c.results, c.order[c.next])
+		c.order[c.next] = key
+		c.next = (c.next + 1) % MaxIdempotencyKeys
+	}
+	c.results[key] = err
+}
+
+// MakePaymentWithKey records a payment for a specific week like MakePayment,
+// unless key was already used on this loan: then the payment is not applied
+// again and the first attempt's result (nil or its error) is returned. An
+// empty key disables the check. Only the last MaxIdempotencyKeys keys are kept
+func (l *Loan) MakePaymentWithKey(amount Money, weekNumber int, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if key == "" {
+		return l.makePayment(amount, weekNumber, "", "")
+	}
+
+	if err, seen := l.idempotency.results[key]; seen {
+		return err
+	}
+
+	err := l.makePayment(amount, weekNumber, "", "")
+	l.idempotency.record(key, err)
+	return err
+}
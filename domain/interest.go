@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// WeeksPerYear converts annual interest rates into weekly periodic rates, see
+// PaymentFrequency.PeriodsPerYear
+const WeeksPerYear = 52
+
+// InterestModel determines how interest is charged over the life of a loan
+type InterestModel int
+
+const (
+	// FlatInterest charges principal * rate up front, split evenly across all weeks
+	FlatInterest InterestModel = iota
+
+	// RevolvingInterest charges interest each week on the outstanding principal
+	// (rate / PeriodsPerYear, 52 for weekly loans), on top of a fixed principal
+	// chunk. Installments shrink as the principal is repaid
+	RevolvingInterest
+
+	// CompoundWeekly compounds interest each period (weekly by default) at
+	// rate / PeriodsPerYear and repays the loan with equal installments derived
+	// from the standard amortization formula: P * r / (1 - (1 + r)^-n)
+	CompoundWeekly
+)
+
+func (m InterestModel) String() string {
+	switch m {
+	case FlatInterest:
+		return "flat"
+	case RevolvingInterest:
+		return "revolving"
+	case CompoundWeekly:
+		return "compound_weekly"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseInterestModel returns the InterestModel whose String() is name
+func ParseInterestModel(name string) (InterestModel, bool) {
+	for _, model := range []InterestModel{FlatInterest, RevolvingInterest, CompoundWeekly} {
+		if model.String() == name {
+			return model, true
+		}
+	}
+	return FlatInterest, false
+}
+
+// InterestCalculator computes the interest and installments of a loan
+// product. Set LoanTerms.InterestCalculator to add products beyond the built-in
+// InterestModels
+type InterestCalculator interface {
+	// ComputeTotal returns principal plus the interest charged on it over
+	// weeks installments at annualRate
+	ComputeTotal(principal Money, annualRate decimal.Decimal, weeks int) Money
+
+	// BuildSchedule returns weeks unpaid installments, numbered from 1, that
+	// repay principal at annualRate and add up to ComputeTotal
+	BuildSchedule(principal Money, annualRate decimal.Decimal, weeks int) []ScheduleEntry
+}
+
+// Calculator returns the InterestCalculator implementing the model for
+// installments due at frequency. Unknown models use flat interest
+func (m InterestModel) Calculator(frequency PaymentFrequency) InterestCalculator {
+	switch m {
+	case RevolvingInterest:
+		return RevolvingInterestCalculator{Frequency: frequency}
+	case CompoundWeekly:
+		return CompoundInterestCalculator{Frequency: frequency}
+	default:
+		return FlatInterestCalculator{}
+	}
+}
+
+// FlatInterestCalculator implements FlatInterest
+type FlatInterestCalculator struct{}
+
+// ComputeTotal returns principal * (1 + annualRate), rounded to the currency's
+// minor units, whatever the number of weeks
+func (FlatInterestCalculator) ComputeTotal(principal Money, annualRate decimal.Decimal, weeks int) Money {
+	return principal.Add(principal.Multiply(annualRate).RoundToCurrency())
+}
+
+// BuildSchedule splits ComputeTotal evenly across the weeks, see
+// buildFlatSchedule
+func (FlatInterestCalculator) BuildSchedule(principal Money, annualRate decimal.Decimal, weeks int) []ScheduleEntry {
+	return buildFlatSchedule(principal, annualRate, weeks)
+}
+
+// RevolvingInterestCalculator implements RevolvingInterest for installments
+// due at Frequency
+type RevolvingInterestCalculator struct {
+	Frequency PaymentFrequency
+}
+
+// ComputeTotal returns the sum of BuildSchedule
+func (c RevolvingInterestCalculator) ComputeTotal(principal Money, annualRate decimal.Decimal, weeks int) Money {
+	return sumSchedule(c.BuildSchedule(principal, annualRate, weeks))
+}
+
+// BuildSchedule charges interest on the declining principal, see
+// buildRevolvingSchedule
+func (c RevolvingInterestCalculator) BuildSchedule(principal Money, annualRate decimal.Decimal, weeks int) []ScheduleEntry {
+	return buildRevolvingSchedule(principal, c.Frequency.periodicRate(annualRate), weeks)
+}
+
+// CompoundInterestCalculator implements CompoundWeekly for installments due at
+// Frequency
+type CompoundInterestCalculator struct {
+	Frequency PaymentFrequency
+}
+
+// ComputeTotal returns the sum of BuildSchedule
+func (c CompoundInterestCalculator) ComputeTotal(principal Money, annualRate decimal.Decimal, weeks int) Money {
+	return sumSchedule(c.BuildSchedule(principal, annualRate, weeks))
+}
+
+// BuildSchedule amortizes principal with equal installments, see
+// buildCompoundSchedule
+func (c CompoundInterestCalculator) BuildSchedule(principal Money, annualRate decimal.Decimal, weeks int) []ScheduleEntry {
+	return buildCompoundSchedule(principal, c.Frequency.periodicRate(annualRate), weeks)
+}
+
+// buildSchedule generates weeks installments repaying principal with
+// calculator, numbered from firstWeek, with the first promoWeeks weeks
+// interest-free (see deferInterest). Principals too small to give every week a
+// positive installment are rejected with ErrInvalidPrincipal
+func buildSchedule(principal Money, calculator InterestCalculator, annualInterestRate decimal.Decimal, weeks, firstWeek, promoWeeks int) ([]ScheduleEntry, error) {
+	schedule := calculator.BuildSchedule(principal, annualInterestRate, weeks)
+	if len(schedule) != weeks {
+		return nil, fmt.Errorf("%w: interest calculator returned %d installments for %d weeks", ErrInvalidDuration, len(schedule), weeks)
+	}
+
+	if promoWeeks > 0 {
+		deferInterest(schedule, promoWeeks)
+	}
+
+	zero := NewMoneyWithCurrency(0, principal.Currency())
+	for i := range schedule {
+		schedule[i].WeekNumber = firstWeek + i
+		schedule[i].PaidAmount = zero
+		schedule[i].IsPaid = false
+		schedule[i].PaidAt = nil
+		if !schedule[i].Amount.GreaterThan(zero) {
+			return nil, ErrInvalidPrincipal
+		}
+	}
+	return schedule, nil
+}
+
+// deferInterest makes the first promoWeeks installments pure principal and
+// spreads the schedule's total interest evenly over the remaining weeks,
+// rounded down to the currency's minor units with the last week absorbing the
+// remainder. The schedule total doesn't change
+func deferInterest(schedule []ScheduleEntry, promoWeeks int) {
+	zero := NewMoneyWithCurrency(0, schedule[0].Amount.Currency())
+	interest := zero
+	for _, entry := range schedule {
+		interest = interest.Add(entry.InterestPortion)
+	}
+
+	interestParts, _ := interest.Split(len(schedule) - promoWeeks)
+	for i := range schedule {
+		interestPart := zero
+		if i >= promoWeeks {
+			interestPart = interestParts[i-promoWeeks]
+		}
+
+		schedule[i].InterestPortion = interestPart
+		schedule[i].Amount = schedule[i].PrincipalPortion.Add(interestPart)
+	}
+}
+
+// buildFlatSchedule splits principal * (1 + rate) evenly across all weeks
+// Installments are rounded down to the currency's minor units and the last week
+// absorbs the remainder, so the schedule sums exactly to the total amount.
+// Interest is split across the weeks the same way
+func buildFlatSchedule(principal Money, annualInterestRate decimal.Decimal, weeks int) []ScheduleEntry {
+	// Calculate total interest: principal * rate (flat interest, not compound),
+	// rounded to the currency's minor units
+	interest := principal.Multiply(annualInterestRate).RoundToCurrency()
+	totalAmount := principal.Add(interest)
+
+	// Weekly payment: total amount / number of weeks, rounded down, with the
+	// last week absorbing the remainder
+	amounts, _ := totalAmount.Split(weeks)
+	interestParts, _ := interest.Split(weeks)
+
+	schedule := make([]ScheduleEntry, weeks)
+	for i, amount := range amounts {
+		schedule[i] = ScheduleEntry{
+			WeekNumber:       i + 1,
+			Amount:           amount,
+			PrincipalPortion: amount.Subtract(interestParts[i]),
+			InterestPortion:  interestParts[i],
+			PaidAmount:       NewMoneyWithCurrency(0, principal.Currency()),
+			IsPaid:           false,
+		}
+	}
+	return schedule
+}
+
+// buildRevolvingSchedule charges periodicRate interest each week on the
+// declining principal. Each installment is a fixed principal chunk, rounded
+// down to the currency's minor units, plus that week's interest, rounded; the
+// last week repays whatever principal is left so the loan fully amortizes
+func buildRevolvingSchedule(principal Money, periodicRate decimal.Decimal, weeks int) []ScheduleEntry {
+	places := principal.Currency().DecimalPlaces()
+	principalChunk := principal.DivideDown(decimal.NewFromInt(int64(weeks)), places).Amount()
+	remaining := principal.Amount()
+
+	schedule := make([]ScheduleEntry, weeks)
+	for i := range weeks {
+		interest := remaining.Mul(periodicRate).Round(places)
+
+		principalPart := principalChunk
+		if i == weeks-1 {
+			principalPart = remaining
+		}
+		remaining = remaining.Sub(principalPart)
+
+		schedule[i] = ScheduleEntry{
+			WeekNumber:       i + 1,
+			Amount:           NewMoneyFromDecimalWithCurrency(principalPart.Add(interest), principal.Currency()),
+			PrincipalPortion: NewMoneyFromDecimalWithCurrency(principalPart, principal.Currency()),
+			InterestPortion:  NewMoneyFromDecimalWithCurrency(interest, principal.Currency()),
+			PaidAmount:       NewMoneyWithCurrency(0, principal.Currency()),
+			IsPaid:           false,
+		}
+	}
+	return schedule
+}
+
+// buildCompoundSchedule amortizes the principal with equal installments
+// compounding at periodicRate. Each week's interest is charged on the remaining
+// balance and rounded to the currency's minor units; the final installment repays
+// the exact remaining balance so the loan fully amortizes
+func buildCompoundSchedule(principal Money, periodicRate decimal.Decimal, weeks int) []ScheduleEntry {
+	if periodicRate.IsZero() {
+		return buildRevolvingSchedule(principal, periodicRate, weeks)
+	}
+
+	// installment = P * r * (1 + r)^n / ((1 + r)^n - 1)
+	places := principal.Currency().DecimalPlaces()
+	growth, _ := decimal.NewFromInt(1).Add(periodicRate).PowInt32(int32(weeks))
+	installment := principal.Amount().Mul(periodicRate).Mul(growth).
+		Div(growth.Sub(decimal.NewFromInt(1))).
+		Round(places)
+
+	remaining := principal.Amount()
+	schedule := make([]ScheduleEntry, weeks)
+	for i := range weeks {
+		interest := remaining.Mul(periodicRate).Round(places)
+
+		principalPart := installment.Sub(interest)
+		if i == weeks-1 {
+			principalPart = remaining
+		}
+		remaining = remaining.Sub(principalPart)
+
+		schedule[i] = ScheduleEntry{
+			WeekNumber:       i + 1,
+			Amount:           NewMoneyFromDecimalWithCurrency(principalPart.Add(interest), principal.Currency()),
+			PrincipalPortion: NewMoneyFromDecimalWithCurrency(principalPart, principal.Currency()),
+			InterestPortion:  NewMoneyFromDecimalWithCurrency(interest, principal.Currency()),
+			PaidAmount:       NewMoneyWithCurrency(0, principal.Currency()),
+			IsPaid:           false,
+		}
+	}
+	return schedule
+}
+
+// sumSchedule returns the total of all scheduled amounts
+func sumSchedule(schedule []ScheduleEntry) Money {
+	total := NewMoneyWithCurrency(0, schedule[0].Amount.Currency())
+	for _, entry := range schedule {
+		total = total.Add(entry.Amount)
+	}
+	return total
+}
@@ -1,164 +1,1477 @@
 package domain
 
 import (
+	"slices"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 const (
-	// LoanDurationWeeks is the fixed loan duration
+	// LoanDurationWeeks is the default loan duration, see DefaultTerms
 	LoanDurationWeeks = 50
 
-	// DelinquencyThreshold is the number of consecutive missed payments to be delinquent
+	// DelinquencyThreshold is the default number of consecutive missed payments
+	// to be delinquent, see LoanTerms.DelinquencyThreshold
 	DelinquencyThreshold = 2
+
+	// weekDuration is the length of a single loan week
+	weekDuration = 7 * 24 * time.Hour
 )
 
 type ScheduleEntry struct {
+	WeekNumber       int
+	Amount           Money
+	PrincipalPortion Money // Part of Amount that repays principal
+	InterestPortion  Money // Part of Amount that pays interest; PrincipalPortion + InterestPortion == Amount
+	PaidAmount       Money // Sum of payments made towards this week so far
+	IsPaid           bool
+	PaidAt           *time.Time // When the week was paid in full; nil while unpaid
+	Deferred         bool       // Moved to the end of the loan by DeferWeek; settled at what was paid
+}
+
+// resize changes the installment to amount. The interest portion is kept,
+// capped at the new amount, and the rest of the installment is principal
+func (e *ScheduleEntry) resize(amount Money) {
+	interest := e.InterestPortion
+	if interest.IsZero() {
+		interest = NewMoneyWithCurrency(0, amount.Currency())
+	}
+	if amount.LessThan(interest) {
+		interest = amount
+	}
+
+	e.Amount = amount
+	e.InterestPortion = interest
+	e.PrincipalPortion = amount.Subtract(interest)
+}
+
+// Remaining returns the amount still due for the week
+func (e ScheduleEntry) Remaining() Money {
+	return e.Amount.Subtract(e.PaidAmount)
+}
+
+// PaymentTiming compares when an installment was due with when it was paid
+type PaymentTiming struct {
 	WeekNumber int
-	Amount     Money
-	IsPaid     bool
+	DueDate    time.Time
+	PaidAt     time.Time
+	DaysDelta  int // Whole days between DueDate and PaidAt; negative when paid early
 }
 
 type Payment struct {
-	WeekNumber int
+	WeekNumber int // 0 for principal prepayments, see PrepayPrincipal
 	Amount     Money
 	PaidAt     time.Time
+	Method     string // How the money arrived, e.g. "bank_transfer" or "cash"
+	Reference  string // External reference such as a bank transaction ID
+	Interest   Money  // Interest a principal prepayment settled along with the installments it removed; zero otherwise
 }
 
+// Loan is safe for concurrent use through its methods. Reading or writing the
+// exported Schedule, Payments and CurrentWeek fields directly bypasses the
+// loan's lock
 type Loan struct {
-	ID            string
-	BorrowerID    string
-	Principal     Money
-	InterestRate  decimal.Decimal // Annual interest rate (e.g., 0.10 for 10%)
-	TotalAmount   Money           // Principal + Interest
-	WeeklyPayment Money
-	Schedule      []ScheduleEntry
-	Payments      []Payment
-	CurrentWeek   int
+	ID             string
+	BorrowerID     string
+	Principal      Money
+	InterestRate   decimal.Decimal // Annual interest rate (e.g., 0.10 for 10%)
+	InterestModel  InterestModel
+	DurationWeeks  int
+	OriginationFee Money // One-off fee disclosed in TotalCostOfCredit, not part of the schedule
+	TotalAmount    Money // Principal + Interest, less any WrittenOff
+	WrittenOff     Money // Interest rebated by PayOff at the PayoffAmount; zero otherwise
+	WeeklyPayment  Money // First installment, whatever the PaymentFrequency; constant for flat interest, see Schedule otherwise
+	Schedule       []ScheduleEntry
+	Payments       []Payment
+	CurrentWeek    int
+	StartDate      time.Time // Date the loan started, week 1 begins here
+	Suspended      bool      // Set by Suspend, e.g. while a dispute is open
+	Archived       bool      // Set by Archive; archived loans reject every modification
+
+	// DelinquencyThreshold is how many weeks behind the loan is delinquent
+	DelinquencyThreshold int
+
+	// StartWeek is the first week on the schedule. Loans migrated mid-term
+	// start after week 1; earlier weeks were settled before the loan was created
+	StartWeek int
+
+	// GracePeriodDays is how long after its due date a week counts as missed
+	// in the date-aware delinquency checks
+	GracePeriodDays int
+
+	// FirstPaymentDelayWeeks is how many calendar weeks after StartDate the
+	// first scheduled week falls due. Until then the borrower isn't behind
+	FirstPaymentDelayWeeks int
+
+	// AllowNonSequential lets borrowers pay any unpaid week instead of only
+	// the first one. Weeks behind then counts the unpaid weeks up to the
+	// current week rather than the weeks since the last paid one
+	AllowNonSequential bool
+
+	// PaymentStrategy controls how MakeNextPayment allocates an amount
+	PaymentStrategy PaymentStrategy
+
+	// OverpaymentPolicy controls what happens to a payment above what is due
+	OverpaymentPolicy OverpaymentPolicy
+
+	// AcceptAtLeastWeekly makes the amount due for a week a minimum rather
+	// than an exact amount. The excess is handled by the overpayment policy;
+	// under Reject it is applied to the following weeks as with ApplyToFuture
+	AcceptAtLeastWeekly bool
+
+	// RequireCatchUp makes a delinquent loan with installments past due
+	// reject payments for single weeks with ErrMustCatchUp; PayOverdue and
+	// CatchUp settle the past due weeks together instead
+	RequireCatchUp bool
+
+	// PromoFreeWeeks is how many weeks from week 1 are interest-free. Their
+	// interest is charged in the remaining weeks, so TotalAmount is unchanged
+	PromoFreeWeeks int
+
+	// PaymentFrequency is how often installments fall due. Every week number
+	// on the loan counts installment periods of this frequency
+	PaymentFrequency PaymentFrequency
+
+	// RebateMethod controls the unearned interest PayoffAmount refunds
+	RebateMethod RebateMethod
+
+	// RateBounds is the InterestRate range the loan was created under, also
+	// applied by Restructure; nil uses DefaultRateBounds
+	RateBounds *RateBounds
+
+	// MaxDeferrals caps how many weeks DeferWeek may move to the end of the loan
+	MaxDeferrals int
+
+	clock       Clock
+	calculator  InterestCalculator // Builds the schedule, also when restructuring; see interestCalculator
+	idempotency idempotencyCache   // Outcomes of keyed payments, see MakePaymentWithKey
+	credit      Money              // Overpayments held under HoldAsCredit, see CreditBalance
+	paid        Money              // Running sum of Payments[:paidCount], see totalPaid
+	paidCount   int                // Number of Payments summed into paid
+	log         []LogEntry         // See PaymentLog
+	origin      *Loan              // The loan as created, never modified; Rebuild replays the log onto a copy
+	mu          sync.Mutex         // guards Schedule, Payments, CurrentWeek, Suspended, Archived, idempotency, credit, the paid cache and log
 }
 
-// NewLoan creates a new loan with the given parameters
-func NewLoan(id, borrowerID string, principal Money, annualInterestRate decimal.Decimal) *Loan {
-	// Calculate total interest: principal * rate (flat interest, not compound)
-	interest := principal.Multiply(annualInterestRate)
-	totalAmount := principal.Add(interest)
+// NewLoan creates a new loan under the given terms
+func NewLoan(id, borrowerID string, principal Money, terms LoanTerms) (*Loan, error) {
+	return NewLoanWithClock(id, borrowerID, principal, terms, SystemClock{})
+}
 
-	// Calculate weekly payment: total amount / number of weeks
-	weeklyPayment := totalAmount.Multiply(decimal.NewFromInt(1).Div(decimal.NewFromInt(LoanDurationWeeks)))
+// NewLoanWithClock creates a new loan that reads the current time from clock.
+// The loan's StartDate is set to clock.Now().
+// A loan with terms.StartWeek after week 1 only schedules weeks StartWeek to
+// DurationWeeks: principal is what is left to repay from StartWeek onwards,
+// and StartWeek is due on StartDate, or FirstPaymentDelayWeeks later
+func NewLoanWithClock(id, borrowerID string, principal Money, terms LoanTerms, clock Clock) (*Loan, error) {
+	if principal.IsNegative() || principal.IsZero() {
+		return nil, ErrInvalidPrincipal
+	}
 
-	// Generate payment schedule
-	schedule := make([]ScheduleEntry, LoanDurationWeeks)
-	for i := range LoanDurationWeeks {
-		schedule[i] = ScheduleEntry{
-			WeekNumber: i + 1,
-			Amount:     weeklyPayment,
-			IsPaid:     false,
-		}
+	if err := terms.Validate(); err != nil {
+		return nil, err
 	}
 
-	return &Loan{
-		ID:            id,
-		BorrowerID:    borrowerID,
-		Principal:     principal,
-		InterestRate:  annualInterestRate,
-		TotalAmount:   totalAmount,
-		WeeklyPayment: weeklyPayment,
-		Schedule:      schedule,
-		Payments:      make([]Payment, 0),
-		CurrentWeek:   1,
+	// A zero fee is taken to be in the loan's currency
+	originationFee := terms.OriginationFee
+	if originationFee.IsZero() {
+		originationFee = NewMoneyWithCurrency(0, principal.Currency())
+	} else if originationFee.Currency() != principal.Currency() {
+		return nil, ErrCurrencyMismatch
 	}
+
+	threshold := terms.DelinquencyThreshold
+	if threshold == 0 {
+		threshold = DelinquencyThreshold
+	}
+
+	startWeek := max(1, terms.StartWeek)
+	weeks := terms.DurationWeeks - startWeek + 1
+	promoWeeks := max(0, terms.PromoFreeWeeks-startWeek+1)
+
+	calculator := terms.InterestCalculator
+	if calculator == nil {
+		calculator = terms.InterestModel.Calculator(terms.PaymentFrequency)
+	}
+
+	schedule, err := buildSchedule(principal, calculator, terms.AnnualInterestRate, weeks, startWeek, promoWeeks)
+	if err != nil {
+		return nil, err
+	}
+
+	loan := &Loan{
+		ID:             id,
+		BorrowerID:     borrowerID,
+		Principal:      principal,
+		InterestRate:   terms.AnnualInterestRate,
+		InterestModel:  terms.InterestModel,
+		DurationWeeks:  terms.DurationWeeks,
+		OriginationFee: originationFee,
+		TotalAmount:    sumSchedule(schedule),
+		WrittenOff:     NewMoneyWithCurrency(0, principal.Currency()),
+		WeeklyPayment:  schedule[0].Amount,
+		Schedule:       schedule,
+		Payments:       make([]Payment, 0),
+		CurrentWeek:    startWeek,
+		StartDate:      clock.Now(),
+		clock:          clock,
+		calculator:     calculator,
+
+		DelinquencyThreshold:   threshold,
+		StartWeek:              startWeek,
+		GracePeriodDays:        terms.GracePeriodDays,
+		FirstPaymentDelayWeeks: terms.FirstPaymentDelayWeeks,
+		AllowNonSequential:     terms.AllowNonSequential,
+		PaymentStrategy:        terms.PaymentStrategy,
+		OverpaymentPolicy:      terms.OverpaymentPolicy,
+		AcceptAtLeastWeekly:    terms.AcceptAtLeastWeekly,
+		RequireCatchUp:         terms.RequireCatchUp,
+		PromoFreeWeeks:         terms.PromoFreeWeeks,
+		PaymentFrequency:       terms.PaymentFrequency,
+		RebateMethod:           terms.RebateMethod,
+		MaxDeferrals:           terms.MaxDeferrals,
+	}
+	if terms.RateBounds != nil {
+		bounds := *terms.RateBounds
+		loan.RateBounds = &bounds
+	}
+	loan.origin = loan.clone()
+	return loan, nil
 }
 
 // GetOutstanding returns the current outstanding amount on the loan
-// Outstanding = Total Amount - Sum of all successful payments
+// Outstanding = Total Amount - Sum of all successful payments, and never
+// negative; see Overpaid
 func (l *Loan) GetOutstanding() Money {
-	totalPaid := NewMoney(0)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.outstanding()
+}
+
+// TotalPaid returns the sum of every payment made, prepayments included
+func (l *Loan) TotalPaid() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.totalPaid()
+}
+
+// PaymentsMadeCount returns how many payments have been recorded, the length
+// of GetPaymentHistory()
+func (l *Loan) PaymentsMadeCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.Payments)
+}
+
+// outstanding never goes below zero; anything paid beyond TotalAmount is
+// reported by Overpaid
+func (l *Loan) outstanding() Money {
+	paid := l.totalPaid()
+	if paid.GreaterThan(l.TotalAmount) {
+		return l.zero()
+	}
+	return l.TotalAmount.Subtract(paid)
+}
+
+// Overpaid returns how much the borrower has paid beyond TotalAmount, counting
+// any credit balance, e.g. to refund it. Zero unless the loan is overpaid
+func (l *Loan) Overpaid() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	received := l.totalPaid().Add(l.creditBalance())
+	if !received.GreaterThan(l.TotalAmount) {
+		return l.zero()
+	}
+	return received.Subtract(l.TotalAmount)
+}
+
+// totalPaid returns the sum of Payments from a running total: payments
+// appended since the last call are added to it, so the cost is proportional
+// to the new payments only. Removing payments must reset paidCount
+func (l *Loan) totalPaid() Money {
+	if l.paidCount == 0 || l.paidCount > len(l.Payments) {
+		l.paid, l.paidCount = l.zero(), 0
+	}
+	for _, payment := range l.Payments[l.paidCount:] {
+		l.paid = l.paid.Add(payment.Amount)
+	}
+	l.paidCount = len(l.Payments)
+	return l.paid
+}
+
+// TotalInterestCost returns the interest charged over the life of the loan
+func (l *Loan) TotalInterestCost() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.TotalAmount.Subtract(l.Principal)
+}
+
+// OutstandingBreakdown splits GetOutstanding() into principal and interest
+// Every payment is allocated between the two in the loan's original ratio of
+// principal to interest, so the interest share is rounded to the currency's
+// minor units and the principal share takes the remainder; the two always sum to
+// the outstanding
+func (l *Loan) OutstandingBreakdown() (principalRemaining, interestRemaining Money) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	outstanding := l.outstanding()
+	interest := l.TotalAmount.Subtract(l.Principal)
+
+	interestShare := outstanding.Amount().Mul(interest.Amount()).Div(l.TotalAmount.Amount()).Round(outstanding.Currency().DecimalPlaces())
+	interestRemaining = NewMoneyFromDecimalWithCurrency(interestShare, outstanding.Currency())
+
+	return outstanding.Subtract(interestRemaining), interestRemaining
+}
+
+// InterestPaidToDate returns the interest portion of TotalPaid according to
+// the schedule's principal/interest split. A partly paid week pays its
+// interest first. Prepayments count the interest of the installments they
+// removed or reduced, see Payment.Interest
+func (l *Loan) InterestPaidToDate() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.interestPaidToDate()
+}
+
+// PrincipalPaidToDate returns the rest of TotalPaid, see InterestPaidToDate
+func (l *Loan) PrincipalPaidToDate() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.totalPaid().Subtract(l.interestPaidToDate())
+}
+
+func (l *Loan) interestPaidToDate() Money {
+	interest := l.zero()
 	for _, payment := range l.Payments {
-		totalPaid = totalPaid.Add(payment.Amount)
+		if payment.WeekNumber == 0 && !payment.Interest.IsZero() {
+			interest = interest.Add(payment.Interest)
+		}
+	}
+	for _, entry := range l.Schedule {
+		if entry.PaidAmount.LessThan(entry.InterestPortion) {
+			interest = interest.Add(entry.PaidAmount)
+		} else {
+			interest = interest.Add(entry.InterestPortion)
+		}
 	}
-	return l.TotalAmount.Subtract(totalPaid)
+	return interest
+}
+
+// TotalCostOfCredit returns what the borrower pays on top of the principal,
+// the disclosure figure for the cost of the loan: TotalAmount - Principal plus
+// the origination fee. Without fees it equals TotalInterestCost
+func (l *Loan) TotalCostOfCredit() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.TotalAmount.Subtract(l.Principal).Add(l.OriginationFee)
+}
+
+// TotalRepayable returns everything the borrower pays over the life of the
+// loan: the scheduled installments plus the origination fee. Without fees it
+// equals TotalAmount
+func (l *Loan) TotalRepayable() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.TotalAmount.Add(l.OriginationFee)
+}
+
+// EffectiveInterestAmount returns what the borrower pays on top of the
+// principal: the scheduled interest, including rounding, plus the origination
+// fee. It equals TotalCostOfCredit
+func (l *Loan) EffectiveInterestAmount() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.effectiveInterestAmount()
+}
+
+func (l *Loan) effectiveInterestAmount() Money {
+	return l.TotalAmount.Add(l.OriginationFee).Subtract(l.Principal)
+}
+
+// EffectiveAPR returns the simple annualized rate, in percent, implied by
+// EffectiveInterestAmount over the scheduled weeks:
+// interest / principal * periods per year / weeks. The default 10% flat loan
+// over 50 weeks is 10.4. Unlike AnnualInterestRate it reflects fees and rounding
+func (l *Loan) EffectiveAPR() decimal.Decimal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	weeks := l.DurationWeeks - l.firstWeek() + 1
+	if weeks <= 0 {
+		return decimal.Zero
+	}
+
+	return l.effectiveInterestAmount().Percentage(l.Principal).
+		Mul(decimal.NewFromInt(int64(l.PaymentFrequency.PeriodsPerYear()))).
+		Div(decimal.NewFromInt(int64(weeks)))
+}
+
+// ProgressPercent returns TotalPaid as a percentage of TotalAmount, rounded to
+// 2 decimal places and capped at 100 for overpaid loans
+func (l *Loan) ProgressPercent() decimal.Decimal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return decimal.Min(l.totalPaid().Percentage(l.TotalAmount), decimal.NewFromInt(100)).Round(2)
+}
+
+// WeeksProgressPercent returns the weeks paid in full as a percentage of the
+// scheduled weeks, rounded to 2 decimal places
+func (l *Loan) WeeksProgressPercent() decimal.Decimal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.Schedule) == 0 {
+		return decimal.Zero
+	}
+	paidWeeks := len(l.weeksWhere(true))
+	return decimal.NewFromInt(int64(paidWeeks * 100)).Div(decimal.NewFromInt(int64(len(l.Schedule)))).Round(2)
+}
+
+// AmountPastDue returns the unpaid amount of installments from weeks before the
+// current week. The current week's installment is due, not yet past due
+func (l *Loan) AmountPastDue() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.amountPastDue()
+}
+
+func (l *Loan) amountPastDue() Money {
+	pastDue := l.zero()
+	for _, entry := range l.Schedule {
+		if entry.WeekNumber < l.CurrentWeek && !entry.IsPaid {
+			pastDue = pastDue.Add(entry.Remaining())
+		}
+	}
+	return pastDue
+}
+
+// OutstandingIfCaughtUp returns the balance that would remain after paying all
+// past due installments today: GetOutstanding() - AmountPastDue()
+func (l *Loan) OutstandingIfCaughtUp() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.outstanding().Subtract(l.amountPastDue())
+}
+
+// ExpectedOutstandingNow returns what the outstanding would be had every
+// installment up to and including the current week been paid on schedule.
+// This is TotalAmount minus the installments due so far (and any prepayments)
+func (l *Loan) ExpectedOutstandingNow() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.expectedOutstanding()
+}
+
+func (l *Loan) expectedOutstanding() Money {
+	expected := l.zero()
+	for _, entry := range l.Schedule {
+		if entry.WeekNumber > l.CurrentWeek {
+			expected = expected.Add(entry.Amount)
+		}
+	}
+	return expected
+}
+
+// OutstandingVariance returns the actual minus the expected outstanding at the
+// current week. Positive means the borrower is behind, negative ahead
+func (l *Loan) OutstandingVariance() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.outstanding().Subtract(l.expectedOutstanding())
 }
 
 // IsDelinquent checks if the borrower is delinquent
-// A borrower is delinquent if they are behind by 2 or more weeks
-// (current week - last paid week >= 2)
+// A borrower is delinquent if they are behind by the loan's
+// DelinquencyThreshold (2 by default) or more weeks
+// (current week - last paid week >= threshold)
 func (l *Loan) IsDelinquent() bool {
-	// Find the last paid week
-	lastPaidWeek := 0
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.isDelinquent()
+}
+
+func (l *Loan) isDelinquent() bool {
+	return l.weeksBehind() >= l.delinquencyThreshold()
+}
+
+// delinquencyThreshold returns the loan's threshold, falling back to the
+// package default for loans that weren't built by NewLoan
+func (l *Loan) delinquencyThreshold() int {
+	if l.DelinquencyThreshold < 1 {
+		return DelinquencyThreshold
+	}
+	return l.DelinquencyThreshold
+}
+
+// WeeksBehind returns how many weeks the current week is past the last paid week
+func (l *Loan) WeeksBehind() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.weeksBehind()
+}
+
+// weeksBehind returns how many weeks the current week is past the last paid week
+// Loans that allow non-sequential payments count the unpaid weeks up to and
+// including the current week instead. Nobody is behind during a first payment
+// delay
+func (l *Loan) weeksBehind() int {
+	if l.inFirstPaymentDelay() {
+		return 0
+	}
+	if l.AllowNonSequential {
+		behind := 0
+		for _, entry := range l.Schedule {
+			if entry.WeekNumber <= l.CurrentWeek && !entry.IsPaid {
+				behind++
+			}
+		}
+		return behind
+	}
+	return l.CurrentWeek - l.lastPaidWeek()
+}
+
+// lastPaidWeek returns the highest paid week number. With nothing paid it is
+// the week before the first scheduled week, 0 unless the loan starts mid-term
+func (l *Loan) lastPaidWeek() int {
+	lastPaidWeek := l.firstWeek() - 1
 	for _, entry := range l.Schedule {
 		if entry.IsPaid && entry.WeekNumber > lastPaidWeek {
 			lastPaidWeek = entry.WeekNumber
 		}
 	}
+	return lastPaidWeek
+}
+
+// WasDelinquentAt reports whether the loan was delinquent when its current
+// week was week, using only payments for weeks up to week. It applies the
+// same rule as IsDelinquent, so nothing paid at week 1 is 1 week behind.
+// Payments for a week are assumed to have been made by that week; catch-up
+// payments made later count as on time. Weeks before the first scheduled week
+// are never delinquent and weeks after the last are treated as the last.
+// CurrentWeek is not changed
+func (l *Loan) WasDelinquentAt(week int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if week < l.firstWeek() {
+		return false
+	}
+	return l.weeksBehindAt(l.clampWeek(week)) >= l.delinquencyThreshold()
+}
+
+// weeksBehindAt is weeksBehind with week as the current week, ignoring
+// payments for later weeks
+func (l *Loan) weeksBehindAt(week int) int {
+	lastPaid, unpaid := l.firstWeek()-1, 0
+	for _, entry := range l.Schedule {
+		if entry.WeekNumber > week {
+			break
+		}
+		if entry.IsPaid {
+			lastPaid = entry.WeekNumber
+		} else {
+			unpaid++
+		}
+	}
+
+	if l.AllowNonSequential {
+		return unpaid
+	}
+	return week - lastPaid
+}
+
+// IsDelinquentByDate checks delinquency against the clock instead of
+// CurrentWeek: the loan is delinquent once WeeksBehindByDate reaches its
+// DelinquencyThreshold
+func (l *Loan) IsDelinquentByDate() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.weeksBehindByDate() >= l.delinquencyThreshold()
+}
 
-	// Calculate how many weeks behind
-	weeksBehind := l.CurrentWeek - lastPaidWeek
+// WeeksBehindByDate counts the weeks after the last paid week that are missed
+// according to the clock. A week is missed once its due date plus
+// GracePeriodDays has been reached; with no grace period this matches
+// WeeksBehind after SyncCurrentWeek. With AllowNonSequential every unpaid
+// missed week counts
+func (l *Loan) WeeksBehindByDate() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	// Delinquent if 2 or more weeks behind
-	return weeksBehind >= DelinquencyThreshold
+	return l.weeksBehindByDate()
+}
+
+func (l *Loan) weeksBehindByDate() int {
+	return l.weeksBehindAsOf(l.now())
+}
+
+// IsDelinquentAsOf reports whether the loan was, or will be, delinquent at now
+// by calendar: IsDelinquentByDate with now in place of the clock. Only weeks
+// paid in full by now count as paid, so a past now ignores later payments
+func (l *Loan) IsDelinquentAsOf(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.weeksBehindAsOf(now) >= l.delinquencyThreshold()
+}
+
+// WeeksBehindAsOf is WeeksBehindByDate at now, see IsDelinquentAsOf
+func (l *Loan) WeeksBehindAsOf(now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.weeksBehindAsOf(now)
+}
+
+func (l *Loan) weeksBehindAsOf(now time.Time) int {
+	grace := time.Duration(l.GracePeriodDays) * 24 * time.Hour
+	paidBy := func(entry ScheduleEntry) bool {
+		return entry.IsPaid && (entry.PaidAt == nil || !entry.PaidAt.After(now))
+	}
+
+	firstWeek := l.firstWeek()
+	if !l.AllowNonSequential {
+		for _, entry := range l.Schedule {
+			if paidBy(entry) && entry.WeekNumber >= firstWeek {
+				firstWeek = entry.WeekNumber + 1
+			}
+		}
+	}
+
+	behind := 0
+	for week := firstWeek; week <= l.DurationWeeks; week++ {
+		dueDate, _ := l.dueDateForWeek(week)
+		if now.Before(dueDate.Add(grace)) {
+			break
+		}
+		if !paidBy(l.Schedule[l.scheduleIndex(week)]) {
+			behind++
+		}
+	}
+	return behind
 }
 
 // SetCurrentWeek sets the current week (for testing/simulation)
-// In production, this would be calculated from dates
+// Out of range weeks are ignored and moving backward is allowed. Use
+// AdvanceToWeek outside simulations, or SyncCurrentWeek to derive the week from dates
 func (l *Loan) SetCurrentWeek(week int) {
-	if week >= 1 && week <= LoanDurationWeeks {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.setCurrentWeek(week)
+}
+
+// AdvanceToWeek moves the current week forward to week. Staying at the
+// current week is allowed; moving backward returns ErrWeekMovedBackward and
+// weeks outside the schedule return ErrInvalidWeekNumber
+func (l *Loan) AdvanceToWeek(week int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if week != l.clampWeek(week) {
+		return ErrInvalidWeekNumber
+	}
+	if week < l.CurrentWeek {
+		return ErrWeekMovedBackward
+	}
+	l.setCurrentWeek(week)
+	return nil
+}
+
+// MoveToWeek sets the current week like SetCurrentWeek and reports whether
+// the loan became delinquent as a result
+func (l *Loan) MoveToWeek(week int) (becameDelinquent bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.moveToWeek(week)
+}
+
+// AdvanceWeek moves the current week forward by one, staying at DurationWeeks
+// once reached. It reports whether the week moved and whether the loan became
+// delinquent as a result
+func (l *Loan) AdvanceWeek() (advanced, becameDelinquent bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	week := l.CurrentWeek
+	becameDelinquent = l.moveToWeek(l.clampWeek(week + 1))
+	return l.CurrentWeek != week, becameDelinquent
+}
+
+func (l *Loan) moveToWeek(week int) bool {
+	wasDelinquent := l.isDelinquent()
+	l.setCurrentWeek(week)
+	return !wasDelinquent && l.isDelinquent()
+}
+
+func (l *Loan) setCurrentWeek(week int) {
+	if week == l.clampWeek(week) && week != l.CurrentWeek {
 		l.CurrentWeek = week
+		l.record(LogEntry{Kind: WeekAdvanced, WeekNumber: week})
+	}
+}
+
+// CurrentWeekFromDate computes the current week from the first due date
+// and the clock: floor((now - first due date) / 7 days) + StartWeek, clamped
+// to [StartWeek, DurationWeeks]. The first due date is StartDate unless the
+// loan has a FirstPaymentDelayWeeks
+func (l *Loan) CurrentWeekFromDate() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.currentWeekFromDate()
+}
+
+func (l *Loan) currentWeekFromDate() int {
+	return l.clampWeek(l.PaymentFrequency.periodsBetween(l.firstDueDate(), l.now()) + l.firstWeek())
+}
+
+// SyncCurrentWeek updates CurrentWeek to the week derived from the clock
+func (l *Loan) SyncCurrentWeek() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.setCurrentWeek(l.currentWeekFromDate())
+}
+
+// Clone returns a deep copy of the loan taken under its lock. The copy shares
+// no slices with the original, so its exported fields can be read while the
+// original keeps taking payments. It doesn't carry over idempotency keys
+func (l *Loan) Clone() *Loan {
+	return l.clone()
+}
+
+// clone returns a deep copy of the loan that shares no mutable state
+func (l *Loan) clone() *Loan {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return &Loan{
+		ID:             l.ID,
+		BorrowerID:     l.BorrowerID,
+		Principal:      l.Principal,
+		InterestRate:   l.InterestRate,
+		InterestModel:  l.InterestModel,
+		DurationWeeks:  l.DurationWeeks,
+		OriginationFee: l.OriginationFee,
+		TotalAmount:    l.TotalAmount,
+		WrittenOff:     l.WrittenOff,
+		WeeklyPayment:  l.WeeklyPayment,
+		Schedule:       copySchedule(l.Schedule),
+		Payments:       slices.Clone(l.Payments),
+		CurrentWeek:    l.CurrentWeek,
+		StartDate:      l.StartDate,
+		Suspended:      l.Suspended,
+		Archived:       l.Archived,
+		clock:          l.clock,
+		calculator:     l.calculator,
+		credit:         l.credit,
+		paid:           l.paid,
+		paidCount:      l.paidCount,
+		log:            slices.Clone(l.log),
+		origin:         l.origin,
+
+		DelinquencyThreshold:   l.DelinquencyThreshold,
+		StartWeek:              l.StartWeek,
+		GracePeriodDays:        l.GracePeriodDays,
+		FirstPaymentDelayWeeks: l.FirstPaymentDelayWeeks,
+		AllowNonSequential:     l.AllowNonSequential,
+		PaymentStrategy:        l.PaymentStrategy,
+		OverpaymentPolicy:      l.OverpaymentPolicy,
+		AcceptAtLeastWeekly:    l.AcceptAtLeastWeekly,
+		RequireCatchUp:         l.RequireCatchUp,
+		PromoFreeWeeks:         l.PromoFreeWeeks,
+		PaymentFrequency:       l.PaymentFrequency,
+		RebateMethod:           l.RebateMethod,
+		MaxDeferrals:           l.MaxDeferrals,
+		RateBounds:             l.RateBounds,
+	}
+}
+
+// InstallmentAmount returns the first installment, the amount WeeklyPayment
+// holds for loans of any PaymentFrequency
+func (l *Loan) InstallmentAmount() Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.WeeklyPayment
+}
+
+// AmountDueForWeek returns the scheduled installment for week. Installments
+// can differ from WeeklyPayment, e.g. the last week absorbs rounding, so use
+// this rather than assuming WeeklyPayment applies to every week
+func (l *Loan) AmountDueForWeek(week int) (Money, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.isScheduledWeek(week) {
+		return Money{}, ErrInvalidWeekNumber
 	}
+	return l.Schedule[l.scheduleIndex(week)].Amount, nil
+}
+
+// DueDateForWeek returns the calendar date the installment for week is due
+// The first scheduled week is due FirstPaymentDelayWeeks after StartDate, on
+// StartDate by default, and each following week one period later: 7 days by
+// default, see PaymentFrequency
+func (l *Loan) DueDateForWeek(week int) (time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.dueDateForWeek(week)
+}
+
+func (l *Loan) dueDateForWeek(week int) (time.Time, error) {
+	if !l.isScheduledWeek(week) {
+		return time.Time{}, ErrInvalidWeekNumber
+	}
+	return l.PaymentFrequency.addPeriods(l.firstDueDate(), week-l.firstWeek()), nil
+}
+
+// interestCalculator returns the calculator the loan was built with, falling
+// back to its InterestModel for loans that weren't built by NewLoan
+func (l *Loan) interestCalculator() InterestCalculator {
+	if l.calculator == nil {
+		return l.InterestModel.Calculator(l.PaymentFrequency)
+	}
+	return l.calculator
+}
+
+// firstDueDate returns when the first scheduled week falls due
+func (l *Loan) firstDueDate() time.Time {
+	return l.StartDate.Add(time.Duration(l.FirstPaymentDelayWeeks) * weekDuration)
+}
+
+// inFirstPaymentDelay reports whether the clock is still before the first due
+// date of a loan with a FirstPaymentDelayWeeks
+func (l *Loan) inFirstPaymentDelay() bool {
+	return l.FirstPaymentDelayWeeks > 0 && l.now().Before(l.firstDueDate())
+}
+
+// ProjectedPayoffDate returns the date the final installment is due:
+// StartDate + (DurationWeeks - 1) weeks for a loan starting at week 1
+func (l *Loan) ProjectedPayoffDate() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	payoff, _ := l.dueDateForWeek(l.DurationWeeks)
+	return payoff
+}
+
+// CurrentOnTimeStreak returns how many of the most recently paid installments
+// were settled on or before their due date, counting back until a late one.
+// Deferred weeks and weeks settled without a payment, e.g. by Restructure,
+// are skipped
+func (l *Loan) CurrentOnTimeStreak() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	streak := 0
+	for i := len(l.Schedule) - 1; i >= 0; i-- {
+		entry := l.Schedule[i]
+		if !entry.IsPaid {
+			if streak > 0 {
+				break
+			}
+			continue
+		}
+
+		settled, ok := l.settledAt(entry)
+		if !ok {
+			continue
+		}
+		dueDate, _ := l.dueDateForWeek(entry.WeekNumber)
+		if settled.After(dueDate) {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// PaymentTimingSeries returns the timing of every paid installment in week
+// order. Deferred weeks and weeks settled without a payment are left out
+func (l *Loan) PaymentTimingSeries() []PaymentTiming {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	series := make([]PaymentTiming, 0)
+	for _, entry := range l.Schedule {
+		if !entry.IsPaid {
+			continue
+		}
+		if _, ok := l.settledAt(entry); !ok {
+			continue
+		}
+
+		dueDate, _ := l.dueDateForWeek(entry.WeekNumber)
+		series = append(series, PaymentTiming{
+			WeekNumber: entry.WeekNumber,
+			DueDate:    dueDate,
+			PaidAt:     *entry.PaidAt,
+			DaysDelta:  int(entry.PaidAt.Sub(dueDate) / (24 * time.Hour)),
+		})
+	}
+	return series
+}
+
+// settledAt returns when the last payment towards entry was made. It reports
+// false for deferred weeks and weeks with no payment
+func (l *Loan) settledAt(entry ScheduleEntry) (time.Time, bool) {
+	if entry.Deferred {
+		return time.Time{}, false
+	}
+
+	var settled time.Time
+	found := false
+	for _, payment := range l.Payments {
+		if payment.WeekNumber == entry.WeekNumber && (!found || payment.PaidAt.After(settled)) {
+			settled = payment.PaidAt
+			found = true
+		}
+	}
+	return settled, found
+}
+
+// now returns the current time from the loan's clock, falling back to the
+// system clock for loans that were not built through NewLoan (e.g. decoded ones)
+func (l *Loan) now() time.Time {
+	if l.clock == nil {
+		return time.Now()
+	}
+	return l.clock.Now()
+}
+
+// zero returns a zero amount in the loan's currency
+func (l *Loan) zero() Money {
+	return NewMoneyWithCurrency(0, l.Principal.Currency())
+}
+
+// clampWeek limits week to the valid range [StartWeek, DurationWeeks]
+func (l *Loan) clampWeek(week int) int {
+	return max(l.firstWeek(), min(week, l.DurationWeeks))
+}
+
+// firstWeek returns the first scheduled week, treating a zero StartWeek on
+// loans that weren't built by NewLoan as week 1
+func (l *Loan) firstWeek() int {
+	return max(1, l.StartWeek)
+}
+
+// isScheduledWeek reports whether week is on the loan's schedule
+func (l *Loan) isScheduledWeek(week int) bool {
+	return week >= l.firstWeek() && week <= l.DurationWeeks
+}
+
+// scheduleIndex returns the index of week in Schedule
+func (l *Loan) scheduleIndex(week int) int {
+	return week - l.firstWeek()
 }
 
 // MakePayment records a payment for a specific week
 // Validation:
-// - Amount is correct (must match weekly payment)
 // - Week is valid
 // - Week hasn't been paid already
 // - Payment is in sequence
+// - Amount is correct (must match the amount still due for the week; see OverpaymentPolicy and AcceptAtLeastWeekly)
 func (l *Loan) MakePayment(amount Money, weekNumber int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.makePayment(amount, weekNumber, "", "")
+}
+
+// MakePaymentWithDetails records a payment for a specific week like
+// MakePayment, keeping the payment method and reference for reconciliation
+func (l *Loan) MakePaymentWithDetails(amount Money, weekNumber int, method, reference string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.makePayment(amount, weekNumber, method, reference)
+}
+
+// MakeNextPayment records a payment for the first unpaid week and returns
+// that week. Finding the week and paying it happen under a single lock.
+// With the OldestFirst payment strategy the amount may span several weeks,
+// see PaymentStrategy
+func (l *Loan) MakeNextPayment(amount Money) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return 0, ErrLoanArchived
+	}
+
+	nextWeek := l.findFirstUnpaidWeek()
+	if nextWeek == 0 {
+		return 0, ErrLoanFullyPaid
+	}
+
+	if l.PaymentStrategy == OldestFirst {
+		if err := l.allocateOldestFirst(amount); err != nil {
+			return 0, err
+		}
+		return nextWeek, nil
+	}
+
+	if err := l.makePayment(amount, nextWeek, "", ""); err != nil {
+		return 0, err
+	}
+
+	return nextWeek, nil
+}
+
+func (l *Loan) makePayment(amount Money, weekNumber int, method, reference string) error {
+	if err := l.validatePayment(amount, weekNumber); err != nil {
+		return err
+	}
+
+	l.settleWeek(l.scheduleIndex(weekNumber), amount, method, reference)
+
+	return nil
+}
+
+// ValidatePayment reports whether MakePayment would accept amount for
+// weekNumber, returning the same error it would, without recording anything
+func (l *Loan) ValidatePayment(amount Money, weekNumber int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.validatePayment(amount, weekNumber)
+}
+
+func (l *Loan) validatePayment(amount Money, weekNumber int) error {
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	if amount.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+
+	paymentError := func(code error) error {
+		expected := l.zero()
+		if l.isScheduledWeek(weekNumber) {
+			expected = l.amountDue(l.scheduleIndex(weekNumber))
+		}
+		return &PaymentError{Code: code, Week: weekNumber, ExpectedAmount: expected, ProvidedAmount: amount}
+	}
+
+	// Validate amount is not negative
+	if amount.IsNegative() {
+		return paymentError(ErrNegativeAmount)
+	}
+
+	// Validate week number
+	if !l.isScheduledWeek(weekNumber) {
+		return paymentError(ErrInvalidWeekNumber)
+	}
+
+	if err := l.validatePaymentWeek(weekNumber); err != nil {
+		if err == ErrLoanFullyPaid {
+			return err
+		}
+		return paymentError(err)
+	}
+
+	if l.mustCatchUp() {
+		return ErrMustCatchUp
+	}
+
+	// Validate amount matches what is still due for the week, or exceeds it
+	// as far as the overpayment policy allows
+	if !l.acceptsAmount(l.scheduleIndex(weekNumber), amount) {
+		return paymentError(ErrInvalidPaymentAmount)
+	}
+
+	return nil
+}
+
+// MakePartialPayment records a payment of up to the amount still due for a week
+// Several partial payments can settle one week; the week is marked paid once
+// its accumulated payments reach the scheduled amount. Sequence rules apply to
+// the targeted week as in MakePayment
+func (l *Loan) MakePartialPayment(amount Money, weekNumber int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	if amount.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+
 	// Validate amount is not negative
 	if amount.IsNegative() {
 		return ErrNegativeAmount
 	}
 
-	// Validate amount matches weekly payment
-	if !amount.Equals(l.WeeklyPayment) {
+	// Validate week number
+	if !l.isScheduledWeek(weekNumber) {
+		return ErrInvalidWeekNumber
+	}
+
+	if err := l.validatePaymentWeek(weekNumber); err != nil {
+		return err
+	}
+
+	if l.mustCatchUp() {
+		return ErrMustCatchUp
+	}
+
+	// Validate amount is positive and doesn't exceed what is still due
+	scheduleIndex := l.scheduleIndex(weekNumber)
+	if amount.IsZero() || amount.GreaterThan(l.Schedule[scheduleIndex].Remaining()) {
 		return ErrInvalidPaymentAmount
 	}
 
-	// Check if loan is already fully paid
-	if l.GetOutstanding().IsZero() {
+	l.applyPayment(scheduleIndex, amount, "", "")
+
+	return nil
+}
+
+// CatchUp pays what is still due for every unpaid week up to CurrentWeek,
+// oldest first, as if the borrower paid everything owed. It stops at the first
+// week that can't be paid, keeping the weeks paid before it, and returns the
+// payments it recorded. A credit balance is used first under HoldAsCredit.
+// Past due weeks are settled together, as by PayOverdue, so RequireCatchUp
+// doesn't stop it
+func (l *Loan) CatchUp() ([]Payment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return nil, ErrLoanArchived
+	}
+
+	recorded := len(l.Payments)
+	l.settleOverdue()
+	for week := l.findFirstUnpaidWeek(); week != 0 && week <= l.CurrentWeek; week = l.findFirstUnpaidWeek() {
+		if err := l.makePayment(l.amountDue(l.scheduleIndex(week)), week, "", ""); err != nil {
+			return slices.Clone(l.Payments[recorded:]), err
+		}
+	}
+	return slices.Clone(l.Payments[recorded:]), nil
+}
+
+// PayOverdue pays every installment past due (before CurrentWeek) in one go,
+// recording a payment per week, and returns those payments. amount must equal what they still owe once any
+// credit balance is applied, see AmountPastDue; otherwise it fails with a
+// PaymentError wrapping ErrInvalidPaymentAmount, whose ExpectedAmount is zero
+// when nothing is past due. It is the only way to pay a delinquent loan with
+// RequireCatchUp, but works on any loan
+func (l *Loan) PayOverdue(amount Money) ([]Payment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return nil, ErrLoanArchived
+	}
+
+	if amount.Currency() != l.Principal.Currency() {
+		return nil, ErrCurrencyMismatch
+	}
+
+	if amount.IsNegative() {
+		return nil, ErrNegativeAmount
+	}
+
+	overdue := l.overdueAmount()
+	if overdue.IsZero() || !amount.Equals(overdue) {
+		return nil, &PaymentError{Code: ErrInvalidPaymentAmount, Week: l.findFirstUnpaidWeek(), ExpectedAmount: overdue, ProvidedAmount: amount}
+	}
+
+	recorded := len(l.Payments)
+	l.settleOverdue()
+	return slices.Clone(l.Payments[recorded:]), nil
+}
+
+// mustCatchUp reports whether payments for single weeks are refused until the
+// past due weeks are settled, see RequireCatchUp
+func (l *Loan) mustCatchUp() bool {
+	return l.RequireCatchUp && l.isDelinquent() && !l.amountPastDue().IsZero()
+}
+
+// overdueAmount returns what PayOverdue expects: the amount past due less any
+// credit balance
+func (l *Loan) overdueAmount() Money {
+	pastDue := l.amountPastDue()
+	if credit := l.creditBalance(); credit.LessThan(pastDue) {
+		return pastDue.Subtract(credit)
+	}
+	return l.zero()
+}
+
+// settleOverdue pays what is due for every unpaid week before CurrentWeek
+func (l *Loan) settleOverdue() {
+	for i, entry := range l.Schedule {
+		if entry.WeekNumber < l.CurrentWeek && !entry.IsPaid {
+			l.settleWeek(i, l.amountDue(i), "", "")
+		}
+	}
+}
+
+// PayOff settles the loan early with a single lump sum
+// The amount must equal the current outstanding, or PayoffAmount(CurrentWeek)
+// when the loan's RebateMethod gives a rebate. The rebate is then written off:
+// it is taken off the interest of the unpaid weeks, latest first, and off
+// TotalAmount, and recorded in WrittenOff. Every remaining week is marked paid
+// and a payment is recorded for each one with the same timestamp
+func (l *Loan) PayOff(amount Money) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	if amount.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+
+	if amount.IsNegative() {
+		return ErrNegativeAmount
+	}
+
+	outstanding := l.outstanding()
+	if outstanding.IsZero() {
 		return ErrLoanFullyPaid
 	}
 
-	// Validate week number
-	if weekNumber < 1 || weekNumber > LoanDurationWeeks {
+	rebate := l.interestRebate(l.clampWeek(l.CurrentWeek))
+	switch {
+	case amount.Equals(outstanding):
+	case !rebate.IsZero() && amount.Equals(outstanding.Subtract(rebate)):
+		l.writeOff(rebate)
+	default:
+		return ErrInvalidPayoffAmount
+	}
+
+	for i, entry := range l.Schedule {
+		if !entry.IsPaid {
+			l.applyPayment(i, entry.Remaining(), "", "")
+		}
+	}
+
+	return nil
+}
+
+// writeOff takes amount off the unpaid weeks and off TotalAmount. It cuts
+// the interest of the weeks, latest first, and only then their principal.
+// amount must not exceed the outstanding
+func (l *Loan) writeOff(amount Money) {
+	left := amount
+	for _, interestOnly := range []bool{true, false} {
+		for i := len(l.Schedule) - 1; i >= 0 && !left.IsZero(); i-- {
+			entry := &l.Schedule[i]
+			cut := entry.Remaining()
+			if interestOnly && entry.InterestPortion.LessThan(cut) {
+				cut = entry.InterestPortion
+			}
+			if left.LessThan(cut) {
+				cut = left
+			}
+
+			interestCut := cut
+			if entry.InterestPortion.LessThan(interestCut) {
+				interestCut = entry.InterestPortion
+			}
+			entry.Amount = entry.Amount.Subtract(cut)
+			entry.InterestPortion = entry.InterestPortion.Subtract(interestCut)
+			entry.PrincipalPortion = entry.PrincipalPortion.Subtract(cut.Subtract(interestCut))
+			left = left.Subtract(cut)
+		}
+	}
+
+	l.TotalAmount = l.TotalAmount.Subtract(amount)
+	l.WrittenOff = amount
+	l.record(LogEntry{Kind: RebateWrittenOff, Amount: amount})
+}
+
+// ReversePayment undoes the payments recorded for weekNumber, e.g. when an
+// operator booked them against the wrong loan or week. The week becomes unpaid
+// again and payments funded from the credit balance return to it. Only the
+// latest week with payments can be reversed, so no paid week is left after an
+// unpaid one, unless the loan allows non-sequential payments
+func (l *Loan) ReversePayment(weekNumber int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	return l.reversePayment(weekNumber)
+}
+
+func (l *Loan) reversePayment(weekNumber int) error {
+	if !l.isScheduledWeek(weekNumber) {
 		return ErrInvalidWeekNumber
 	}
 
+	index := l.scheduleIndex(weekNumber)
+	entry := &l.Schedule[index]
+	if entry.PaidAmount.IsZero() {
+		return ErrWeekNotPaid
+	}
+
+	for _, later := range l.Schedule[index+1:] {
+		if !later.PaidAmount.IsZero() && !l.AllowNonSequential {
+			return ErrReversalOutOfSequence
+		}
+	}
+
+	// Payments funded from the credit balance go back to it
+	refunded := l.zero()
+	for _, payment := range l.Payments {
+		if payment.WeekNumber == weekNumber && payment.Method == CreditPaymentMethod {
+			refunded = refunded.Add(payment.Amount)
+		}
+	}
+	if !refunded.IsZero() {
+		l.credit = l.creditBalance().Add(refunded)
+	}
+
+	l.Payments = slices.DeleteFunc(l.Payments, func(p Payment) bool {
+		return p.WeekNumber == weekNumber
+	})
+	l.paidCount = 0
+	entry.PaidAmount = l.zero()
+	entry.IsPaid = false
+	entry.PaidAt = nil
+	l.record(LogEntry{Kind: PaymentReversed, WeekNumber: weekNumber, Amount: refunded})
+
+	return nil
+}
+
+// LoadPayments replays previously recorded payments, e.g. when rebuilding a
+// loan from storage. Payments keep their PaidAt, Method and Reference and are
+// applied as recorded: amounts, sequence and the current week aren't checked.
+// A payment for a week that is already paid fails with ErrWeekAlreadyPaid and
+// one for an unscheduled week, including principal prepayments (week 0), with
+// ErrInvalidWeekNumber. Nothing is loaded unless every payment applies
+func (l *Loan) LoadPayments(payments []Payment) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	schedule := copySchedule(l.Schedule)
+	for _, payment := range payments {
+		if err := l.loadPayment(schedule, payment); err != nil {
+			return err
+		}
+	}
+
+	l.Schedule = schedule
+	l.Payments = append(l.Payments, payments...)
+	l.paidCount = 0
+	for _, payment := range payments {
+		l.record(LogEntry{Kind: PaymentMade, Payment: payment})
+	}
+
+	return nil
+}
+
+// loadPayment applies a recorded payment to schedule as LoadPayments does
+func (l *Loan) loadPayment(schedule []ScheduleEntry, payment Payment) error {
+	if payment.Amount.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+	if payment.Amount.IsNegative() {
+		return ErrNegativeAmount
+	}
+	if !l.isScheduledWeek(payment.WeekNumber) {
+		return ErrInvalidWeekNumber
+	}
+
+	entry := &schedule[l.scheduleIndex(payment.WeekNumber)]
+	if entry.IsPaid {
+		return ErrWeekAlreadyPaid
+	}
+	entry.PaidAmount = entry.PaidAmount.Add(payment.Amount)
+	if !entry.Remaining().GreaterThan(l.zero()) {
+		paidAt := payment.PaidAt
+		entry.IsPaid = true
+		entry.PaidAt = &paidAt
+	}
+	return nil
+}
+
+// validatePaymentWeek checks that weekNumber can currently receive a payment
+func (l *Loan) validatePaymentWeek(weekNumber int) error {
+	// Check if loan is already fully paid
+	if l.outstanding().IsZero() {
+		return ErrLoanFullyPaid
+	}
+
 	// Check if this specific week is already paid
-	scheduleIndex := weekNumber - 1
-	if l.Schedule[scheduleIndex].IsPaid {
+	if l.Schedule[l.scheduleIndex(weekNumber)].IsPaid {
 		return ErrWeekAlreadyPaid
 	}
 
-	// Ensure payments are made in sequence
-	// Find the first unpaid week
+	// Ensure payments are made in sequence unless the loan allows otherwise
+	if l.AllowNonSequential {
+		return nil
+	}
 	firstUnpaidWeek := l.findFirstUnpaidWeek()
 	if weekNumber != firstUnpaidWeek {
 		return ErrPaymentOutOfSequence
 	}
 
+	return nil
+}
+
+// applyPayment records a payment against the schedule entry at index and marks
+// the week paid once it has been covered in full
+func (l *Loan) applyPayment(scheduleIndex int, amount Money, method, reference string) {
+	entry := &l.Schedule[scheduleIndex]
+
 	// Record the payment
+	paidAt := l.now()
 	payment := Payment{
-		WeekNumber: weekNumber,
+		WeekNumber: entry.WeekNumber,
 		Amount:     amount,
-		PaidAt:     time.Now(),
+		PaidAt:     paidAt,
+		Method:     method,
+		Reference:  reference,
 	}
 	l.Payments = append(l.Payments, payment)
+	l.record(LogEntry{Kind: PaymentMade, Payment: payment})
 
 	// Update schedule
-	l.Schedule[scheduleIndex].IsPaid = true
-
-	return nil
+	entry.PaidAmount = entry.PaidAmount.Add(amount)
+	if entry.Remaining().IsZero() {
+		entry.IsPaid = true
+		entry.PaidAt = &paidAt
+	}
 }
 
 // findFirstUnpaidWeek returns the week number of the first unpaid week
@@ -174,24 +1487,189 @@ func (l *Loan) findFirstUnpaidWeek() int {
 
 // GetSchedule returns a copy of the payment schedule
 func (l *Loan) GetSchedule() []ScheduleEntry {
-	scheduleCopy := make([]ScheduleEntry, len(l.Schedule))
-	copy(scheduleCopy, l.Schedule)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return copySchedule(l.Schedule)
+}
+
+// ScheduleProjection returns a copy of the schedule with its first
+// assumePaidWeeks entries paid in full, for previews such as "if I pay ahead".
+// Projected weeks are paid at the clock's current time; weeks already paid
+// keep their PaidAt. The loan isn't modified. assumePaidWeeks must be between
+// 0 and the number of scheduled weeks, otherwise ErrInvalidWeekNumber
+func (l *Loan) ScheduleProjection(assumePaidWeeks int) ([]ScheduleEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if assumePaidWeeks < 0 || assumePaidWeeks > len(l.Schedule) {
+		return nil, ErrInvalidWeekNumber
+	}
+
+	projection := copySchedule(l.Schedule)
+	now := l.now()
+	for i := range projection[:assumePaidWeeks] {
+		entry := &projection[i]
+		if entry.IsPaid {
+			continue
+		}
+		paidAt := now
+		entry.PaidAmount = entry.Amount
+		entry.IsPaid = true
+		entry.PaidAt = &paidAt
+	}
+	return projection, nil
+}
+
+// DatedScheduleEntry is a schedule entry with the date it is due
+type DatedScheduleEntry struct {
+	ScheduleEntry
+	DueDate time.Time
+}
+
+// ScheduleWithDates returns a copy of the schedule with each installment's
+// due date, see DueDateForWeek
+func (l *Loan) ScheduleWithDates() []DatedScheduleEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	schedule := copySchedule(l.Schedule)
+	dated := make([]DatedScheduleEntry, len(schedule))
+	for i, entry := range schedule {
+		dueDate, _ := l.dueDateForWeek(entry.WeekNumber)
+		dated[i] = DatedScheduleEntry{ScheduleEntry: entry, DueDate: dueDate}
+	}
+	return dated
+}
+
+// copySchedule returns a copy of schedule that shares no PaidAt pointers
+func copySchedule(schedule []ScheduleEntry) []ScheduleEntry {
+	scheduleCopy := make([]ScheduleEntry, len(schedule))
+	copy(scheduleCopy, schedule)
+	for i, entry := range scheduleCopy {
+		if entry.PaidAt != nil {
+			paidAt := *entry.PaidAt
+			scheduleCopy[i].PaidAt = &paidAt
+		}
+	}
 	return scheduleCopy
 }
 
 // GetPaymentHistory returns a copy of the payment history
 func (l *Loan) GetPaymentHistory() []Payment {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	paymentsCopy := make([]Payment, len(l.Payments))
 	copy(paymentsCopy, l.Payments)
 	return paymentsCopy
 }
 
+// GetPayment returns the payment recorded for week and whether there is one.
+// Several payments towards the week, e.g. partial payments, are combined: the
+// Amount is their sum and the rest comes from the latest of them. See
+// GetPaymentsForWeek for the individual payments
+func (l *Loan) GetPayment(week int) (Payment, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	payments := l.paymentsForWeek(week)
+	if len(payments) == 0 {
+		return Payment{}, false
+	}
+
+	combined := payments[len(payments)-1]
+	combined.Amount = l.zero()
+	for _, payment := range payments {
+		combined.Amount = combined.Amount.Add(payment.Amount)
+	}
+	return combined, true
+}
+
+// GetPaymentsForWeek returns a copy of the payments recorded for week, oldest
+// first; empty when the week has none
+func (l *Loan) GetPaymentsForWeek(week int) []Payment {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.paymentsForWeek(week)
+}
+
+func (l *Loan) paymentsForWeek(week int) []Payment {
+	payments := make([]Payment, 0)
+	for _, payment := range l.Payments {
+		if payment.WeekNumber == week {
+			payments = append(payments, payment)
+		}
+	}
+	return payments
+}
+
+// DistinctPaymentAmounts returns the unique amounts found in the payment history,
+// sorted ascending. More than one value indicates irregular (partial/bulk) payments
+func (l *Loan) DistinctPaymentAmounts() []Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	amounts := make([]Money, 0)
+	for _, payment := range l.Payments {
+		if !slices.ContainsFunc(amounts, payment.Amount.Equals) {
+			amounts = append(amounts, payment.Amount)
+		}
+	}
+
+	sort.Slice(amounts, func(i, j int) bool {
+		return amounts[i].LessThan(amounts[j])
+	})
+
+	return amounts
+}
+
 // GetNextDueWeek returns the next week number that needs to be paid
 // Returns 0 if all weeks are paid
 func (l *Loan) GetNextDueWeek() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	return l.findFirstUnpaidWeek()
 }
 
+// UnpaidWeeks returns the week numbers not yet paid in full, in order.
+// Partly paid weeks are included
+func (l *Loan) UnpaidWeeks() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.weeksWhere(false)
+}
+
+// PaidWeeks returns the week numbers paid in full, in order
+func (l *Loan) PaidWeeks() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.weeksWhere(true)
+}
+
+// weeksWhere returns the week numbers whose IsPaid equals paid
+func (l *Loan) weeksWhere(paid bool) []int {
+	weeks := make([]int, 0, len(l.Schedule))
+	for _, entry := range l.Schedule {
+		if entry.IsPaid == paid {
+			weeks = append(weeks, entry.WeekNumber)
+		}
+	}
+	return weeks
+}
+
+// IsClosed reports whether payments cover TotalAmount, overpaid loans included
 func (l *Loan) IsClosed() bool {
-	return l.GetOutstanding().IsZero()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.isClosed()
+}
+
+func (l *Loan) isClosed() bool {
+	return !l.totalPaid().LessThan(l.TotalAmount)
 }
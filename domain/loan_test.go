@@ -1,16 +1,25 @@
 package domain
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 func TestNewLoan(t *testing.T) {
 	principal := NewMoney(5000000)
-	interestRate := decimal.NewFromFloat(0.10)
 
-	loan := NewLoan("loan-1", "borrower-1", principal, interestRate)
+	loan, err := NewLoan("loan-1", "borrower-1", principal, DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
 
 	// Test basic loan properties
 	if loan.ID != "loan-1" {
@@ -54,6 +63,382 @@ func TestNewLoan(t *testing.T) {
 	}
 }
 
+func TestNewLoan_CustomTerms(t *testing.T) {
+	terms := LoanTerms{
+		DurationWeeks:      20,
+		AnnualInterestRate: decimal.NewFromFloat(0.05),
+	}
+
+	loan := createTestLoanWithTerms(terms)
+
+	// 5,000,000 * 1.05 = 5,250,000 over 20 weeks = 262,500
+	if !loan.TotalAmount.Equals(NewMoney(5250000)) {
+		t.Errorf("Expected total amount IDR 5250000, got %s", loan.TotalAmount)
+	}
+	if !loan.WeeklyPayment.Equals(NewMoney(262500)) {
+		t.Errorf("Expected weekly payment IDR 262500, got %s", loan.WeeklyPayment)
+	}
+	if len(loan.Schedule) != 20 || loan.DurationWeeks != 20 {
+		t.Errorf("Expected 20 week schedule, got %d entries (duration %d)", len(loan.Schedule), loan.DurationWeeks)
+	}
+
+	// Weeks beyond the configured duration are invalid
+	if err := loan.MakePayment(NewMoney(262500), 21); !errors.Is(err, ErrInvalidWeekNumber) {
+		t.Errorf("Expected ErrInvalidWeekNumber for week 21, got %v", err)
+	}
+
+	// Current week is capped at the configured duration
+	loan.SetCurrentWeek(30)
+	if loan.CurrentWeek != 1 {
+		t.Errorf("Expected week 30 to be ignored, got current week %d", loan.CurrentWeek)
+	}
+	loan.SetCurrentWeek(20)
+	if !loan.IsDelinquent() {
+		t.Error("Expected loan to be delinquent in its final week with no payments")
+	}
+}
+
+func TestNewLoan_StartWeek(t *testing.T) {
+	terms := DefaultTerms()
+	terms.StartWeek = 10
+	loan := createTestLoanWithTerms(terms)
+
+	// Weeks 10-50 remain: 5,500,000 / 41 = 134,146 with week 50 absorbing the remainder
+	if len(loan.Schedule) != 41 || loan.Schedule[0].WeekNumber != 10 || loan.Schedule[40].WeekNumber != 50 {
+		t.Fatalf("Expected schedule for weeks 10-50, got %d entries from week %d", len(loan.Schedule), loan.Schedule[0].WeekNumber)
+	}
+	if !loan.TotalAmount.Equals(NewMoney(5500000)) || !loan.WeeklyPayment.Equals(NewMoney(134146)) {
+		t.Errorf("Expected total 5500000 at 134146 a week, got %s at %s", loan.TotalAmount, loan.WeeklyPayment)
+	}
+	if loan.CurrentWeek != 10 || loan.GetNextDueWeek() != 10 {
+		t.Errorf("Expected current and next due week 10, got %d and %d", loan.CurrentWeek, loan.GetNextDueWeek())
+	}
+	// Like week 1 of a new loan, the first week is due but not yet missed
+	if loan.IsDelinquent() || loan.WeeksBehind() != 1 {
+		t.Errorf("Expected a fresh mid-term loan 1 week behind, got %d weeks", loan.WeeksBehind())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected valid loan, got %v", err)
+	}
+
+	// Weeks before the start can't be paid
+	if err := loan.MakePayment(NewMoney(134146), 9); !errors.Is(err, ErrInvalidWeekNumber) {
+		t.Errorf("Expected ErrInvalidWeekNumber for week 9, got %v", err)
+	}
+	if err := loan.MakePayment(NewMoney(134146), 11); !errors.Is(err, ErrPaymentOutOfSequence) {
+		t.Errorf("Expected ErrPaymentOutOfSequence for week 11, got %v", err)
+	}
+	if err := loan.MakePayment(NewMoney(134146), 10); err != nil {
+		t.Fatalf("Expected week 10 payment to succeed, got %v", err)
+	}
+	if loan.GetNextDueWeek() != 11 {
+		t.Errorf("Expected next due week 11, got %d", loan.GetNextDueWeek())
+	}
+
+	// Delinquency counts from the last paid week
+	loan.SetCurrentWeek(12)
+	if !loan.IsDelinquent() || loan.WeeksBehind() != 2 {
+		t.Errorf("Expected delinquent 2 weeks behind at week 12, got %d", loan.WeeksBehind())
+	}
+
+	// Weeks before the start are out of range
+	loan.SetCurrentWeek(5)
+	if loan.CurrentWeek != 12 {
+		t.Errorf("Expected week 5 to be ignored, got current week %d", loan.CurrentWeek)
+	}
+	if _, err := loan.DueDateForWeek(9); !errors.Is(err, ErrInvalidWeekNumber) {
+		t.Errorf("Expected ErrInvalidWeekNumber for due date of week 9, got %v", err)
+	}
+}
+
+func TestNewLoan_StartWeekDates(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	terms := DefaultTerms()
+	terms.StartWeek = 10
+
+	loan, err := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, clock)
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+
+	if due, _ := loan.DueDateForWeek(10); !due.Equal(start) {
+		t.Errorf("Expected week 10 due on the start date, got %s", due)
+	}
+	if due, _ := loan.DueDateForWeek(12); !due.Equal(start.AddDate(0, 0, 14)) {
+		t.Errorf("Expected week 12 due two weeks after the start, got %s", due)
+	}
+
+	clock.Advance(15 * 24 * time.Hour)
+	if week := loan.CurrentWeekFromDate(); week != 12 {
+		t.Errorf("Expected week 12 after 15 days, got %d", week)
+	}
+}
+
+func TestNewLoan_InvalidTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		terms    LoanTerms
+		expected error
+	}{
+		{"zero duration", LoanTerms{DurationWeeks: 0, AnnualInterestRate: decimal.NewFromFloat(0.10)}, ErrInvalidDuration},
+		{"negative duration", LoanTerms{DurationWeeks: -5, AnnualInterestRate: decimal.NewFromFloat(0.10)}, ErrInvalidDuration},
+		{"negative rate", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(-0.01)}, ErrInvalidInterestRate},
+		{"negative origination fee", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10), OriginationFee: NewMoney(-1)}, ErrNegativeAmount},
+		{"negative grace period", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10), GracePeriodDays: -1}, ErrInvalidGracePeriod},
+		{"negative start week", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10), StartWeek: -1}, ErrInvalidStartWeek},
+		{"start week after duration", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10), StartWeek: 51}, ErrInvalidStartWeek},
+		{"negative delinquency threshold", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10), DelinquencyThreshold: -1}, ErrInvalidDelinquencyThreshold},
+		{"rate of 1000%", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(10.0)}, ErrInterestRateOutOfRange},
+		{"rate below custom minimum", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.01),
+			RateBounds: &RateBounds{Min: decimal.NewFromFloat(0.05), Max: decimal.NewFromFloat(0.5)}}, ErrInterestRateOutOfRange},
+		{"rate above zero-only bounds", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10), RateBounds: &RateBounds{}}, ErrInterestRateOutOfRange},
+		{"bounds minimum above maximum", LoanTerms{DurationWeeks: 50, AnnualInterestRate: decimal.NewFromFloat(0.10),
+			RateBounds: &RateBounds{Min: decimal.NewFromFloat(0.5), Max: decimal.NewFromFloat(0.05)}}, ErrInvalidRateBounds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), tt.terms)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if loan != nil {
+				t.Error("Expected no loan for invalid terms")
+			}
+		})
+	}
+
+	// Zero interest is allowed
+	terms := DefaultTerms()
+	terms.AnnualInterestRate = decimal.Zero
+	if _, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), terms); err != nil {
+		t.Errorf("Expected zero interest to be valid, got %v", err)
+	}
+
+	// The upper bound is inclusive and can be raised per product
+	terms.AnnualInterestRate = decimal.NewFromInt(1)
+	if _, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), terms); err != nil {
+		t.Errorf("Expected a 100%% rate to be within the default bounds, got %v", err)
+	}
+	terms.AnnualInterestRate = decimal.NewFromFloat(1.5)
+	terms.RateBounds = &RateBounds{Max: decimal.NewFromInt(2)}
+	if _, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), terms); err != nil {
+		t.Errorf("Expected 150%% to be within custom bounds, got %v", err)
+	}
+}
+
+func TestFlatSchedule_Rounding(t *testing.T) {
+	loan, _ := NewLoan("loan-1", "borrower-1", NewMoney(5000001), DefaultTerms())
+
+	// Interest 500,000.1 rounds to whole rupiah, and 5,500,001 / 50 = 110,000.02:
+	// 49 weeks of 110,000 and the rest in week 50
+	if !loan.WeeklyPayment.Equals(NewMoney(110000)) {
+		t.Errorf("Expected weekly payment IDR 110000, got %s", loan.WeeklyPayment)
+	}
+	if !loan.TotalAmount.Equals(NewMoney(5500001)) {
+		t.Errorf("Expected total IDR 5500001, got %s", loan.TotalAmount.Amount())
+	}
+	last := loan.Schedule[LoanDurationWeeks-1].Amount
+	if !last.Equals(NewMoney(110001)) {
+		t.Errorf("Expected last installment 110001, got %s", last.Amount())
+	}
+
+	total := NewMoney(0)
+	for _, entry := range loan.Schedule {
+		total = total.Add(entry.Amount)
+	}
+	if !total.Equals(loan.TotalAmount) {
+		t.Errorf("Expected schedule to sum to %s, got %s", loan.TotalAmount, total)
+	}
+
+	for week := 1; week <= LoanDurationWeeks; week++ {
+		if err := loan.MakePayment(loan.Schedule[week-1].Amount, week); err != nil {
+			t.Fatalf("Failed to pay week %d: %v", week, err)
+		}
+	}
+	if !loan.IsClosed() {
+		t.Errorf("Expected loan to close, %s outstanding", loan.GetOutstanding())
+	}
+}
+
+func TestAmountDueForWeek(t *testing.T) {
+	loan, _ := NewLoan("loan-1", "borrower-1", NewMoney(5000001), DefaultTerms())
+
+	tests := []struct {
+		week     int
+		expected Money
+		err      error
+	}{
+		{1, NewMoney(110000), nil},
+		{49, NewMoney(110000), nil},
+		{50, NewMoney(110001), nil},
+		{0, Money{}, ErrInvalidWeekNumber},
+		{51, Money{}, ErrInvalidWeekNumber},
+	}
+
+	for _, tt := range tests {
+		amount, err := loan.AmountDueForWeek(tt.week)
+		if err != tt.err {
+			t.Errorf("Week %d: expected error %v, got %v", tt.week, tt.err, err)
+			continue
+		}
+		if err == nil && !amount.Equals(tt.expected) {
+			t.Errorf("Week %d: expected %s, got %s", tt.week, tt.expected.Amount(), amount.Amount())
+		}
+	}
+
+	// The last week can be paid with the amount it reports
+	for week := 1; week <= LoanDurationWeeks; week++ {
+		amount, _ := loan.AmountDueForWeek(week)
+		if _, err := loan.MakeNextPayment(amount); err != nil {
+			t.Fatalf("Failed to pay week %d: %v", week, err)
+		}
+	}
+	if !loan.IsClosed() {
+		t.Errorf("Expected loan to close, %s outstanding", loan.GetOutstanding())
+	}
+}
+
+func TestNewLoan_PrincipalTooSmall(t *testing.T) {
+	// 44 * 1.1 = 48.4 can't be split into 50 positive whole installments
+	if _, err := NewLoan("loan-1", "borrower-1", NewMoney(44), DefaultTerms()); err != ErrInvalidPrincipal {
+		t.Errorf("Expected ErrInvalidPrincipal, got %v", err)
+	}
+}
+
+func TestTotalCostOfCredit(t *testing.T) {
+	t.Run("Without fees", func(t *testing.T) {
+		loan := createTestLoan()
+
+		if !loan.TotalCostOfCredit().Equals(NewMoney(500000)) {
+			t.Errorf("Expected cost of credit IDR 500000, got %s", loan.TotalCostOfCredit())
+		}
+		if !loan.TotalRepayable().Equals(loan.TotalAmount) {
+			t.Errorf("Expected total repayable %s, got %s", loan.TotalAmount, loan.TotalRepayable())
+		}
+		if !loan.TotalInterestCost().Equals(NewMoney(500000)) {
+			t.Errorf("Expected interest IDR 500000, got %s", loan.TotalInterestCost())
+		}
+	})
+
+	t.Run("With origination fee", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.OriginationFee = NewMoney(150000)
+		loan := createTestLoanWithTerms(terms)
+
+		if !loan.TotalCostOfCredit().Equals(NewMoney(650000)) {
+			t.Errorf("Expected cost of credit IDR 650000, got %s", loan.TotalCostOfCredit())
+		}
+		if !loan.TotalRepayable().Equals(NewMoney(5650000)) {
+			t.Errorf("Expected total repayable IDR 5650000, got %s", loan.TotalRepayable())
+		}
+		if !loan.TotalInterestCost().Equals(NewMoney(500000)) {
+			t.Errorf("Expected interest IDR 500000, got %s", loan.TotalInterestCost())
+		}
+
+		// The fee isn't collected through the schedule
+		if !loan.GetOutstanding().Equals(NewMoney(5500000)) {
+			t.Errorf("Expected outstanding IDR 5500000, got %s", loan.GetOutstanding())
+		}
+	})
+}
+
+func TestEffectiveAPR(t *testing.T) {
+	tolerance := decimal.RequireFromString("0.001")
+
+	tests := []struct {
+		name     string
+		fee      int64
+		interest int64
+		apr      string
+	}{
+		// 10% over 50 weeks annualizes to 10 * 52 / 50
+		{"Default terms", 0, 500000, "10.4"},
+		{"With origination fee", 150000, 650000, "13.52"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := DefaultTerms()
+			terms.OriginationFee = NewMoney(tt.fee)
+			loan := createTestLoanWithTerms(terms)
+
+			if !loan.EffectiveInterestAmount().Equals(NewMoney(tt.interest)) {
+				t.Errorf("Expected effective interest IDR %d, got %s", tt.interest, loan.EffectiveInterestAmount())
+			}
+			want := decimal.RequireFromString(tt.apr)
+			if got := loan.EffectiveAPR(); got.Sub(want).Abs().GreaterThan(tolerance) {
+				t.Errorf("Expected APR %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestLoanCurrency(t *testing.T) {
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoneyWithCurrency(5000, USD), DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+
+	if !loan.WeeklyPayment.Equals(NewMoneyWithCurrency(110, USD)) {
+		t.Errorf("Expected weekly payment USD 110, got %s", loan.WeeklyPayment)
+	}
+	if err := loan.MakePayment(NewMoney(110), 1); err != ErrCurrencyMismatch {
+		t.Errorf("Expected ErrCurrencyMismatch for an IDR payment, got %v", err)
+	}
+	if err := loan.MakePayment(NewMoneyWithCurrency(110, USD), 1); err != nil {
+		t.Errorf("Expected USD payment to succeed, got %v", err)
+	}
+	if !loan.GetOutstanding().Equals(NewMoneyWithCurrency(5390, USD)) {
+		t.Errorf("Expected outstanding USD 5390, got %s", loan.GetOutstanding())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected valid loan, got %v", err)
+	}
+
+	terms := DefaultTerms()
+	terms.OriginationFee = NewMoney(100)
+	if _, err := NewLoan("loan-2", "borrower-1", NewMoneyWithCurrency(5000, USD), terms); err != ErrCurrencyMismatch {
+		t.Errorf("Expected ErrCurrencyMismatch for an IDR fee, got %v", err)
+	}
+}
+
+func TestLoanCurrency_ScheduleInCents(t *testing.T) {
+	cents := func(s string) Money { return NewMoneyFromDecimalWithCurrency(decimal.RequireFromString(s), USD) }
+
+	// USD 1001 at 10% is USD 1101.10 over 50 weeks: 22.022 a week
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoneyWithCurrency(1001, USD), DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+
+	if !loan.Schedule[0].Amount.Equals(cents("22.02")) || !loan.Schedule[49].Amount.Equals(cents("22.12")) {
+		t.Errorf("Expected USD 22.02 a week and USD 22.12 in the last, got %s and %s", loan.Schedule[0].Amount, loan.Schedule[49].Amount)
+	}
+	if !loan.Schedule[0].InterestPortion.Equals(cents("2.00")) || !loan.Schedule[49].InterestPortion.Equals(cents("2.10")) {
+		t.Errorf("Expected USD 2.00 interest a week and USD 2.10 in the last, got %s and %s", loan.Schedule[0].InterestPortion, loan.Schedule[49].InterestPortion)
+	}
+	if !loan.TotalAmount.Equals(cents("1101.10")) {
+		t.Errorf("Expected total USD 1101.10, got %s", loan.TotalAmount)
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected valid loan, got %v", err)
+	}
+
+	// Revolving interest is charged in cents too
+	terms := DefaultTerms()
+	terms.InterestModel = RevolvingInterest
+	revolving, err := NewLoan("loan-2", "borrower-1", NewMoneyWithCurrency(1001, USD), terms)
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	// 1001 / 50 = 20.02 principal and 1001 * (0.10 / 52) = 1.92499... interest, rounded to 1.92
+	if !revolving.Schedule[0].Amount.Equals(cents("21.94")) {
+		t.Errorf("Expected USD 21.94 in week 1, got %s", revolving.Schedule[0].Amount)
+	}
+}
+
 func TestGetOutstanding(t *testing.T) {
 	loan := createTestLoan()
 
@@ -78,6 +463,60 @@ func TestGetOutstanding(t *testing.T) {
 	}
 }
 
+func TestTotalPaid(t *testing.T) {
+	loan := createTestLoan()
+
+	if !loan.TotalPaid().IsZero() || loan.PaymentsMadeCount() != 0 {
+		t.Errorf("Expected nothing paid, got %s in %d payments", loan.TotalPaid(), loan.PaymentsMadeCount())
+	}
+
+	for week := 1; week <= 3; week++ {
+		loan.MakePayment(NewMoney(110000), week)
+	}
+	loan.PrepayPrincipal(NewMoney(110000), ReduceTerm)
+
+	if !loan.TotalPaid().Equals(NewMoney(440000)) {
+		t.Errorf("Expected IDR 440000 paid, got %s", loan.TotalPaid())
+	}
+	if loan.PaymentsMadeCount() != 4 {
+		t.Errorf("Expected 4 payments, got %d", loan.PaymentsMadeCount())
+	}
+	if !loan.TotalPaid().Add(loan.GetOutstanding()).Equals(loan.TotalAmount) {
+		t.Errorf("Expected paid plus outstanding to equal %s", loan.TotalAmount)
+	}
+}
+
+func TestOutstandingVariance(t *testing.T) {
+	tests := []struct {
+		name             string
+		paidWeeks        int
+		currentWeek      int
+		expectedVariance int64
+	}{
+		{"On schedule", 3, 3, 0},
+		{"Ahead", 5, 3, -220000},
+		{"Behind", 1, 3, 220000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			for week := 1; week <= tt.paidWeeks; week++ {
+				loan.MakePayment(NewMoney(110000), week)
+			}
+			loan.SetCurrentWeek(tt.currentWeek)
+
+			// 47 installments remain after week 3
+			if expected := loan.ExpectedOutstandingNow(); !expected.Equals(NewMoney(5170000)) {
+				t.Errorf("Expected IDR 5170000 outstanding on schedule, got %s", expected)
+			}
+			if variance := loan.OutstandingVariance(); !variance.Equals(NewMoney(tt.expectedVariance)) {
+				t.Errorf("Expected variance IDR %d, got %s", tt.expectedVariance, variance)
+			}
+		})
+	}
+}
+
 func TestIsDelinquent(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -151,242 +590,3363 @@ func TestIsDelinquent(t *testing.T) {
 	}
 }
 
-// Helper to find last paid week
-func findLastPaidWeek(loan *Loan) int {
-	lastPaid := 0
-	for _, entry := range loan.Schedule {
-		if entry.IsPaid && entry.WeekNumber > lastPaid {
-			lastPaid = entry.WeekNumber
+func TestOutstandingIfCaughtUp(t *testing.T) {
+	t.Run("Current loan", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.MakePayment(NewMoney(110000), 2)
+		loan.SetCurrentWeek(3)
+
+		if !loan.AmountPastDue().IsZero() {
+			t.Errorf("Expected nothing past due, got %s", loan.AmountPastDue())
 		}
-	}
-	return lastPaid
-}
+		if !loan.OutstandingIfCaughtUp().Equals(loan.GetOutstanding()) {
+			t.Errorf("Expected %s, got %s", loan.GetOutstanding(), loan.OutstandingIfCaughtUp())
+		}
+	})
 
-func TestMakePayment_Success(t *testing.T) {
-	loan := createTestLoan()
+	t.Run("Three weeks behind", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.SetCurrentWeek(4)
 
-	// Make first payment
-	err := loan.MakePayment(NewMoney(110000), 1)
-	if err != nil {
-		t.Errorf("Expected successful payment, got error: %v", err)
-	}
+		// Weeks 1-3 are past due, week 4 is due now
+		if !loan.AmountPastDue().Equals(NewMoney(330000)) {
+			t.Errorf("Expected IDR 330000 past due, got %s", loan.AmountPastDue())
+		}
+		if !loan.OutstandingIfCaughtUp().Equals(NewMoney(5170000)) {
+			t.Errorf("Expected IDR 5170000, got %s", loan.OutstandingIfCaughtUp())
+		}
+	})
 
-	// Verify payment was recorded
-	if len(loan.Payments) != 1 {
-		t.Errorf("Expected 1 payment recorded, got %d", len(loan.Payments))
+	t.Run("Partially paid past due week", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.MakePartialPayment(NewMoney(10000), 1)
+		loan.SetCurrentWeek(2)
+
+		if !loan.AmountPastDue().Equals(NewMoney(100000)) {
+			t.Errorf("Expected IDR 100000 past due, got %s", loan.AmountPastDue())
+		}
+	})
+}
+
+func TestIsDelinquent_Threshold(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   int
+		weeksBehind int
+		expected    bool
+	}{
+		{"Default - 1 week behind", 0, 1, false},
+		{"Default - 2 weeks behind", 0, 2, true},
+		{"Strict - on time", 1, 0, false},
+		{"Strict - 1 week behind", 1, 1, true},
+		{"Lenient - 2 weeks behind", 3, 2, false},
+		{"Lenient - 3 weeks behind", 3, 3, true},
 	}
 
-	// Verify schedule was updated
-	if !loan.Schedule[0].IsPaid {
-		t.Error("Expected week 1 to be marked as paid")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := DefaultTerms()
+			terms.DelinquencyThreshold = tt.threshold
+			loan := createTestLoanWithTerms(terms)
+
+			// Pay weeks 1-5, then fall behind
+			for week := 1; week <= 5; week++ {
+				loan.MakePayment(NewMoney(110000), week)
+			}
+			loan.SetCurrentWeek(5 + tt.weeksBehind)
+
+			if delinquent := loan.IsDelinquent(); delinquent != tt.expected {
+				t.Errorf("Expected delinquent=%v with threshold %d at %d weeks behind, got %v", tt.expected, loan.DelinquencyThreshold, tt.weeksBehind, delinquent)
+			}
+			if info := loan.DelinquencyInfo(); info.IsDelinquent != tt.expected {
+				t.Errorf("Expected DelinquencyInfo to agree, got %v", info.IsDelinquent)
+			}
+		})
 	}
+}
 
-	// Make second payment
-	err = loan.MakePayment(NewMoney(110000), 2)
-	if err != nil {
-		t.Errorf("Expected successful payment, got error: %v", err)
+func TestDelinquencyInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		paidWeeks    []int
+		currentWeek  int
+		delinquent   bool
+		weeksBehind  int
+		lastPaidWeek int
+		overdue      int64
+		partial      int64 // Partly paid on the week after paidWeeks
+	}{
+		{"Current", []int{1, 2}, 2, false, 0, 2, 0, 0},
+		{"One week behind", []int{1}, 2, false, 1, 1, 110000, 0},
+		{"Three weeks behind", []int{1, 2}, 5, true, 3, 2, 330000, 0},
+		{"Paid ahead", []int{1, 2, 3}, 1, false, 0, 3, 0, 0},
+		{"Clamped to outstanding", makeRange(1, 48), 50, true, 2, 48, 120000, 100000},
 	}
 
-	if len(loan.Payments) != 2 {
-		t.Errorf("Expected 2 payments recorded, got %d", len(loan.Payments))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			for _, week := range tt.paidWeeks {
+				loan.MakePayment(NewMoney(110000), week)
+			}
+			if tt.partial > 0 {
+				loan.MakePartialPayment(NewMoney(tt.partial), len(tt.paidWeeks)+1)
+			}
+			loan.SetCurrentWeek(tt.currentWeek)
+
+			info := loan.DelinquencyInfo()
+			if info.IsDelinquent != tt.delinquent || info.IsDelinquent != loan.IsDelinquent() {
+				t.Errorf("Expected delinquent=%v consistent with IsDelinquent, got %v", tt.delinquent, info.IsDelinquent)
+			}
+			if info.WeeksBehind != tt.weeksBehind || info.LastPaidWeek != tt.lastPaidWeek {
+				t.Errorf("Expected %d weeks behind after week %d, got %+v", tt.weeksBehind, tt.lastPaidWeek, info)
+			}
+			if !info.OverdueAmount.Equals(NewMoney(tt.overdue)) {
+				t.Errorf("Expected overdue %d, got %s", tt.overdue, info.OverdueAmount)
+			}
+		})
 	}
 }
 
-func TestMakePayment_InvalidAmount(t *testing.T) {
-	loan := createTestLoan()
+func TestDelinquencyInfo_StartWeek(t *testing.T) {
+	terms := DefaultTerms()
+	terms.StartWeek = 10
+	loan := createTestLoanWithTerms(terms)
 
-	// Try to pay wrong amount
-	err := loan.MakePayment(NewMoney(100000), 1)
-	if err != ErrInvalidPaymentAmount {
-		t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+	// Nothing paid: the week before the first scheduled week
+	if info := loan.DelinquencyInfo(); info.LastPaidWeek != 9 || info.WeeksBehind != 1 {
+		t.Errorf("Expected last paid week 9 and 1 week behind at week 10, got %+v", info)
 	}
 
-	// Try to pay more than required
-	err = loan.MakePayment(NewMoney(120000), 1)
-	if err != ErrInvalidPaymentAmount {
-		t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+	due, _ := loan.AmountDueForWeek(10)
+	loan.MakePayment(due, 10)
+	loan.SetCurrentWeek(12)
+	if info := loan.DelinquencyInfo(); info.LastPaidWeek != 10 || info.WeeksBehind != 2 || !info.IsDelinquent {
+		t.Errorf("Expected last paid week 10 and 2 weeks behind at week 12, got %+v", info)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.SetCurrentWeek(4)
+
+	snapshot := loan.Snapshot()
+	want := LoanSnapshot{
+		LoanID:        "loan-1",
+		Currency:      "IDR",
+		Outstanding:   "5390000",
+		PaidToDate:    "110000",
+		WeeksPaid:     1,
+		CurrentWeek:   4,
+		IsDelinquent:  true,
+		WeeksBehind:   3,
+		LastPaidWeek:  1,
+		OverdueAmount: "330000",
+		GeneratedAt:   clock.Now(),
+	}
+	if snapshot != want {
+		t.Errorf("Expected snapshot %+v, got %+v", want, snapshot)
+	}
+
+	// Later changes to the loan leave the captured snapshot alone
+	clock.Advance(weekDuration)
+	loan.MakePayment(NewMoney(110000), 2)
+	loan.SetCurrentWeek(10)
+	if snapshot != want {
+		t.Errorf("Expected snapshot to stay %+v, got %+v", want, snapshot)
+	}
+	if later := loan.Snapshot(); later.WeeksPaid != 2 || !later.GeneratedAt.After(snapshot.GeneratedAt) {
+		t.Errorf("Expected a new snapshot to reflect the payment, got %+v", later)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		paidWeeks   int
+		currentWeek int
+		expected    LoanStatus
+	}{
+		{"new loan", 0, 1, Active},
+		{"one week behind", 1, 2, Active},
+		{"two weeks behind", 1, 3, Delinquent},
+		{"just short of default", 2, 2 + DefaultThreshold - 1, Delinquent},
+		{"defaulted", 2, 2 + DefaultThreshold, Defaulted},
+		{"paid off", LoanDurationWeeks, LoanDurationWeeks, PaidOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			for week := 1; week <= tt.paidWeeks; week++ {
+				loan.MakePayment(NewMoney(110000), week)
+			}
+			loan.SetCurrentWeek(tt.currentWeek)
+
+			if status := loan.Status(); status != tt.expected {
+				t.Errorf("Expected status %s, got %s", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestStatus_HighDelinquencyThreshold(t *testing.T) {
+	terms := DefaultTerms()
+	terms.DelinquencyThreshold = 12
+	loan := createTestLoanWithTerms(terms)
+	loan.MakePayment(NewMoney(110000), 1)
+
+	tests := []struct {
+		currentWeek int
+		expected    LoanStatus
+	}{
+		{12, Active},
+		{13, Delinquent},
+		{22, Delinquent},
+		{23, Defaulted},
+	}
+	for _, tt := range tests {
+		loan.SetCurrentWeek(tt.currentWeek)
+		if status := loan.Status(); status != tt.expected {
+			t.Errorf("Week %d: expected status %s, got %s", tt.currentWeek, tt.expected, status)
+		}
+	}
+}
+
+// Helper to find last paid week
+func findLastPaidWeek(loan *Loan) int {
+	lastPaid := 0
+	for _, entry := range loan.Schedule {
+		if entry.IsPaid && entry.WeekNumber > lastPaid {
+			lastPaid = entry.WeekNumber
+		}
+	}
+	return lastPaid
+}
+
+func TestArchive(t *testing.T) {
+	mutations := []struct {
+		name   string
+		mutate func(l *Loan) error
+	}{
+		{"MakePayment", func(l *Loan) error { return l.MakePayment(NewMoney(110000), 2) }},
+		{"MakeNextPayment", func(l *Loan) error { _, err := l.MakeNextPayment(NewMoney(110000)); return err }},
+		{"MakePartialPayment", func(l *Loan) error { return l.MakePartialPayment(NewMoney(10000), 2) }},
+		{"PayOff", func(l *Loan) error { return l.PayOff(NewMoney(5390000)) }},
+		{"PrepayPrincipal", func(l *Loan) error { return l.PrepayPrincipal(NewMoney(110000), ReduceTerm) }},
+		{"ReversePayment", func(l *Loan) error { return l.ReversePayment(1) }},
+	}
+
+	for _, tt := range mutations {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			loan.MakePayment(NewMoney(110000), 1)
+			loan.Archive()
+
+			if err := tt.mutate(loan); err != ErrLoanArchived {
+				t.Errorf("Expected ErrLoanArchived, got %v", err)
+			}
+			if len(loan.GetPaymentHistory()) != 1 || !loan.GetOutstanding().Equals(NewMoney(5390000)) {
+				t.Error("Expected archived loan to be unchanged")
+			}
+
+			loan.Unarchive()
+			if err := tt.mutate(loan); err != nil {
+				t.Errorf("Expected mutation to succeed after unarchive, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIsAutoDebitEligible(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(l *Loan)
+		expected bool
+	}{
+		{"active loan", func(l *Loan) {}, true},
+		{"one week behind", func(l *Loan) { l.MakePayment(NewMoney(110000), 1); l.SetCurrentWeek(2) }, true},
+		{"closed", func(l *Loan) { l.PayOff(NewMoney(5500000)) }, false},
+		{"delinquent", func(l *Loan) { l.SetCurrentWeek(3) }, false},
+		{"suspended", func(l *Loan) { l.Suspend() }, false},
+		{"resumed", func(l *Loan) { l.Suspend(); l.Resume() }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			tt.setup(loan)
+
+			if eligible := loan.IsAutoDebitEligible(); eligible != tt.expected {
+				t.Errorf("Expected eligible=%v, got %v", tt.expected, eligible)
+			}
+		})
+	}
+}
+
+func TestMakePayment_Success(t *testing.T) {
+	loan := createTestLoan()
+
+	// Make first payment
+	err := loan.MakePayment(NewMoney(110000), 1)
+	if err != nil {
+		t.Errorf("Expected successful payment, got error: %v", err)
+	}
+
+	// Verify payment was recorded
+	if len(loan.Payments) != 1 {
+		t.Errorf("Expected 1 payment recorded, got %d", len(loan.Payments))
+	}
+
+	// Verify schedule was updated
+	if !loan.Schedule[0].IsPaid {
+		t.Error("Expected week 1 to be marked as paid")
+	}
+
+	// Make second payment
+	err = loan.MakePayment(NewMoney(110000), 2)
+	if err != nil {
+		t.Errorf("Expected successful payment, got error: %v", err)
+	}
+
+	if len(loan.Payments) != 2 {
+		t.Errorf("Expected 2 payments recorded, got %d", len(loan.Payments))
+	}
+}
+
+func TestOutstandingBreakdown(t *testing.T) {
+	tests := []struct {
+		name              string
+		paidWeeks         int
+		expectedPrincipal int64
+		expectedInterest  int64
+	}{
+		{"Fresh loan", 0, 5000000, 500000},
+		{"Half paid", 25, 2500000, 250000},
+		{"One week paid", 1, 4900000, 490000},
+		{"Fully paid", 50, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			for week := 1; week <= tt.paidWeeks; week++ {
+				loan.MakePayment(NewMoney(110000), week)
+			}
+
+			principal, interest := loan.OutstandingBreakdown()
+			if !principal.Equals(NewMoney(tt.expectedPrincipal)) || !interest.Equals(NewMoney(tt.expectedInterest)) {
+				t.Errorf("Expected %d principal + %d interest, got %s + %s", tt.expectedPrincipal, tt.expectedInterest, principal, interest)
+			}
+			if !principal.Add(interest).Equals(loan.GetOutstanding()) {
+				t.Errorf("Breakdown %s + %s doesn't sum to outstanding %s", principal, interest, loan.GetOutstanding())
+			}
+		})
+	}
+}
+
+func TestPaidToDate(t *testing.T) {
+	loan := createTestLoan()
+
+	// Each installment of 110000 is 100000 principal and 10000 interest
+	steps := []struct {
+		name      string
+		pay       func() error
+		principal int64
+		interest  int64
+	}{
+		{"Nothing paid", func() error { return nil }, 0, 0},
+		{"Week 1", func() error { return loan.MakePayment(NewMoney(110000), 1) }, 100000, 10000},
+		{"Week 2", func() error { return loan.MakePayment(NewMoney(110000), 2) }, 200000, 20000},
+		{"Week 3 partly, interest first", func() error { return loan.MakePartialPayment(NewMoney(5000), 3) }, 200000, 25000},
+		{"Week 3 partly, past its interest", func() error { return loan.MakePartialPayment(NewMoney(30000), 3) }, 225000, 30000},
+		{"Prepayment covers week 50's interest", func() error { return loan.PrepayPrincipal(NewMoney(110000), ReduceTerm) }, 325000, 40000},
+	}
+
+	for _, step := range steps {
+		if err := step.pay(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		principal, interest := loan.PrincipalPaidToDate(), loan.InterestPaidToDate()
+		if !principal.Equals(NewMoney(step.principal)) || !interest.Equals(NewMoney(step.interest)) {
+			t.Errorf("%s: expected %d principal and %d interest, got %s and %s", step.name, step.principal, step.interest, principal, interest)
+		}
+		if !principal.Add(interest).Equals(loan.TotalPaid()) {
+			t.Errorf("%s: expected principal and interest to sum to %s", step.name, loan.TotalPaid())
+		}
+	}
+}
+
+func TestPaidToDate_Prepayments(t *testing.T) {
+	for _, mode := range []PrepayMode{ReduceTerm, ReducePayment} {
+		t.Run(mode.String(), func(t *testing.T) {
+			loan := createTestLoan()
+			loan.MakePayment(NewMoney(110000), 1)
+			if err := loan.PrepayPrincipal(NewMoney(550000), mode); err != nil {
+				t.Fatalf("PrepayPrincipal failed: %v", err)
+			}
+
+			check := func(when string) {
+				principal, interest := loan.PrincipalPaidToDate(), loan.InterestPaidToDate()
+				if principal.GreaterThan(loan.Principal) {
+					t.Errorf("%s: principal paid %s exceeds the principal %s", when, principal, loan.Principal)
+				}
+				if !principal.Add(interest).Equals(loan.TotalPaid()) {
+					t.Errorf("%s: expected %s and %s to sum to %s", when, principal, interest, loan.TotalPaid())
+				}
+			}
+			check("after the prepayment")
+
+			for _, entry := range loan.GetSchedule() {
+				if !entry.IsPaid {
+					if err := loan.MakePayment(entry.Remaining(), entry.WeekNumber); err != nil {
+						t.Fatalf("Paying week %d failed: %v", entry.WeekNumber, err)
+					}
+				}
+			}
+			if loan.Status() != PaidOff {
+				t.Fatalf("Expected the loan to be paid off, got %s", loan.Status())
+			}
+			check("once closed")
+
+			if !loan.PrincipalPaidToDate().Equals(loan.Principal) || !loan.InterestPaidToDate().Equals(loan.TotalInterestCost()) {
+				t.Errorf("Expected %s principal and %s interest once closed, got %s and %s",
+					loan.Principal, loan.TotalInterestCost(), loan.PrincipalPaidToDate(), loan.InterestPaidToDate())
+			}
+		})
+	}
+}
+
+func TestOutstandingBreakdown_Rounding(t *testing.T) {
+	terms := DefaultTerms()
+	terms.AnnualInterestRate = decimal.NewFromFloat(0.07)
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoney(1000003), terms)
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+
+	for week := 1; week <= 7; week++ {
+		loan.MakePayment(loan.Schedule[week-1].Amount, week)
+
+		principal, interest := loan.OutstandingBreakdown()
+		if !principal.Add(interest).Equals(loan.GetOutstanding()) {
+			t.Errorf("Week %d: breakdown %s + %s doesn't sum to outstanding %s", week, principal, interest, loan.GetOutstanding())
+		}
+		if !interest.Amount().Equal(interest.Amount().Round(0)) {
+			t.Errorf("Week %d: expected whole interest units, got %s", week, interest)
+		}
+	}
+}
+
+func TestValidatePayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*Loan)
+		amount   int64
+		week     int
+		expected error
+	}{
+		{"Valid", func(l *Loan) {}, 110000, 1, nil},
+		{"Negative amount", func(l *Loan) {}, -110000, 1, ErrNegativeAmount},
+		{"Wrong amount", func(l *Loan) {}, 100000, 1, ErrInvalidPaymentAmount},
+		{"Invalid week", func(l *Loan) {}, 110000, 51, ErrInvalidWeekNumber},
+		{"Already paid", func(l *Loan) { l.MakePayment(NewMoney(110000), 1) }, 110000, 1, ErrWeekAlreadyPaid},
+		{"Out of sequence", func(l *Loan) {}, 110000, 2, ErrPaymentOutOfSequence},
+		{"Fully paid", func(l *Loan) { l.PayOff(NewMoney(5500000)) }, 110000, 50, ErrLoanFullyPaid},
+		{"Archived", func(l *Loan) { l.Archive() }, 110000, 1, ErrLoanArchived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			tt.setup(loan)
+			payments := len(loan.Payments)
+
+			if err := loan.ValidatePayment(NewMoney(tt.amount), tt.week); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if len(loan.Payments) != payments {
+				t.Errorf("Expected ValidatePayment not to record a payment")
+			}
+
+			// MakePayment reaches the same verdict
+			if err := loan.MakePayment(NewMoney(tt.amount), tt.week); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected MakePayment to return %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMakePaymentWithDetails(t *testing.T) {
+	loan := createTestLoan()
+
+	if err := loan.MakePaymentWithDetails(NewMoney(110000), 1, "bank_transfer", "TRX-0001"); err != nil {
+		t.Fatalf("Expected successful payment, got error: %v", err)
+	}
+	if err := loan.MakePayment(NewMoney(110000), 2); err != nil {
+		t.Fatalf("Expected successful payment, got error: %v", err)
+	}
+
+	history := loan.GetPaymentHistory()
+	if history[0].Method != "bank_transfer" || history[0].Reference != "TRX-0001" {
+		t.Errorf("Expected method and reference preserved, got %q/%q", history[0].Method, history[0].Reference)
+	}
+	if history[1].Method != "" || history[1].Reference != "" {
+		t.Errorf("Expected MakePayment to leave details empty, got %q/%q", history[1].Method, history[1].Reference)
+	}
+
+	// Validation matches MakePayment
+	if err := loan.MakePaymentWithDetails(NewMoney(100000), 3, "cash", "R-3"); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+	}
+}
+
+func TestMakePaymentWithKey(t *testing.T) {
+	loan := createTestLoan()
+
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 1, "req-1"); err != nil {
+		t.Fatalf("Expected successful payment, got error: %v", err)
+	}
+
+	// Retrying the same key is a no-op that reports the original success
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 1, "req-1"); err != nil {
+		t.Errorf("Expected retry to return nil, got %v", err)
+	}
+	if len(loan.Payments) != 1 {
+		t.Errorf("Expected 1 payment after retry, got %d", len(loan.Payments))
+	}
+
+	// A failed attempt keeps returning its error, even once it would succeed
+	if err := loan.MakePaymentWithKey(NewMoney(100000), 2, "req-2"); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Fatalf("Expected ErrInvalidPaymentAmount, got %v", err)
+	}
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 2, "req-2"); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected retry to return the original error, got %v", err)
+	}
+	if len(loan.Payments) != 1 {
+		t.Errorf("Expected 1 payment after failed retries, got %d", len(loan.Payments))
+	}
+
+	// Without a key every call is applied
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 2, ""); err != nil {
+		t.Errorf("Expected successful payment, got error: %v", err)
+	}
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 2, ""); !errors.Is(err, ErrWeekAlreadyPaid) {
+		t.Errorf("Expected ErrWeekAlreadyPaid, got %v", err)
+	}
+}
+
+func TestMakePaymentWithKey_Bounded(t *testing.T) {
+	loan := createTestLoan()
+
+	loan.MakePaymentWithKey(NewMoney(1), 1, "oldest")
+	for i := range MaxIdempotencyKeys - 1 {
+		loan.MakePaymentWithKey(NewMoney(1), 1, fmt.Sprintf("req-%d", i))
+	}
+	if len(loan.idempotency.results) != MaxIdempotencyKeys {
+		t.Fatalf("Expected %d keys remembered, got %d", MaxIdempotencyKeys, len(loan.idempotency.results))
+	}
+
+	// One more key evicts the oldest, so reusing it is applied again
+	loan.MakePaymentWithKey(NewMoney(1), 1, "newest")
+	if len(loan.idempotency.results) != MaxIdempotencyKeys {
+		t.Errorf("Expected key count to stay at %d, got %d", MaxIdempotencyKeys, len(loan.idempotency.results))
+	}
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 1, "oldest"); err != nil {
+		t.Errorf("Expected evicted key to be applied, got %v", err)
+	}
+	if err := loan.MakePaymentWithKey(NewMoney(110000), 2, "newest"); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected remembered key to return its original error, got %v", err)
+	}
+}
+
+func TestMakePayment_InvalidAmount(t *testing.T) {
+	loan := createTestLoan()
+
+	// Try to pay wrong amount
+	err := loan.MakePayment(NewMoney(100000), 1)
+	if !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+	}
+
+	// Try to pay more than required
+	err = loan.MakePayment(NewMoney(120000), 1)
+	if !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
 	}
 
 	// Try negative amount
 	err = loan.MakePayment(NewMoney(-110000), 1)
-	if err != ErrNegativeAmount {
+	if !errors.Is(err, ErrNegativeAmount) {
 		t.Errorf("Expected ErrNegativeAmount, got %v", err)
 	}
 }
 
-func TestMakePayment_InvalidWeekNumber(t *testing.T) {
-	loan := createTestLoan()
+func TestPaymentError(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+
+	err := loan.MakePayment(NewMoney(100000), 3)
+	if !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Fatalf("Expected ErrInvalidPaymentAmount, got %v", err)
+	}
+
+	var paymentErr *PaymentError
+	if !errors.As(err, &paymentErr) {
+		t.Fatalf("Expected a *PaymentError, got %T", err)
+	}
+	if paymentErr.Code != ErrInvalidPaymentAmount || paymentErr.Week != 3 ||
+		!paymentErr.ExpectedAmount.Equals(NewMoney(110000)) || !paymentErr.ProvidedAmount.Equals(NewMoney(100000)) {
+		t.Errorf("Unexpected payment error fields %+v", paymentErr)
+	}
+	if got := err.Error(); got != "week 3 expects IDR 110000, got IDR 100000" {
+		t.Errorf("Unexpected message %q", got)
+	}
+
+	tests := []struct {
+		week     int
+		expected error
+	}{
+		{0, ErrInvalidWeekNumber},
+		{2, ErrWeekAlreadyPaid},
+		{4, ErrPaymentOutOfSequence},
+	}
+	for _, tt := range tests {
+		err := loan.MakePayment(NewMoney(110000), tt.week)
+		if !errors.As(err, &paymentErr) || paymentErr.Code != tt.expected || paymentErr.Week != tt.week {
+			t.Errorf("Week %d: expected a PaymentError for %v, got %v", tt.week, tt.expected, err)
+		}
+	}
+}
+
+func TestMakePayment_InvalidWeekNumber(t *testing.T) {
+	loan := createTestLoan()
+
+	// Try week 0
+	err := loan.MakePayment(NewMoney(110000), 0)
+	if !errors.Is(err, ErrInvalidWeekNumber) {
+		t.Errorf("Expected ErrInvalidWeekNumber for week 0, got %v", err)
+	}
+
+	// Try week 51
+	err = loan.MakePayment(NewMoney(110000), 51)
+	if !errors.Is(err, ErrInvalidWeekNumber) {
+		t.Errorf("Expected ErrInvalidWeekNumber for week 51, got %v", err)
+	}
+}
+
+func TestMakePayment_AlreadyPaid(t *testing.T) {
+	loan := createTestLoan()
+
+	// Pay week 1
+	loan.MakePayment(NewMoney(110000), 1)
+
+	// Try to pay week 1 again
+	err := loan.MakePayment(NewMoney(110000), 1)
+	if !errors.Is(err, ErrWeekAlreadyPaid) {
+		t.Errorf("Expected ErrWeekAlreadyPaid, got %v", err)
+	}
+}
+
+func TestMakePayment_OutOfSequence(t *testing.T) {
+	loan := createTestLoan()
+
+	// Try to pay week 2 before week 1
+	err := loan.MakePayment(NewMoney(110000), 2)
+	if !errors.Is(err, ErrPaymentOutOfSequence) {
+		t.Errorf("Expected ErrPaymentOutOfSequence, got %v", err)
+	}
+
+	// Pay week 1
+	loan.MakePayment(NewMoney(110000), 1)
+
+	// Try to skip week 2 and pay week 3
+	err = loan.MakePayment(NewMoney(110000), 3)
+	if !errors.Is(err, ErrPaymentOutOfSequence) {
+		t.Errorf("Expected ErrPaymentOutOfSequence, got %v", err)
+	}
+}
+
+func TestMakeNextPayment_OldestFirst(t *testing.T) {
+	t.Run("Explicit by default", func(t *testing.T) {
+		loan := createTestLoan()
+
+		if _, err := loan.MakeNextPayment(NewMoney(275000)); !errors.Is(err, ErrInvalidPaymentAmount) {
+			t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+		}
+	})
+
+	terms := DefaultTerms()
+	terms.PaymentStrategy = OldestFirst
+	loan := createTestLoanWithTerms(terms)
+
+	// 2.5 weeks: weeks 1 and 2 in full, half of week 3
+	week, err := loan.MakeNextPayment(NewMoney(275000))
+	if err != nil || week != 1 {
+		t.Fatalf("Expected payment to start at week 1, got week %d: %v", week, err)
+	}
+	schedule := loan.GetSchedule()
+	if !schedule[0].IsPaid || !schedule[1].IsPaid || schedule[2].IsPaid {
+		t.Errorf("Expected weeks 1 and 2 paid and week 3 open")
+	}
+	if !schedule[2].PaidAmount.Equals(NewMoney(55000)) {
+		t.Errorf("Expected IDR 55000 towards week 3, got %s", schedule[2].PaidAmount)
+	}
+	if history := loan.GetPaymentHistory(); len(history) != 3 || history[2].WeekNumber != 3 {
+		t.Errorf("Expected one payment per week touched, got %+v", history)
+	}
+
+	// The next payment finishes week 3 first
+	if week, err := loan.MakeNextPayment(NewMoney(165000)); err != nil || week != 3 {
+		t.Fatalf("Expected payment to start at week 3, got week %d: %v", week, err)
+	}
+	if next := loan.GetNextDueWeek(); next != 5 {
+		t.Errorf("Expected week 5 to be next due, got %d", next)
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5060000)) {
+		t.Errorf("Expected outstanding IDR 5060000, got %s", loan.GetOutstanding())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent, got %v", err)
+	}
+
+	if _, err := loan.MakeNextPayment(NewMoney(5060001)); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount above the outstanding, got %v", err)
+	}
+	if _, err := loan.MakeNextPayment(NewMoney(5060000)); err != nil || !loan.IsClosed() {
+		t.Errorf("Expected the outstanding to close the loan, got %v", err)
+	}
+}
+
+func TestAcceptAtLeastWeekly(t *testing.T) {
+	terms := DefaultTerms()
+	terms.AcceptAtLeastWeekly = true
+	loan := createTestLoanWithTerms(terms)
+
+	// Exactly the minimum
+	if err := loan.MakePayment(NewMoney(110000), 1); err != nil {
+		t.Fatalf("Expected the weekly amount to be accepted, got %v", err)
+	}
+
+	// Above the minimum: under Reject the excess goes to the following weeks
+	if err := loan.MakePayment(NewMoney(250000), 2); err != nil {
+		t.Fatalf("Expected more than the weekly amount to be accepted, got %v", err)
+	}
+	schedule := loan.GetSchedule()
+	if !schedule[1].IsPaid || !schedule[2].IsPaid || !schedule[3].PaidAmount.Equals(NewMoney(30000)) {
+		t.Errorf("Expected weeks 2 and 3 paid and IDR 30000 towards week 4, got %+v", schedule[3])
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5140000)) {
+		t.Errorf("Expected outstanding IDR 5140000, got %s", loan.GetOutstanding())
+	}
+
+	// Below the minimum
+	var paymentErr *PaymentError
+	if err := loan.MakePayment(NewMoney(50000), 4); !errors.As(err, &paymentErr) || !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount below the minimum, got %v", err)
+	} else if !paymentErr.ExpectedAmount.Equals(NewMoney(80000)) {
+		t.Errorf("Expected IDR 80000 due for week 4, got %s", paymentErr.ExpectedAmount)
+	}
+
+	if err := loan.MakePayment(loan.GetOutstanding().Add(NewMoney(1)), 4); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount above the outstanding, got %v", err)
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent, got %v", err)
+	}
+
+	t.Run("HoldAsCredit", func(t *testing.T) {
+		terms := terms
+		terms.OverpaymentPolicy = HoldAsCredit
+		loan := createTestLoanWithTerms(terms)
+
+		if err := loan.MakePayment(NewMoney(150000), 1); err != nil {
+			t.Fatalf("Expected overpayment to be accepted, got %v", err)
+		}
+		if !loan.CreditBalance().Equals(NewMoney(40000)) {
+			t.Errorf("Expected the excess held as credit, got %s", loan.CreditBalance())
+		}
+	})
+}
+
+func TestOverpaymentPolicy(t *testing.T) {
+	newLoan := func(policy OverpaymentPolicy) *Loan {
+		terms := DefaultTerms()
+		terms.OverpaymentPolicy = policy
+		return createTestLoanWithTerms(terms)
+	}
+
+	t.Run("Reject", func(t *testing.T) {
+		loan := newLoan(Reject)
+
+		if err := loan.MakePayment(NewMoney(150000), 1); !errors.Is(err, ErrInvalidPaymentAmount) {
+			t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+		}
+		if !loan.CreditBalance().IsZero() || len(loan.GetPaymentHistory()) != 0 {
+			t.Errorf("Expected nothing recorded")
+		}
+	})
+
+	t.Run("ApplyToFuture", func(t *testing.T) {
+		loan := newLoan(ApplyToFuture)
+
+		if err := loan.MakePayment(NewMoney(150000), 1); err != nil {
+			t.Fatalf("Expected overpayment to be accepted, got %v", err)
+		}
+		schedule := loan.GetSchedule()
+		if !schedule[0].IsPaid || !schedule[1].PaidAmount.Equals(NewMoney(40000)) {
+			t.Errorf("Expected week 1 paid and IDR 40000 towards week 2, got %s", schedule[1].PaidAmount)
+		}
+		if !loan.GetOutstanding().Equals(NewMoney(5350000)) || !loan.CreditBalance().IsZero() {
+			t.Errorf("Expected outstanding IDR 5350000 and no credit, got %s and %s", loan.GetOutstanding(), loan.CreditBalance())
+		}
+
+		// Week 2 now only needs the rest
+		if err := loan.MakePayment(NewMoney(70000), 2); err != nil {
+			t.Errorf("Expected the rest of week 2 to be accepted, got %v", err)
+		}
+		if err := loan.MakePayment(NewMoney(5280001), 3); !errors.Is(err, ErrInvalidPaymentAmount) {
+			t.Errorf("Expected ErrInvalidPaymentAmount above the outstanding, got %v", err)
+		}
+		if err := loan.Validate(); err != nil {
+			t.Errorf("Expected loan to stay consistent, got %v", err)
+		}
+	})
+
+	t.Run("HoldAsCredit", func(t *testing.T) {
+		loan := newLoan(HoldAsCredit)
+
+		if err := loan.MakePayment(NewMoney(150000), 1); err != nil {
+			t.Fatalf("Expected overpayment to be accepted, got %v", err)
+		}
+		if !loan.CreditBalance().Equals(NewMoney(40000)) {
+			t.Errorf("Expected credit IDR 40000, got %s", loan.CreditBalance())
+		}
+		if !loan.GetOutstanding().Equals(NewMoney(5390000)) {
+			t.Errorf("Expected the credit not to count towards the outstanding, got %s", loan.GetOutstanding())
+		}
+
+		// The credit applies first, so week 2 expects IDR 70000
+		var paymentErr *PaymentError
+		if err := loan.MakePayment(NewMoney(110000), 2); err != nil {
+			t.Fatalf("Expected an overpayment on week 2 to be accepted too, got %v", err)
+		}
+		if !loan.CreditBalance().Equals(NewMoney(40000)) {
+			t.Errorf("Expected the new excess to be held, got %s", loan.CreditBalance())
+		}
+		if err := loan.MakePayment(NewMoney(60000), 3); !errors.As(err, &paymentErr) || !paymentErr.ExpectedAmount.Equals(NewMoney(70000)) {
+			t.Errorf("Expected week 3 to expect IDR 70000, got %v", err)
+		}
+		if err := loan.MakePayment(NewMoney(70000), 3); err != nil {
+			t.Fatalf("Expected week 3 to be paid with the credit, got %v", err)
+		}
+		if !loan.CreditBalance().IsZero() {
+			t.Errorf("Expected the credit to be used up, got %s", loan.CreditBalance())
+		}
+
+		history := loan.GetPaymentHistory()
+		if last := history[len(history)-2]; last.Method != CreditPaymentMethod || !last.Amount.Equals(NewMoney(40000)) {
+			t.Errorf("Expected the credit to be recorded as a payment, got %+v", last)
+		}
+
+		// A credit covering a whole week leaves nothing to pay
+		loan.MakePayment(NewMoney(300000), 4)
+		if err := loan.MakePayment(NewMoney(0), 5); err != nil {
+			t.Errorf("Expected week 5 to be covered by the credit, got %v", err)
+		}
+		if !loan.CreditBalance().Equals(NewMoney(80000)) {
+			t.Errorf("Expected IDR 80000 credit left, got %s", loan.CreditBalance())
+		}
+		if err := loan.Validate(); err != nil {
+			t.Errorf("Expected loan to stay consistent, got %v", err)
+		}
+	})
+}
+
+func TestMakePayment_NonSequential(t *testing.T) {
+	t.Run("Default terms", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.MakePayment(NewMoney(110000), 2)
+
+		if err := loan.MakePayment(NewMoney(110000), 5); !errors.Is(err, ErrPaymentOutOfSequence) {
+			t.Errorf("Expected ErrPaymentOutOfSequence, got %v", err)
+		}
+	})
+
+	terms := DefaultTerms()
+	terms.AllowNonSequential = true
+	loan := createTestLoanWithTerms(terms)
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+
+	if err := loan.MakePayment(NewMoney(110000), 5); err != nil {
+		t.Fatalf("Expected week 5 to be payable before week 3, got %v", err)
+	}
+	if err := loan.MakePayment(NewMoney(110000), 5); !errors.Is(err, ErrWeekAlreadyPaid) {
+		t.Errorf("Expected ErrWeekAlreadyPaid, got %v", err)
+	}
+	if next := loan.GetNextDueWeek(); next != 3 {
+		t.Errorf("Expected week 3 to be next due, got %d", next)
+	}
+
+	// Weeks 3 and 4 are unpaid; paying week 5 early doesn't hide them
+	loan.SetCurrentWeek(5)
+	if behind := loan.WeeksBehind(); behind != 2 {
+		t.Errorf("Expected 2 weeks behind, got %d", behind)
+	}
+	if !loan.IsDelinquent() {
+		t.Error("Expected loan to be delinquent with weeks 3 and 4 unpaid")
+	}
+
+	loan.MakePayment(NewMoney(110000), 3)
+	if loan.IsDelinquent() {
+		t.Error("Expected loan not to be delinquent with only week 4 unpaid")
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent, got %v", err)
+	}
+	if err := loan.ReversePayment(3); err != nil {
+		t.Errorf("Expected week 3 to be reversible while week 5 is paid, got %v", err)
+	}
+
+	// Prepayments leave the weeks paid ahead alone
+	if err := loan.PrepayPrincipal(NewMoney(450000), ReducePayment); err != nil {
+		t.Fatalf("Expected prepayment to succeed, got %v", err)
+	}
+	if schedule := loan.GetSchedule(); !schedule[4].IsPaid || !schedule[4].Amount.Equals(NewMoney(110000)) {
+		t.Errorf("Expected week 5 to stay paid at IDR 110000, got %+v", schedule[4])
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent after prepaying, got %v", err)
+	}
+}
+
+func TestMakePayment_FullyPaid(t *testing.T) {
+	loan := createTestLoan()
+
+	// Pay all 50 weeks
+	for i := 1; i <= 50; i++ {
+		err := loan.MakePayment(NewMoney(110000), i)
+		if err != nil {
+			t.Fatalf("Failed to make payment for week %d: %v", i, err)
+		}
+	}
+
+	// Verify loan is closed
+	if !loan.IsClosed() {
+		t.Error("Expected loan to be closed after all payments")
+	}
+
+	// Verify outstanding is zero
+	if !loan.GetOutstanding().IsZero() {
+		t.Errorf("Expected outstanding to be zero, got %s", loan.GetOutstanding())
+	}
+
+	// Try to make another payment
+	err := loan.MakePayment(NewMoney(110000), 1)
+	if err != ErrLoanFullyPaid {
+		t.Errorf("Expected ErrLoanFullyPaid, got %v", err)
+	}
+}
+
+func TestMakePayment_Concurrent(t *testing.T) {
+	loan := createTestLoan()
+
+	const attempts = 20
+	errs := make(chan error, attempts)
+
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- loan.MakePayment(NewMoney(110000), 1)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrWeekAlreadyPaid):
+		default:
+			t.Errorf("Expected nil or ErrWeekAlreadyPaid, got %v", err)
+		}
+	}
+
+	if succeeded != 1 {
+		t.Errorf("Expected exactly 1 successful payment, got %d", succeeded)
+	}
+	if payments := loan.GetPaymentHistory(); len(payments) != 1 {
+		t.Errorf("Expected 1 payment recorded, got %d", len(payments))
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5390000)) {
+		t.Errorf("Expected outstanding IDR 5390000, got %s", loan.GetOutstanding())
+	}
+}
+
+func TestCostAccessors_ConcurrentWithRestructure(t *testing.T) {
+	loan := createTestLoan()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		loan.Restructure(60, decimal.NewFromFloat(0.08))
+	}()
+	go func() {
+		defer wg.Done()
+		loan.TotalInterestCost()
+		loan.TotalCostOfCredit()
+		loan.TotalRepayable()
+		loan.InstallmentAmount()
+	}()
+	wg.Wait()
+
+	if !loan.TotalInterestCost().Equals(loan.TotalAmount.Subtract(loan.Principal)) {
+		t.Errorf("Expected TotalInterestCost to follow the restructured TotalAmount, got %s", loan.TotalInterestCost())
+	}
+}
+
+func TestScheduleEntryPaidAt(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+	loan.MakePayment(NewMoney(110000), 1)
+	paidAt := clock.Now()
+
+	// Week 2 only partially paid a day later
+	clock.Advance(24 * time.Hour)
+	loan.MakePartialPayment(NewMoney(50000), 2)
+
+	schedule := loan.GetSchedule()
+	if schedule[0].PaidAt == nil || !schedule[0].PaidAt.Equal(paidAt) {
+		t.Errorf("Expected week 1 paid at %v, got %v", paidAt, schedule[0].PaidAt)
+	}
+	for _, entry := range schedule[1:] {
+		if entry.PaidAt != nil {
+			t.Fatalf("Expected no paid timestamp for unpaid week %d, got %v", entry.WeekNumber, entry.PaidAt)
+		}
+	}
+
+	// Completing the week stamps it with the final payment's time
+	clock.Advance(24 * time.Hour)
+	loan.MakePartialPayment(NewMoney(60000), 2)
+	if week2 := loan.GetSchedule()[1]; week2.PaidAt == nil || !week2.PaidAt.Equal(clock.Now()) {
+		t.Errorf("Expected week 2 paid at %v, got %v", clock.Now(), week2.PaidAt)
+	}
+
+	// The returned schedule is a copy
+	*schedule[0].PaidAt = time.Time{}
+	if !loan.GetSchedule()[0].PaidAt.Equal(paidAt) {
+		t.Error("Expected modifying the copy to leave the loan's schedule unchanged")
+	}
+}
+
+func TestDistinctPaymentAmounts(t *testing.T) {
+	loan := createTestLoan()
+	if amounts := loan.DistinctPaymentAmounts(); len(amounts) != 0 {
+		t.Errorf("Expected no amounts before any payment, got %v", amounts)
+	}
+
+	// Regular payments produce a single amount
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+	amounts := loan.DistinctPaymentAmounts()
+	if len(amounts) != 1 || !amounts[0].Equals(NewMoney(110000)) {
+		t.Errorf("Expected single amount IDR 110000, got %v", amounts)
+	}
+
+	// Revolving installments differ every week
+	revolving := createRevolvingTestLoan()
+	for week := 1; week <= 3; week++ {
+		revolving.MakePayment(revolving.Schedule[week-1].Amount, week)
+	}
+	revolving.Payments = append(revolving.Payments, Payment{WeekNumber: 3, Amount: revolving.Schedule[2].Amount})
+
+	amounts = revolving.DistinctPaymentAmounts()
+	if len(amounts) != 3 {
+		t.Fatalf("Expected 3 distinct amounts, got %v", amounts)
+	}
+	for i := 1; i < len(amounts); i++ {
+		if !amounts[i-1].LessThan(amounts[i]) {
+			t.Errorf("Expected amounts sorted ascending, got %v", amounts)
+		}
+	}
+}
+
+func TestMakePartialPayment(t *testing.T) {
+	loan := createTestLoan()
+
+	// Two partial payments settle week 1
+	if err := loan.MakePartialPayment(NewMoney(60000), 1); err != nil {
+		t.Fatalf("Expected partial payment to succeed, got %v", err)
+	}
+	if loan.Schedule[0].IsPaid {
+		t.Error("Expected week 1 to remain unpaid after a partial payment")
+	}
+	if !loan.Schedule[0].Remaining().Equals(NewMoney(50000)) {
+		t.Errorf("Expected IDR 50000 remaining for week 1, got %s", loan.Schedule[0].Remaining())
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5440000)) {
+		t.Errorf("Expected outstanding IDR 5440000, got %s", loan.GetOutstanding())
+	}
+	if loan.GetNextDueWeek() != 1 {
+		t.Errorf("Expected week 1 to still be due, got %d", loan.GetNextDueWeek())
+	}
+
+	if err := loan.MakePartialPayment(NewMoney(50000), 1); err != nil {
+		t.Fatalf("Expected partial payment to succeed, got %v", err)
+	}
+	if !loan.Schedule[0].IsPaid {
+		t.Error("Expected week 1 to be paid once partial payments reach the weekly amount")
+	}
+	if len(loan.Payments) != 2 {
+		t.Errorf("Expected 2 payments recorded, got %d", len(loan.Payments))
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5390000)) {
+		t.Errorf("Expected outstanding IDR 5390000, got %s", loan.GetOutstanding())
+	}
+
+	// MakePayment settles whatever is left of a partially paid week
+	loan.MakePartialPayment(NewMoney(10000), 2)
+	if err := loan.MakePayment(NewMoney(110000), 2); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount for the full weekly amount, got %v", err)
+	}
+	if err := loan.MakePayment(NewMoney(100000), 2); err != nil {
+		t.Errorf("Expected remaining amount to settle week 2, got %v", err)
+	}
+}
+
+func TestMakePartialPayment_Validation(t *testing.T) {
+	loan := createTestLoan()
+
+	tests := []struct {
+		name     string
+		amount   Money
+		week     int
+		expected error
+	}{
+		{"more than weekly amount", NewMoney(110001), 1, ErrInvalidPaymentAmount},
+		{"zero amount", NewMoney(0), 1, ErrInvalidPaymentAmount},
+		{"negative amount", NewMoney(-1000), 1, ErrNegativeAmount},
+		{"invalid week", NewMoney(1000), 51, ErrInvalidWeekNumber},
+		{"out of sequence", NewMoney(1000), 2, ErrPaymentOutOfSequence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := loan.MakePartialPayment(tt.amount, tt.week); err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// A partial payment can't exceed what is left for the week
+	loan.MakePartialPayment(NewMoney(100000), 1)
+	if err := loan.MakePartialPayment(NewMoney(20000), 1); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount when exceeding the remaining amount, got %v", err)
+	}
+}
+
+func TestPayOff(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePartialPayment(NewMoney(30000), 2)
+
+	// Must match the outstanding exactly
+	if err := loan.PayOff(NewMoney(5000000)); err != ErrInvalidPayoffAmount {
+		t.Errorf("Expected ErrInvalidPayoffAmount, got %v", err)
+	}
+	if err := loan.PayOff(NewMoney(-5360000)); !errors.Is(err, ErrNegativeAmount) {
+		t.Errorf("Expected ErrNegativeAmount, got %v", err)
+	}
+
+	// 5,500,000 - 110,000 - 30,000
+	if err := loan.PayOff(NewMoney(5360000)); err != nil {
+		t.Fatalf("Expected payoff to succeed, got %v", err)
+	}
+
+	if !loan.IsClosed() {
+		t.Error("Expected loan to be closed after payoff")
+	}
+	if loan.GetNextDueWeek() != 0 {
+		t.Errorf("Expected no next due week after payoff, got %d", loan.GetNextDueWeek())
+	}
+	for _, entry := range loan.Schedule {
+		if !entry.IsPaid {
+			t.Errorf("Expected week %d to be paid", entry.WeekNumber)
+		}
+	}
+
+	// One payment per remaining week, plus the two made before
+	if len(loan.Payments) != 2+49 {
+		t.Errorf("Expected 51 payments, got %d", len(loan.Payments))
+	}
+	if !loan.Payments[2].Amount.Equals(NewMoney(80000)) {
+		t.Errorf("Expected the rest of week 2 (IDR 80000) to be paid, got %s", loan.Payments[2].Amount)
+	}
+
+	if err := loan.PayOff(NewMoney(0)); err != ErrLoanFullyPaid {
+		t.Errorf("Expected ErrLoanFullyPaid, got %v", err)
+	}
+}
+
+func TestPrepayPrincipal_ReduceTerm(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+
+	// Three installments worth, taken from the end of the schedule
+	if err := loan.PrepayPrincipal(NewMoney(330000), ReduceTerm); err != nil {
+		t.Fatalf("Expected prepayment to succeed, got %v", err)
+	}
+
+	if loan.DurationWeeks != 47 || len(loan.GetSchedule()) != 47 {
+		t.Errorf("Expected term of 47 weeks, got %d (%d entries)", loan.DurationWeeks, len(loan.GetSchedule()))
+	}
+	if !loan.GetSchedule()[1].Amount.Equals(NewMoney(110000)) {
+		t.Errorf("Expected installment to stay IDR 110000, got %s", loan.GetSchedule()[1].Amount)
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5060000)) {
+		t.Errorf("Expected outstanding IDR 5060000, got %s", loan.GetOutstanding())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent, got %v", err)
+	}
+
+	// Paying the shortened schedule closes the loan
+	for week := 2; week <= 47; week++ {
+		if err := loan.MakePayment(NewMoney(110000), week); err != nil {
+			t.Fatalf("Failed to pay week %d: %v", week, err)
+		}
+	}
+	if !loan.IsClosed() {
+		t.Error("Expected loan to be closed after paying the shortened term")
+	}
+}
+
+func TestPrepayPrincipal_ReducePayment(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+
+	// 49 weeks left at IDR 110000; prepaying 49 * 10000 lowers each to 100000
+	if err := loan.PrepayPrincipal(NewMoney(490000), ReducePayment); err != nil {
+		t.Fatalf("Expected prepayment to succeed, got %v", err)
+	}
+
+	if loan.DurationWeeks != 50 {
+		t.Errorf("Expected term to stay 50 weeks, got %d", loan.DurationWeeks)
+	}
+	for _, entry := range loan.GetSchedule()[1:] {
+		if !entry.Amount.Equals(NewMoney(100000)) {
+			t.Fatalf("Expected week %d installment IDR 100000, got %s", entry.WeekNumber, entry.Amount)
+		}
+		// Interest stays as scheduled, the prepayment only reduces principal
+		if !entry.InterestPortion.Equals(NewMoney(10000)) || !entry.PrincipalPortion.Equals(NewMoney(90000)) {
+			t.Fatalf("Expected week %d portions IDR 90000 + 10000, got %s + %s", entry.WeekNumber, entry.PrincipalPortion, entry.InterestPortion)
+		}
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(4900000)) {
+		t.Errorf("Expected outstanding IDR 4900000, got %s", loan.GetOutstanding())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent, got %v", err)
+	}
+
+	// The last week absorbs what doesn't split evenly
+	if err := loan.PrepayPrincipal(NewMoney(1), ReducePayment); err != nil {
+		t.Fatalf("Expected prepayment to succeed, got %v", err)
+	}
+	schedule := loan.GetSchedule()
+	if !schedule[1].Amount.Equals(NewMoney(99999)) || !schedule[49].Amount.Equals(NewMoney(100047)) {
+		t.Errorf("Expected IDR 99999 per week and IDR 100047 last, got %s and %s", schedule[1].Amount, schedule[49].Amount)
+	}
+}
+
+func TestRestructure(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+	for week := 1; week <= 3; week++ {
+		loan.MakePayment(NewMoney(110000), week)
+	}
+	loan.MakePartialPayment(NewMoney(50000), 4)
+	clock.Advance(7 * weekDuration)
+	loan.SyncCurrentWeek()
+	if !loan.IsDelinquent() {
+		t.Fatal("Expected loan to be delinquent before restructuring")
+	}
+
+	// IDR 5,120,000 outstanding over 20 weeks at 5% flat
+	if err := loan.Restructure(20, decimal.NewFromFloat(0.05)); err != nil {
+		t.Fatalf("Expected restructure to succeed, got %v", err)
+	}
+
+	schedule := loan.GetSchedule()
+	newTotal := NewMoney(0)
+	for _, entry := range schedule[4:] {
+		newTotal = newTotal.Add(entry.Amount)
+	}
+	if !newTotal.Equals(NewMoney(5376000)) || !loan.GetOutstanding().Equals(newTotal) {
+		t.Errorf("Expected new schedule and outstanding IDR 5376000, got %s and %s", newTotal, loan.GetOutstanding())
+	}
+	if loan.DurationWeeks != 24 || len(schedule) != 24 || schedule[4].WeekNumber != 5 {
+		t.Errorf("Expected weeks 5 to 24 to be restructured, got duration %d", loan.DurationWeeks)
+	}
+
+	// The partly paid week is settled at what was paid
+	if week4 := schedule[3]; !week4.IsPaid || !week4.Amount.Equals(NewMoney(50000)) {
+		t.Errorf("Expected week 4 settled at IDR 50000, got %+v", week4)
+	}
+	if len(loan.GetPaymentHistory()) != 4 {
+		t.Errorf("Expected payment history to be kept, got %d payments", len(loan.GetPaymentHistory()))
+	}
+
+	// Delinquency restarts from the first restructured week
+	if loan.CurrentWeek != 5 || loan.IsDelinquent() || loan.IsDelinquentByDate() {
+		t.Errorf("Expected a fresh baseline at week 5, got week %d behind %d", loan.CurrentWeek, loan.WeeksBehind())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected loan to stay consistent, got %v", err)
+	}
+
+	for week := 5; week <= 24; week++ {
+		amount, _ := loan.AmountDueForWeek(week)
+		if err := loan.MakePayment(amount, week); err != nil {
+			t.Fatalf("Failed to pay week %d: %v", week, err)
+		}
+	}
+	if !loan.IsClosed() {
+		t.Errorf("Expected loan to close, %s outstanding", loan.GetOutstanding())
+	}
+	if err := loan.Restructure(20, decimal.NewFromFloat(0.05)); err != ErrLoanFullyPaid {
+		t.Errorf("Expected ErrLoanFullyPaid for a closed loan, got %v", err)
+	}
+}
+
+func TestRestructure_Validation(t *testing.T) {
+	loan := createTestLoan()
+
+	if err := loan.Restructure(0, decimal.NewFromFloat(0.05)); err != ErrInvalidDuration {
+		t.Errorf("Expected ErrInvalidDuration, got %v", err)
+	}
+	if err := loan.Restructure(20, decimal.NewFromFloat(-0.05)); err != ErrInvalidInterestRate {
+		t.Errorf("Expected ErrInvalidInterestRate, got %v", err)
+	}
+	if len(loan.GetSchedule()) != 50 {
+		t.Errorf("Expected rejected restructures to leave the schedule alone")
+	}
+}
+
+func TestRestructure_RateBounds(t *testing.T) {
+	terms := DefaultTerms()
+	terms.RateBounds = &RateBounds{Min: decimal.NewFromFloat(0.05), Max: decimal.NewFromFloat(0.5)}
+	loan := createTestLoanWithTerms(terms)
+
+	// Within the defaults but below the product's minimum
+	if err := loan.Restructure(20, decimal.NewFromFloat(0.01)); !errors.Is(err, ErrInterestRateOutOfRange) {
+		t.Errorf("Expected ErrInterestRateOutOfRange below the loan's bounds, got %v", err)
+	}
+	if err := loan.Restructure(20, decimal.NewFromFloat(0.3)); err != nil {
+		t.Errorf("Expected a rate within the loan's bounds to be accepted, got %v", err)
+	}
+
+	// Above the defaults but within the product's maximum
+	terms.RateBounds = &RateBounds{Max: decimal.NewFromInt(2)}
+	loan = createTestLoanWithTerms(terms)
+	if err := loan.Restructure(20, decimal.NewFromFloat(1.5)); err != nil {
+		t.Errorf("Expected 150%% to be within the loan's bounds, got %v", err)
+	}
+}
+
+func TestPrepayPrincipal_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		mode     PrepayMode
+		expected error
+	}{
+		{"Negative", NewMoney(-110000), ReduceTerm, ErrNegativeAmount},
+		{"Zero", NewMoney(0), ReducePayment, ErrInvalidPrepaymentAmount},
+		{"Not a whole number of installments", NewMoney(150000), ReduceTerm, ErrInvalidPrepaymentAmount},
+		{"Would remove the week due", NewMoney(5500000), ReduceTerm, ErrInvalidPrepaymentAmount},
+		{"Covers the whole balance", NewMoney(5500000), ReducePayment, ErrInvalidPrepaymentAmount},
+		{"Unknown mode", NewMoney(110000), PrepayMode(9), ErrInvalidPrepayMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			if err := loan.PrepayPrincipal(tt.amount, tt.mode); err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if len(loan.GetPaymentHistory()) != 0 {
+				t.Error("Expected no payment to be recorded")
+			}
+		})
+	}
+}
+
+func TestReversePayment(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+
+	if err := loan.ReversePayment(2); err != nil {
+		t.Fatalf("Expected reversal to succeed, got %v", err)
+	}
+
+	week2 := loan.GetSchedule()[1]
+	if week2.IsPaid || !week2.PaidAmount.IsZero() || week2.PaidAt != nil {
+		t.Errorf("Expected week 2 to be unpaid, got %+v", week2)
+	}
+	if len(loan.GetPaymentHistory()) != 1 || !loan.GetOutstanding().Equals(NewMoney(5390000)) {
+		t.Errorf("Expected 1 payment and IDR 5390000 outstanding, got %d and %s", len(loan.GetPaymentHistory()), loan.GetOutstanding())
+	}
+	if loan.GetNextDueWeek() != 2 {
+		t.Errorf("Expected week 2 to be due again, got %d", loan.GetNextDueWeek())
+	}
+
+	// Week 2 can be paid again
+	if err := loan.MakePayment(NewMoney(110000), 2); err != nil {
+		t.Errorf("Expected week 2 to accept a new payment, got %v", err)
+	}
+}
+
+func TestReversePayment_HeldCredit(t *testing.T) {
+	terms := DefaultTerms()
+	terms.OverpaymentPolicy = HoldAsCredit
+	loan := createTestLoanWithTerms(terms)
+
+	loan.MakePayment(NewMoney(150000), 1)
+	loan.SetCurrentWeek(2)
+	loan.MakePayment(NewMoney(70000), 2) // IDR 40000 from credit
+	if !loan.CreditBalance().IsZero() {
+		t.Fatalf("Expected the credit to be used, got %s", loan.CreditBalance())
+	}
+
+	if err := loan.ReversePayment(2); err != nil {
+		t.Fatalf("Expected reversal to succeed, got %v", err)
+	}
+	if !loan.CreditBalance().Equals(NewMoney(40000)) || !loan.TotalPaid().Equals(NewMoney(110000)) {
+		t.Errorf("Expected IDR 40000 back in credit and IDR 110000 paid, got %s and %s", loan.CreditBalance(), loan.TotalPaid())
+	}
+	log := loan.PaymentLog()
+	if last := log[len(log)-1]; last.Kind != PaymentReversed || !last.Amount.Equals(NewMoney(40000)) {
+		t.Errorf("Expected the reversal to log IDR 40000 returned to credit, got %+v", last)
+	}
+
+	rebuilt, err := loan.Rebuild(log)
+	if err != nil || !rebuilt.CreditBalance().Equals(loan.CreditBalance()) {
+		t.Errorf("Expected the rebuilt loan to hold IDR 40000 credit, got %v, %v", rebuilt, err)
+	}
+}
+
+func TestReversePayment_Validation(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+	loan.MakePartialPayment(NewMoney(50000), 3)
+
+	tests := []struct {
+		name     string
+		week     int
+		expected error
+	}{
+		{"Invalid week", 51, ErrInvalidWeekNumber},
+		{"Unpaid week", 4, ErrWeekNotPaid},
+		{"Would leave a hole", 1, ErrReversalOutOfSequence},
+		{"Later partial payment", 2, ErrReversalOutOfSequence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := loan.ReversePayment(tt.week); err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if len(loan.GetPaymentHistory()) != 3 {
+		t.Errorf("Expected failed reversals to keep all 3 payments, got %d", len(loan.GetPaymentHistory()))
+	}
+
+	// Reversing from the latest week backwards works
+	for _, week := range []int{3, 2, 1} {
+		if err := loan.ReversePayment(week); err != nil {
+			t.Errorf("Expected week %d reversal to succeed, got %v", week, err)
+		}
+	}
+}
+
+func TestGetNextDueWeek(t *testing.T) {
+	loan := createTestLoan()
+
+	// Initially, week 1 should be due
+	if loan.GetNextDueWeek() != 1 {
+		t.Errorf("Expected next due week to be 1, got %d", loan.GetNextDueWeek())
+	}
+
+	// After paying week 1, week 2 should be due
+	loan.MakePayment(NewMoney(110000), 1)
+	if loan.GetNextDueWeek() != 2 {
+		t.Errorf("Expected next due week to be 2, got %d", loan.GetNextDueWeek())
+	}
+
+	// After paying all weeks, should return 0
+	for i := 2; i <= 50; i++ {
+		loan.MakePayment(NewMoney(110000), i)
+	}
+	if loan.GetNextDueWeek() != 0 {
+		t.Errorf("Expected next due week to be 0 (all paid), got %d", loan.GetNextDueWeek())
+	}
+}
+
+func TestUnpaidWeeks(t *testing.T) {
+	terms := DefaultTerms()
+	terms.DurationWeeks = 6
+	terms.AllowNonSequential = true
+	loan := createTestLoanWithTerms(terms)
+	amount := loan.WeeklyPayment
+
+	if weeks := loan.UnpaidWeeks(); !slices.Equal(weeks, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Expected every week unpaid, got %v", weeks)
+	}
+	if weeks := loan.PaidWeeks(); len(weeks) != 0 {
+		t.Errorf("Expected no paid weeks, got %v", weeks)
+	}
+
+	loan.MakePayment(amount, 1)
+	loan.MakePayment(amount, 2)
+	loan.MakePayment(amount, 5)
+
+	if weeks := loan.UnpaidWeeks(); !slices.Equal(weeks, []int{3, 4, 6}) {
+		t.Errorf("Expected weeks 3, 4 and 6 unpaid, got %v", weeks)
+	}
+	if weeks := loan.PaidWeeks(); !slices.Equal(weeks, []int{1, 2, 5}) {
+		t.Errorf("Expected weeks 1, 2 and 5 paid, got %v", weeks)
+	}
+}
+
+func TestDelinquencyScenarios(t *testing.T) {
+	t.Run("New loan in week 1 is not delinquent", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.SetCurrentWeek(1)
+
+		// New loan in week 1 should NOT be delinquent
+		if loan.IsDelinquent() {
+			t.Error("Expected new loan in week 1 to NOT be delinquent")
+		}
+	})
+
+	t.Run("Week 3 with no payments is delinquent", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.SetCurrentWeek(3)
+
+		// Week 3, no payments (behind by 3) - should be delinquent
+		if !loan.IsDelinquent() {
+			t.Error("Expected to be delinquent in week 3 with no payments")
+		}
+	})
+
+	t.Run("Paid week 1, now in week 3 - delinquent", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.SetCurrentWeek(3)
+
+		// Week 3, last paid week 1 (behind by 2) - should be delinquent
+		if !loan.IsDelinquent() {
+			t.Error("Expected to be delinquent when 2 weeks behind")
+		}
+	})
+
+	t.Run("Paid weeks 1-2, now in week 3 - not delinquent", func(t *testing.T) {
+		loan := createTestLoan()
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.MakePayment(NewMoney(110000), 2)
+		loan.SetCurrentWeek(3)
+
+		// Week 3, last paid week 2 (behind by 1) - should NOT be delinquent
+		if loan.IsDelinquent() {
+			t.Error("Expected to NOT be delinquent when only 1 week behind")
+		}
+	})
+
+	t.Run("Customer catches up from delinquency", func(t *testing.T) {
+		loan := createTestLoan()
+
+		// Start at week 3, paid only week 1 (delinquent)
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.SetCurrentWeek(3)
+		if !loan.IsDelinquent() {
+			t.Error("Expected to be delinquent (2 weeks behind)")
+		}
+
+		// Pay week 2 to catch up
+		loan.MakePayment(NewMoney(110000), 2)
+		// Still in week 3, now only 1 week behind
+		if loan.IsDelinquent() {
+			t.Error("Expected to NOT be delinquent after catching up (1 week behind)")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePartialPayment(NewMoney(50000), 2)
+
+	if err := loan.Validate(); err != nil {
+		t.Fatalf("Expected valid loan, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(l *Loan)
+	}{
+		{"Missing borrower", func(l *Loan) { l.BorrowerID = "" }},
+		{"Total doesn't match schedule", func(l *Loan) { l.TotalAmount = NewMoney(5000000) }},
+		{"Truncated schedule", func(l *Loan) { l.Schedule = l.Schedule[:10] }},
+		{"Paid flag without payment", func(l *Loan) { l.Schedule[5].IsPaid = true }},
+		{"Payment not reflected in schedule", func(l *Loan) {
+			l.Payments = append(l.Payments, Payment{WeekNumber: 3, Amount: NewMoney(110000)})
+		}},
+		{"Current week out of range", func(l *Loan) { l.CurrentWeek = 51 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			loan.MakePayment(NewMoney(110000), 1)
+			tt.tamper(loan)
+
+			if err := loan.Validate(); !errors.Is(err, ErrInvalidLoan) {
+				t.Errorf("Expected ErrInvalidLoan, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+	loan.MakePartialPayment(NewMoney(50000), 3)
+
+	if result := loan.Reconcile(); !result.Consistent() {
+		t.Fatalf("Expected no discrepancies, got %+v", result.Discrepancies)
+	}
+
+	// Inject one of each kind of inconsistency
+	loan.Schedule[5].IsPaid = true
+	loan.Payments = append(loan.Payments, Payment{WeekNumber: 10, Amount: NewMoney(110000)})
+	loan.Payments = append(loan.Payments, Payment{WeekNumber: 60, Amount: NewMoney(110000)})
+	loan.Schedule[1].PaidAmount = NewMoney(100000)
+
+	expected := []struct {
+		week     int
+		kind     DiscrepancyKind
+		recorded int64
+	}{
+		{2, AmountMismatch, 110000},
+		{6, PaidWithoutPayment, 0},
+		{10, PaymentWithoutPaidWeek, 110000},
+		{60, PaymentWithoutPaidWeek, 110000},
+	}
+
+	result := loan.Reconcile()
+	if result.Consistent() || len(result.Discrepancies) != len(expected) {
+		t.Fatalf("Expected %d discrepancies, got %+v", len(expected), result.Discrepancies)
+	}
+	for i, want := range expected {
+		got := result.Discrepancies[i]
+		if got.WeekNumber != want.week || got.Kind != want.kind || !got.Recorded.Equals(NewMoney(want.recorded)) {
+			t.Errorf("Expected week %d %s recorded %d, got week %d %s recorded %s", want.week, want.kind, want.recorded, got.WeekNumber, got.Kind, got.Recorded)
+		}
+	}
+}
+
+func TestAdvanceToWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		week     int
+		expected error
+		current  int
+	}{
+		{"Forward", 5, nil, 5},
+		{"Same week", 3, nil, 3},
+		{"Last week", 50, nil, 50},
+		{"Backward", 2, ErrWeekMovedBackward, 3},
+		{"Week 0", 0, ErrInvalidWeekNumber, 3},
+		{"Past duration", 51, ErrInvalidWeekNumber, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := createTestLoan()
+			loan.SetCurrentWeek(3)
+
+			if err := loan.AdvanceToWeek(tt.week); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if loan.CurrentWeek != tt.current {
+				t.Errorf("Expected current week %d, got %d", tt.current, loan.CurrentWeek)
+			}
+		})
+	}
+}
+
+func TestAdvanceWeek(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+
+	if advanced, delinquent := loan.AdvanceWeek(); !advanced || delinquent || loan.CurrentWeek != 2 {
+		t.Errorf("Expected week 2 without becoming delinquent, got week %d", loan.CurrentWeek)
+	}
+	if advanced, delinquent := loan.AdvanceWeek(); !advanced || !delinquent || loan.CurrentWeek != 3 {
+		t.Errorf("Expected week 3 to make the loan delinquent, got week %d", loan.CurrentWeek)
+	}
+	if _, delinquent := loan.AdvanceWeek(); delinquent {
+		t.Error("Expected no new transition while staying delinquent")
+	}
+
+	// Capped at the loan's duration
+	loan.SetCurrentWeek(LoanDurationWeeks)
+	if advanced, _ := loan.AdvanceWeek(); advanced || loan.CurrentWeek != LoanDurationWeeks {
+		t.Errorf("Expected week to stay at %d, got %d", LoanDurationWeeks, loan.CurrentWeek)
+	}
+}
+
+func TestCurrentWeekFromDate(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+	if !loan.StartDate.Equal(clock.Now()) {
+		t.Errorf("Expected start date %v, got %v", clock.Now(), loan.StartDate)
+	}
+
+	tests := []struct {
+		advance  time.Duration
+		expected int
+	}{
+		{0, 1},
+		{6 * 24 * time.Hour, 1}, // day 6, still week 1
+		{24 * time.Hour, 2},     // day 7, week 2
+		{7 * 24 * time.Hour, 3}, // day 14, week 3
+		{100 * 7 * 24 * time.Hour, LoanDurationWeeks}, // past the end, clamped
+	}
+
+	for _, tt := range tests {
+		clock.Advance(tt.advance)
+		if got := loan.CurrentWeekFromDate(); got != tt.expected {
+			t.Errorf("Expected week %d at %v, got %d", tt.expected, clock.Now(), got)
+		}
+	}
+}
+
+func TestDueDateForWeek(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+	due, err := loan.DueDateForWeek(1)
+	if err != nil || !due.Equal(clock.Now()) {
+		t.Errorf("Expected week 1 due on start date %v, got %v (err %v)", clock.Now(), due, err)
+	}
+
+	due, _ = loan.DueDateForWeek(3)
+	if expected := clock.Now().AddDate(0, 0, 14); !due.Equal(expected) {
+		t.Errorf("Expected week 3 due on %v, got %v", expected, due)
+	}
+
+	for _, week := range []int{0, 51} {
+		if _, err := loan.DueDateForWeek(week); !errors.Is(err, ErrInvalidWeekNumber) {
+			t.Errorf("Expected ErrInvalidWeekNumber for week %d, got %v", week, err)
+		}
+	}
+}
+
+func TestProjectedPayoffDate(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		terms    func(*LoanTerms)
+		expected time.Time
+	}{
+		{"Default 50 weeks", func(*LoanTerms) {}, time.Date(2025, 12, 15, 9, 0, 0, 0, time.UTC)},
+		{"Single week", func(t *LoanTerms) { t.DurationWeeks = 1 }, start},
+		{"Started at week 10", func(t *LoanTerms) { t.StartWeek = 10 }, time.Date(2025, 10, 13, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := DefaultTerms()
+			tt.terms(&terms)
+			loan, err := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, &fakeClock{now: start})
+			if err != nil {
+				t.Fatalf("Failed to create loan: %v", err)
+			}
+
+			payoff := loan.ProjectedPayoffDate()
+			if !payoff.Equal(tt.expected) {
+				t.Errorf("Expected payoff on %v, got %v", tt.expected, payoff)
+			}
+
+			// The payoff date is the due date of the last week
+			if last, _ := loan.DueDateForWeek(loan.DurationWeeks); !last.Equal(payoff) {
+				t.Errorf("Expected payoff date to match the last due date %v, got %v", last, payoff)
+			}
+		})
+	}
+}
+
+func TestCurrentOnTimeStreak(t *testing.T) {
+	week := 7 * 24 * time.Hour
+
+	t.Run("Unbroken streak", func(t *testing.T) {
+		clock := newFakeClock()
+		loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+		if loan.CurrentOnTimeStreak() != 0 {
+			t.Errorf("Expected no streak before any payment, got %d", loan.CurrentOnTimeStreak())
+		}
+
+		// Each week paid exactly on its due date
+		for w := 1; w <= 4; w++ {
+			loan.MakePayment(NewMoney(110000), w)
+			clock.Advance(week)
+		}
+
+		if streak := loan.CurrentOnTimeStreak(); streak != 4 {
+			t.Errorf("Expected streak of 4, got %d", streak)
+		}
+	})
+
+	t.Run("Broken by a late payment", func(t *testing.T) {
+		clock := newFakeClock()
+		loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+		// Weeks 1 and 2 on time
+		loan.MakePayment(NewMoney(110000), 1)
+		clock.Advance(week)
+		loan.MakePayment(NewMoney(110000), 2)
+
+		// Week 3 paid a day late
+		clock.Advance(week + 24*time.Hour)
+		loan.MakePayment(NewMoney(110000), 3)
+
+		// Weeks 4 and 5 paid early
+		loan.MakePayment(NewMoney(110000), 4)
+		loan.MakePayment(NewMoney(110000), 5)
+
+		if streak := loan.CurrentOnTimeStreak(); streak != 2 {
+			t.Errorf("Expected streak of 2 after late week 3, got %d", streak)
+		}
+	})
+
+	t.Run("Deferred and restructured weeks skipped", func(t *testing.T) {
+		clock := newFakeClock()
+		terms := DefaultTerms()
+		terms.MaxDeferrals = 2
+		loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, clock)
+
+		// Week 1 paid a day late, then weeks 2 and 3 deferred unpaid
+		clock.Advance(24 * time.Hour)
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.DeferWeek(2)
+		loan.DeferWeek(3)
+
+		if streak := loan.CurrentOnTimeStreak(); streak != 0 {
+			t.Errorf("Expected deferred weeks not to count as on time, got a streak of %d", streak)
+		}
+		if series := loan.PaymentTimingSeries(); len(series) != 1 || series[0].WeekNumber != 1 {
+			t.Errorf("Expected only week 1 in the timing series, got %+v", series)
+		}
+
+		// Week 2 is settled at nothing by the restructure, between a late
+		// week 1 and an early week 3
+		terms = DefaultTerms()
+		terms.AllowNonSequential = true
+		loan, _ = NewLoanWithClock("loan-2", "borrower-1", NewMoney(5000000), terms, clock)
+		clock.Advance(24 * time.Hour)
+		loan.MakePayment(NewMoney(110000), 1)
+		loan.MakePayment(NewMoney(110000), 3)
+		if err := loan.Restructure(60, decimal.NewFromFloat(0.10)); err != nil {
+			t.Fatalf("Restructure failed: %v", err)
+		}
+
+		if streak := loan.CurrentOnTimeStreak(); streak != 1 {
+			t.Errorf("Expected a streak of 1 with week 2 skipped, got %d", streak)
+		}
+		if series := loan.PaymentTimingSeries(); len(series) != 2 || series[1].WeekNumber != 3 {
+			t.Errorf("Expected weeks 1 and 3 in the timing series, got %+v", series)
+		}
+	})
+}
+
+func TestPaymentTimingSeries(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+	day := 24 * time.Hour
+
+	// Week 1 on its due date, week 2 three days late, week 3 two days early
+	loan.MakePayment(NewMoney(110000), 1)
+	clock.Advance(10 * day)
+	loan.MakePayment(NewMoney(110000), 2)
+	clock.Advance(2 * day)
+	loan.MakePayment(NewMoney(110000), 3)
+
+	series := loan.PaymentTimingSeries()
+	if len(series) != len(loan.GetPaymentHistory()) {
+		t.Fatalf("Expected %d timings, got %d", len(loan.GetPaymentHistory()), len(series))
+	}
+
+	expected := []int{0, 3, -2}
+	for i, timing := range series {
+		if timing.WeekNumber != i+1 || timing.DaysDelta != expected[i] {
+			t.Errorf("Expected week %d delta %d, got week %d delta %d", i+1, expected[i], timing.WeekNumber, timing.DaysDelta)
+		}
+		if due, _ := loan.DueDateForWeek(i + 1); !timing.DueDate.Equal(due) {
+			t.Errorf("Expected week %d due %v, got %v", i+1, due, timing.DueDate)
+		}
+	}
+}
+
+func TestDelinquencyWithClock(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+	// Week 1: pay on time
+	loan.SyncCurrentWeek()
+	if err := loan.MakePayment(NewMoney(110000), 1); err != nil {
+		t.Fatalf("Failed to make payment for week 1: %v", err)
+	}
+	if !loan.Payments[0].PaidAt.Equal(clock.Now()) {
+		t.Errorf("Expected payment to be stamped with clock time %v, got %v", clock.Now(), loan.Payments[0].PaidAt)
+	}
+
+	// Week 2: one week behind, not delinquent
+	clock.Advance(7 * 24 * time.Hour)
+	loan.SyncCurrentWeek()
+	if loan.CurrentWeek != 2 || loan.IsDelinquent() {
+		t.Errorf("Expected week 2 and not delinquent, got week %d delinquent=%v", loan.CurrentWeek, loan.IsDelinquent())
+	}
+
+	// Week 3: two weeks behind, delinquent
+	clock.Advance(7 * 24 * time.Hour)
+	loan.SyncCurrentWeek()
+	if loan.CurrentWeek != 3 || !loan.IsDelinquent() {
+		t.Errorf("Expected week 3 and delinquent, got week %d delinquent=%v", loan.CurrentWeek, loan.IsDelinquent())
+	}
+}
+
+func TestIsDelinquentByDate_GracePeriod(t *testing.T) {
+	day := 24 * time.Hour
+	week := 7 * day
+
+	tests := []struct {
+		name        string
+		graceDays   int
+		elapsed     time.Duration // Time since the start date
+		weeksBehind int
+		delinquent  bool
+	}{
+		// Week 2 is due 7 days in; nothing is paid
+		{"No grace, week 2 due", 0, week, 2, true},
+		{"Within grace, 1 day past due", 3, week + day, 1, false},
+		{"Last moment of grace", 3, week + 3*day - time.Second, 1, false},
+		{"Grace over", 3, week + 3*day, 2, true},
+		{"Week 1 within grace", 3, day, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			terms := DefaultTerms()
+			terms.GracePeriodDays = tt.graceDays
+			loan, err := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, clock)
+			if err != nil {
+				t.Fatalf("Failed to create loan: %v", err)
+			}
+
+			clock.Advance(tt.elapsed)
+			if behind := loan.WeeksBehindByDate(); behind != tt.weeksBehind {
+				t.Errorf("Expected %d weeks behind, got %d", tt.weeksBehind, behind)
+			}
+			if delinquent := loan.IsDelinquentByDate(); delinquent != tt.delinquent {
+				t.Errorf("Expected delinquent=%v, got %v", tt.delinquent, delinquent)
+			}
+		})
+	}
+}
+
+func TestIsDelinquentByDate_MatchesWeekBased(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+	loan.MakePayment(NewMoney(110000), 1)
+
+	// Without a grace period the date-aware check agrees with SyncCurrentWeek
+	for day := 0; day < 35; day++ {
+		loan.SyncCurrentWeek()
+		if loan.WeeksBehindByDate() != loan.WeeksBehind() || loan.IsDelinquentByDate() != loan.IsDelinquent() {
+			t.Errorf("Day %d: date-aware %d weeks behind, week-based %d", day, loan.WeeksBehindByDate(), loan.WeeksBehind())
+		}
+		clock.Advance(24 * time.Hour)
+	}
+}
+
+func TestIsDelinquentAsOf(t *testing.T) {
+	day := 24 * time.Hour
+	clock := newFakeClock()
+	start := clock.Now()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+
+	// Week 1 is due at origination and week 2 seven days later; threshold is 2
+	tests := []struct {
+		name        string
+		now         time.Time
+		weeksBehind int
+		delinquent  bool
+	}{
+		{"Before origination", start.Add(-day), 0, false},
+		{"Week 1 due", start, 1, false},
+		{"Just before week 2 due", start.Add(7*day - time.Second), 1, false},
+		{"Week 2 due", start.Add(7 * day), 2, true},
+		{"Week 3 due", start.Add(15 * day), 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if behind := loan.WeeksBehindAsOf(tt.now); behind != tt.weeksBehind {
+				t.Errorf("Expected %d weeks behind, got %d", tt.weeksBehind, behind)
+			}
+			if delinquent := loan.IsDelinquentAsOf(tt.now); delinquent != tt.delinquent {
+				t.Errorf("Expected delinquent=%v, got %v", tt.delinquent, delinquent)
+			}
+		})
+	}
+
+	// Week 1 paid on day 10: delinquent on day 8 but not after the payment
+	clock.Advance(10 * day)
+	if err := loan.MakePayment(NewMoney(110000), 1); err != nil {
+		t.Fatalf("MakePayment failed: %v", err)
+	}
+	if !loan.IsDelinquentAsOf(start.Add(8 * day)) {
+		t.Error("Expected loan to be delinquent on day 8, before week 1 was paid")
+	}
+	if loan.IsDelinquentAsOf(start.Add(10 * day)) {
+		t.Error("Expected loan not to be delinquent on day 10, once week 1 was paid")
+	}
+
+	// The integer-based check is unaffected
+	if loan.IsDelinquent() {
+		t.Error("Expected IsDelinquent to stay false at week 1")
+	}
+}
+
+func TestRevolvingInterest(t *testing.T) {
+	principal := NewMoney(5000000)
+	flat := createTestLoan()
+	loan := createRevolvingTestLoan()
+
+	// Interest on the declining balance is lower than flat interest
+	flatInterest := flat.TotalAmount.Subtract(principal)
+	revolvingInterest := loan.TotalAmount.Subtract(principal)
+	if !revolvingInterest.LessThan(flatInterest) {
+		t.Errorf("Expected revolving interest %s to be less than flat interest %s", revolvingInterest, flatInterest)
+	}
+
+	// Week 1: 100,000 principal + 5,000,000 * 0.10 / 52 = 9,615 interest
+	if !loan.Schedule[0].Amount.Equals(NewMoney(109615)) {
+		t.Errorf("Expected week 1 installment IDR 109615, got %s", loan.Schedule[0].Amount)
+	}
+
+	// Installments shrink as principal is repaid
+	last := loan.Schedule[len(loan.Schedule)-1].Amount
+	if !last.LessThan(loan.Schedule[0].Amount) {
+		t.Errorf("Expected last installment %s to be less than first %s", last, loan.Schedule[0].Amount)
+	}
+
+	// Schedule sums to the total amount
+	total := NewMoney(0)
+	for _, entry := range loan.Schedule {
+		total = total.Add(entry.Amount)
+	}
+	if !total.Equals(loan.TotalAmount) {
+		t.Errorf("Expected schedule to sum to %s, got %s", loan.TotalAmount, total)
+	}
+
+	// Paying each scheduled installment fully amortizes the loan
+	for _, entry := range loan.Schedule {
+		if err := loan.MakePayment(entry.Amount, entry.WeekNumber); err != nil {
+			t.Fatalf("Failed to make payment for week %d: %v", entry.WeekNumber, err)
+		}
+	}
+	if !loan.IsClosed() {
+		t.Errorf("Expected revolving loan to be closed, outstanding %s", loan.GetOutstanding())
+	}
+}
+
+func TestRevolvingInterest_SmallPrincipal(t *testing.T) {
+	// IDR 11 over 7 weeks: 1.57 a week rounded up would leave week 7 at -1
+	terms := DefaultTerms()
+	terms.InterestModel = RevolvingInterest
+	terms.DurationWeeks = 7
+	terms.AnnualInterestRate = decimal.Zero
+
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoney(11), terms)
+	if err != nil {
+		t.Fatalf("Expected a small principal to be accepted, got %v", err)
+	}
+	if !loan.Schedule[0].PrincipalPortion.Equals(NewMoney(1)) || !loan.Schedule[6].PrincipalPortion.Equals(NewMoney(5)) {
+		t.Errorf("Expected IDR 1 a week and IDR 5 in week 7, got %s and %s", loan.Schedule[0].PrincipalPortion, loan.Schedule[6].PrincipalPortion)
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected a valid loan, got %v", err)
+	}
+}
+
+func TestRevolvingInterest_RejectsFlatAmount(t *testing.T) {
+	loan := createRevolvingTestLoan()
+
+	err := loan.MakePayment(NewMoney(110000), 1)
+	if !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount, got %v", err)
+	}
+}
+
+func TestInterestModels_KnownTotals(t *testing.T) {
+	tests := []struct {
+		model         InterestModel
+		expectedTotal Money
+		expectedFirst Money
+	}{
+		// 5,000,000 + 5,000,000 * 0.10
+		{FlatInterest, NewMoney(5500000), NewMoney(110000)},
+		// r = 0.10 / 52, installment = P * r / (1 - (1 + r)^-50) ≈ 104,980.78
+		{CompoundWeekly, NewMoney(5249038), NewMoney(104981)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model.String(), func(t *testing.T) {
+			terms := DefaultTerms()
+			terms.InterestModel = tt.model
+			loan := createTestLoanWithTerms(terms)
+
+			if !loan.TotalAmount.Equals(tt.expectedTotal) {
+				t.Errorf("Expected total %s, got %s", tt.expectedTotal, loan.TotalAmount)
+			}
+			if !loan.WeeklyPayment.Equals(tt.expectedFirst) {
+				t.Errorf("Expected weekly payment %s, got %s", tt.expectedFirst, loan.WeeklyPayment)
+			}
+		})
+	}
+}
+
+func TestCompoundWeekly(t *testing.T) {
+	terms := DefaultTerms()
+	terms.InterestModel = CompoundWeekly
+	loan := createTestLoanWithTerms(terms)
+
+	// Equal installments, apart from the final one absorbing rounding
+	for _, entry := range loan.Schedule[:len(loan.Schedule)-1] {
+		if !entry.Amount.Equals(loan.WeeklyPayment) {
+			t.Errorf("Expected week %d installment %s, got %s", entry.WeekNumber, loan.WeeklyPayment, entry.Amount)
+		}
+	}
+
+	for _, entry := range loan.Schedule {
+		if err := loan.MakePayment(entry.Amount, entry.WeekNumber); err != nil {
+			t.Fatalf("Failed to make payment for week %d: %v", entry.WeekNumber, err)
+		}
+	}
+	if !loan.IsClosed() {
+		t.Errorf("Expected compound loan to be closed, outstanding %s", loan.GetOutstanding())
+	}
+}
+
+func TestCompoundWeekly_ZeroRate(t *testing.T) {
+	terms := DefaultTerms()
+	terms.InterestModel = CompoundWeekly
+	terms.AnnualInterestRate = decimal.Zero
+	loan := createTestLoanWithTerms(terms)
+
+	if !loan.TotalAmount.Equals(NewMoney(5000000)) {
+		t.Errorf("Expected total to equal principal, got %s", loan.TotalAmount)
+	}
+	if !loan.WeeklyPayment.Equals(NewMoney(100000)) {
+		t.Errorf("Expected weekly payment IDR 100000, got %s", loan.WeeklyPayment)
+	}
+}
+
+func TestSchedulePortions(t *testing.T) {
+	for _, model := range []InterestModel{FlatInterest, RevolvingInterest, CompoundWeekly} {
+		t.Run(model.String(), func(t *testing.T) {
+			terms := DefaultTerms()
+			terms.InterestModel = model
+			loan := createTestLoanWithTerms(terms)
+
+			principal, interest := NewMoney(0), NewMoney(0)
+			for _, entry := range loan.Schedule {
+				if !entry.PrincipalPortion.Add(entry.InterestPortion).Equals(entry.Amount) {
+					t.Errorf("Week %d: portions %s + %s do not sum to %s",
+						entry.WeekNumber, entry.PrincipalPortion, entry.InterestPortion, entry.Amount)
+				}
+				principal = principal.Add(entry.PrincipalPortion)
+				interest = interest.Add(entry.InterestPortion)
+			}
+
+			if !principal.Equals(loan.Principal) {
+				t.Errorf("Expected principal portions to sum to %s, got %s", loan.Principal, principal)
+			}
+			if !principal.Add(interest).Equals(loan.TotalAmount) {
+				t.Errorf("Expected portions to sum to %s, got %s", loan.TotalAmount, principal.Add(interest))
+			}
+		})
+	}
+
+	t.Run("compound interest declines", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.InterestModel = CompoundWeekly
+		loan := createTestLoanWithTerms(terms)
+
+		first, last := loan.Schedule[0], loan.Schedule[len(loan.Schedule)-1]
+		if !last.InterestPortion.LessThan(first.InterestPortion) {
+			t.Errorf("Expected interest to decline from %s, got %s in the last week", first.InterestPortion, last.InterestPortion)
+		}
+	})
+
+	t.Run("flat interest is even", func(t *testing.T) {
+		loan := createTestLoan()
+
+		if !loan.Schedule[0].InterestPortion.Equals(NewMoney(10000)) {
+			t.Errorf("Expected weekly interest IDR 10000, got %s", loan.Schedule[0].InterestPortion)
+		}
+	})
+}
+
+// Helper functions
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func createTestLoan() *Loan {
+	return createTestLoanWithTerms(DefaultTerms())
+}
+
+func createRevolvingTestLoan() *Loan {
+	terms := DefaultTerms()
+	terms.InterestModel = RevolvingInterest
+	return createTestLoanWithTerms(terms)
+}
+
+func createTestLoanWithTerms(terms LoanTerms) *Loan {
+	principal := NewMoney(5000000)
+	loan, err := NewLoan("test-loan", "test-borrower", principal, terms)
+	if err != nil {
+		panic(err)
+	}
+	return loan
+}
+
+func makeRange(min, max int) []int {
+	result := make([]int, max-min+1)
+	for i := range result {
+		result[i] = min + i
+	}
+	return result
+}
+
+func TestPromoFreeWeeks(t *testing.T) {
+	terms := DefaultTerms()
+	terms.PromoFreeWeeks = 10
+	loan := createTestLoanWithTerms(terms)
+
+	for _, entry := range loan.Schedule {
+		want, wantInterest := NewMoney(112500), NewMoney(12500)
+		if entry.WeekNumber <= 10 {
+			want, wantInterest = NewMoney(100000), NewMoney(0)
+		}
+		if !entry.Amount.Equals(want) || !entry.InterestPortion.Equals(wantInterest) {
+			t.Errorf("Week %d: expected %s with %s interest, got %s with %s",
+				entry.WeekNumber, want, wantInterest, entry.Amount, entry.InterestPortion)
+		}
+	}
+
+	if !loan.TotalAmount.Equals(NewMoney(5500000)) || !loan.TotalInterestCost().Equals(NewMoney(500000)) {
+		t.Errorf("Expected total 5500000 with 500000 interest, got %s with %s", loan.TotalAmount, loan.TotalInterestCost())
+	}
+	if !loan.WeeklyPayment.Equals(NewMoney(100000)) {
+		t.Errorf("Expected first installment 100000, got %s", loan.WeeklyPayment)
+	}
+	if err := loan.MakePayment(NewMoney(100000), 1); err != nil {
+		t.Errorf("Expected the promo installment to be accepted, got %v", err)
+	}
+
+	t.Run("migrated mid-promo", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.PromoFreeWeeks = 10
+		terms.StartWeek = 6
+		loan := createTestLoanWithTerms(terms)
+
+		free := 0
+		for _, entry := range loan.Schedule {
+			if entry.InterestPortion.IsZero() {
+				free++
+			}
+		}
+		if free != 5 {
+			t.Errorf("Expected weeks 6-10 to be interest-free, got %d free weeks", free)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, weeks := range []int{-1, 50} {
+			terms := DefaultTerms()
+			terms.PromoFreeWeeks = weeks
+			if _, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), terms); !errors.Is(err, ErrInvalidPromoFreeWeeks) {
+				t.Errorf("PromoFreeWeeks %d: expected ErrInvalidPromoFreeWeeks, got %v", weeks, err)
+			}
+		}
+	})
+}
+
+func TestTotalPaidCache(t *testing.T) {
+	loan := createTestLoan()
+
+	recomputed := func() Money {
+		total := NewMoney(0)
+		for _, payment := range loan.GetPaymentHistory() {
+			total = total.Add(payment.Amount)
+		}
+		return total
+	}
+	check := func(step string) {
+		t.Helper()
+		if got, want := loan.TotalPaid(), recomputed(); !got.Equals(want) {
+			t.Errorf("After %s: cached total %s, recomputed %s", step, got, want)
+		}
+		if got, want := loan.GetOutstanding(), loan.TotalAmount.Subtract(recomputed()); !got.Equals(want) {
+			t.Errorf("After %s: outstanding %s, expected %s", step, got, want)
+		}
+	}
+
+	check("no payments")
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+	check("two payments")
+	loan.MakePartialPayment(NewMoney(50000), 3)
+	loan.MakePartialPayment(NewMoney(60000), 3)
+	check("partial payments")
+	loan.ReversePayment(3)
+	check("a reversal")
+	loan.MakePayment(NewMoney(110000), 3)
+	loan.PrepayPrincipal(NewMoney(220000), ReduceTerm)
+	check("a prepayment")
+	if !loan.TotalPaid().Equals(NewMoney(550000)) {
+		t.Errorf("Expected 550000 paid, got %s", loan.TotalPaid())
+	}
+}
+
+func TestWasDelinquentAt(t *testing.T) {
+	loan := createTestLoan()
+
+	// Weeks 1-2 paid, 3-4 missed, 5 paid late, then nothing
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePayment(NewMoney(110000), 2)
+	loan.SetCurrentWeek(8)
+	loan.MakePayment(NewMoney(110000), 3)
+	loan.MakePayment(NewMoney(110000), 4)
+	loan.MakePayment(NewMoney(110000), 5)
+
+	timeline := map[int]bool{
+		0:  false,
+		1:  false,
+		2:  false,
+		3:  false,
+		5:  false,
+		6:  false,
+		7:  true,
+		8:  true,
+		50: true,
+		99: true,
+	}
+	for week, want := range timeline {
+		if got := loan.WasDelinquentAt(week); got != want {
+			t.Errorf("Week %d: expected delinquent=%v, got %v", week, want, got)
+		}
+	}
+
+	if loan.CurrentWeek != 8 {
+		t.Errorf("Expected current week to stay 8, got %d", loan.CurrentWeek)
+	}
+	if loan.WasDelinquentAt(8) != loan.IsDelinquent() {
+		t.Errorf("Expected WasDelinquentAt(CurrentWeek) to match IsDelinquent")
+	}
+
+	t.Run("non-sequential", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.AllowNonSequential = true
+		loan := createTestLoanWithTerms(terms)
+		loan.MakePayment(NewMoney(110000), 2)
+		loan.MakePayment(NewMoney(110000), 4)
+
+		// Week 3 has weeks 1 and 3 unpaid; week 4 still only those two
+		for week, want := range map[int]bool{2: false, 3: true, 4: true, 5: true} {
+			if got := loan.WasDelinquentAt(week); got != want {
+				t.Errorf("Week %d: expected delinquent=%v, got %v", week, want, got)
+			}
+		}
+	})
+}
+
+func TestOverpaid(t *testing.T) {
+	loan := createTestLoan()
+	if !loan.Overpaid().IsZero() {
+		t.Errorf("Expected a new loan not to be overpaid, got %s", loan.Overpaid())
+	}
+
+	// Imported history paying more than the total
+	loan.Payments = append(loan.Payments, Payment{WeekNumber: 0, Amount: NewMoney(5600000)})
+	if !loan.GetOutstanding().IsZero() {
+		t.Errorf("Expected outstanding clamped at zero, got %s", loan.GetOutstanding())
+	}
+	if !loan.Overpaid().Equals(NewMoney(100000)) {
+		t.Errorf("Expected 100000 overpaid, got %s", loan.Overpaid())
+	}
+	if !loan.IsClosed() {
+		t.Errorf("Expected an overpaid loan to be closed")
+	}
+
+	t.Run("credit beyond the total", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.OverpaymentPolicy = HoldAsCredit
+		loan := createTestLoanWithTerms(terms)
+
+		if err := loan.MakePayment(NewMoney(6000000), 1); err != nil {
+			t.Fatalf("MakePayment failed: %v", err)
+		}
+		if !loan.Overpaid().Equals(NewMoney(500000)) {
+			t.Errorf("Expected 500000 overpaid, got %s", loan.Overpaid())
+		}
+		if loan.IsClosed() {
+			t.Errorf("Expected the loan to stay open until the credit is applied")
+		}
+	})
+}
+
+func TestScheduleProjection(t *testing.T) {
+	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.MakePartialPayment(NewMoney(50000), 2)
+	before := loan.GetSchedule()
+
+	projection, err := loan.ScheduleProjection(5)
+	if err != nil {
+		t.Fatalf("ScheduleProjection failed: %v", err)
+	}
+	for i, entry := range projection {
+		paid := i < 5
+		if entry.IsPaid != paid || (paid && (!entry.PaidAmount.Equals(entry.Amount) || entry.PaidAt == nil)) {
+			t.Errorf("Week %d: expected paid=%v, got %+v", entry.WeekNumber, paid, entry)
+		}
+	}
+	if !projection[0].PaidAt.Equal(*before[0].PaidAt) {
+		t.Errorf("Expected week 1 to keep its PaidAt %s, got %s", before[0].PaidAt, projection[0].PaidAt)
+	}
+
+	// The real schedule is unchanged
+	for i, entry := range loan.Schedule {
+		if entry.IsPaid != before[i].IsPaid || !entry.PaidAmount.Equals(before[i].PaidAmount) {
+			t.Errorf("Week %d changed by the projection: %+v", entry.WeekNumber, entry)
+		}
+	}
+	if !loan.TotalPaid().Equals(NewMoney(160000)) || !loan.GetOutstanding().Equals(NewMoney(5340000)) {
+		t.Errorf("Expected IDR 160000 paid, got %s", loan.TotalPaid())
+	}
+
+	for _, weeks := range []int{-1, 51} {
+		if _, err := loan.ScheduleProjection(weeks); err != ErrInvalidWeekNumber {
+			t.Errorf("%d weeks: expected ErrInvalidWeekNumber, got %v", weeks, err)
+		}
+	}
+	if projection, err := loan.ScheduleProjection(50); err != nil || !projection[49].IsPaid {
+		t.Errorf("Expected every week paid, got %v", err)
+	}
+}
+
+func TestScheduleWithDates(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
+	loan.MakePayment(NewMoney(110000), 1)
+
+	schedule := loan.ScheduleWithDates()
+	if len(schedule) != 50 {
+		t.Fatalf("Expected 50 entries, got %d", len(schedule))
+	}
+	if !schedule[0].DueDate.Equal(clock.Now()) || !schedule[0].IsPaid || !schedule[0].Amount.Equals(NewMoney(110000)) {
+		t.Errorf("Expected paid week 1 due on the start date, got %+v", schedule[0])
+	}
+	for i := 1; i < len(schedule); i++ {
+		if gap := schedule[i].DueDate.Sub(schedule[i-1].DueDate); gap != 7*24*time.Hour {
+			t.Errorf("Week %d: expected 7 days after the previous week, got %v", schedule[i].WeekNumber, gap)
+		}
+	}
+}
+
+func TestPaymentFrequency(t *testing.T) {
+	t.Run("biweekly", func(t *testing.T) {
+		clock := newFakeClock()
+		terms := DefaultTerms()
+		terms.DurationWeeks = 25
+		terms.PaymentFrequency = Biweekly
+		loan, err := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, clock)
+		if err != nil {
+			t.Fatalf("NewLoan failed: %v", err)
+		}
+
+		schedule := loan.ScheduleWithDates()
+		if len(schedule) != 25 {
+			t.Fatalf("Expected 25 installments, got %d", len(schedule))
+		}
+		for i := 1; i < len(schedule); i++ {
+			if gap := schedule[i].DueDate.Sub(schedule[i-1].DueDate); gap != 14*24*time.Hour {
+				t.Errorf("Installment %d: expected 14 days after the previous one, got %v", schedule[i].WeekNumber, gap)
+			}
+		}
+		if !loan.InstallmentAmount().Equals(NewMoney(220000)) || !loan.WeeklyPayment.Equals(loan.InstallmentAmount()) {
+			t.Errorf("Expected installments of 220000, got %s", loan.InstallmentAmount())
+		}
+
+		// Delinquency counts periods: 29 days in is the third installment
+		clock.Advance(29 * 24 * time.Hour)
+		loan.SyncCurrentWeek()
+		if loan.CurrentWeek != 3 || loan.WeeksBehind() != 3 || !loan.IsDelinquentByDate() {
+			t.Errorf("Expected installment 3 with 3 behind, got %d with %d", loan.CurrentWeek, loan.WeeksBehind())
+		}
+	})
+
+	t.Run("monthly", func(t *testing.T) {
+		start := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+		clock := &fakeClock{now: start}
+		terms := DefaultTerms()
+		terms.DurationWeeks = 12
+		terms.PaymentFrequency = Monthly
+		terms.InterestModel = CompoundWeekly
+		loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(1200000), terms, clock)
+
+		due, _ := loan.DueDateForWeek(3)
+		if want := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC); !due.Equal(want) {
+			t.Errorf("Expected installment 3 due %v, got %v", want, due)
+		}
+
+		clock.now = time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+		if week := loan.CurrentWeekFromDate(); week != 2 {
+			t.Errorf("Expected installment 2 the day before the third is due, got %d", week)
+		}
+
+		// Interest compounds at 10% / 12 per month
+		if got := loan.Schedule[0].InterestPortion; !got.Equals(NewMoney(10000)) {
+			t.Errorf("Expected first month's interest 10000, got %s", got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.PaymentFrequency = PaymentFrequency(7)
+		if _, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), terms); !errors.Is(err, ErrInvalidPaymentFrequency) {
+			t.Errorf("Expected ErrInvalidPaymentFrequency, got %v", err)
+		}
+	})
+}
+
+func TestCatchUp(t *testing.T) {
+	loan := createTestLoan()
+	loan.SetCurrentWeek(3)
+	loan.MakePartialPayment(NewMoney(10000), 1)
+
+	payments, err := loan.CatchUp()
+	if err != nil {
+		t.Fatalf("CatchUp failed: %v", err)
+	}
+
+	// Week 1 only owes what the partial payment left
+	want := []Payment{
+		{WeekNumber: 1, Amount: NewMoney(100000)},
+		{WeekNumber: 2, Amount: NewMoney(110000)},
+		{WeekNumber: 3, Amount: NewMoney(110000)},
+	}
+	if len(payments) != len(want) {
+		t.Fatalf("Expected %d payments, got %+v", len(want), payments)
+	}
+	for i, payment := range payments {
+		if payment.WeekNumber != want[i].WeekNumber || !payment.Amount.Equals(want[i].Amount) {
+			t.Errorf("Expected %+v, got %+v", want[i], payment)
+		}
+	}
+	if loan.WeeksBehind() != 0 || loan.GetNextDueWeek() != 4 {
+		t.Errorf("Expected to be caught up with week 4 next, got %d behind", loan.WeeksBehind())
+	}
+
+	loan.Archive()
+	loan.SetCurrentWeek(5)
+	if _, err := loan.CatchUp(); !errors.Is(err, ErrLoanArchived) {
+		t.Errorf("Expected ErrLoanArchived, got %v", err)
+	}
+}
+
+func TestPayoffAmount(t *testing.T) {
+	tests := []struct {
+		method     RebateMethod
+		wantRebate int64
+	}{
+		{NoRebate, 0},
+		// 40 unpaid weeks of 10000 interest each
+		{Actuarial, 400000},
+		// 500000 * (40*41) / (50*51), rounded down
+		{RuleOf78, 321568},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method.String(), func(t *testing.T) {
+			terms := DefaultTerms()
+			terms.RebateMethod = tt.method
+			loan := createTestLoanWithTerms(terms)
+			for week := 1; week <= 10; week++ {
+				if err := loan.MakePayment(NewMoney(110000), week); err != nil {
+					t.Fatalf("MakePayment week %d failed: %v", week, err)
+				}
+			}
+
+			outstanding := loan.GetOutstanding()
+			if !outstanding.Equals(NewMoney(4400000)) {
+				t.Fatalf("Expected outstanding 4400000, got %s", outstanding)
+			}
+			if got := loan.InterestRebate(10); !got.Equals(NewMoney(tt.wantRebate)) {
+				t.Errorf("Expected rebate %d, got %s", tt.wantRebate, got)
+			}
+			want := outstanding.Subtract(NewMoney(tt.wantRebate))
+			if got := loan.PayoffAmount(10); !got.Equals(want) {
+				t.Errorf("Expected payoff %s, got %s", want, got)
+			}
+		})
+	}
+
+	t.Run("invalid method", func(t *testing.T) {
+		terms := DefaultTerms()
+		terms.RebateMethod = RebateMethod(99)
+		_, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), terms)
+		if !errors.Is(err, ErrInvalidRebateMethod) {
+			t.Errorf("Expected ErrInvalidRebateMethod, got %v", err)
+		}
+	})
+}
+
+func TestPayOff_AtPayoffAmount(t *testing.T) {
+	for _, method := range []RebateMethod{NoRebate, Actuarial, RuleOf78} {
+		t.Run(method.String(), func(t *testing.T) {
+			terms := DefaultTerms()
+			terms.RebateMethod = method
+			loan := createTestLoanWithTerms(terms)
+			for week := 1; week <= 10; week++ {
+				loan.MakePayment(NewMoney(110000), week)
+			}
+			loan.SetCurrentWeek(10)
+
+			outstanding := loan.GetOutstanding()
+			rebate := loan.InterestRebate(10)
+			payoff := loan.PayoffAmount(10)
+			if method != NoRebate {
+				// Amounts other than the outstanding and the payoff amount are rejected
+				if err := loan.PayOff(payoff.Add(NewMoney(1))); err != ErrInvalidPayoffAmount {
+					t.Errorf("Expected ErrInvalidPayoffAmount just above the payoff amount, got %v", err)
+				}
+			}
+
+			if err := loan.PayOff(payoff); err != nil {
+				t.Fatalf("PayOff(%s) failed: %v", payoff, err)
+			}
+			if loan.Status() != PaidOff || !loan.GetOutstanding().IsZero() {
+				t.Errorf("Expected the loan paid off, got %s with %s outstanding", loan.Status(), loan.GetOutstanding())
+			}
+			if !loan.WrittenOff.Equals(rebate) || !loan.TotalAmount.Equals(NewMoney(5500000).Subtract(rebate)) {
+				t.Errorf("Expected %s written off, got %s with total amount %s", rebate, loan.WrittenOff, loan.TotalAmount)
+			}
+			if !loan.TotalPaid().Equals(NewMoney(1100000).Add(payoff)) || !payoff.Add(rebate).Equals(outstanding) {
+				t.Errorf("Expected %s paid, got %s", NewMoney(1100000).Add(payoff), loan.TotalPaid())
+			}
+			if !loan.PrincipalPaidToDate().Equals(loan.Principal) {
+				t.Errorf("Expected the whole principal %s paid, got %s", loan.Principal, loan.PrincipalPaidToDate())
+			}
+			if err := loan.Validate(); err != nil {
+				t.Errorf("Expected a valid loan, got %v", err)
+			}
+		})
+	}
+
+	// The full outstanding is still accepted, without a rebate
+	terms := DefaultTerms()
+	terms.RebateMethod = Actuarial
+	loan := createTestLoanWithTerms(terms)
+	if err := loan.PayOff(loan.GetOutstanding()); err != nil || !loan.WrittenOff.IsZero() {
+		t.Errorf("Expected payoff at the outstanding without a write-off, got %v and %s written off", err, loan.WrittenOff)
+	}
+}
+
+func TestLoadPayments(t *testing.T) {
+	paidAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	payments := []Payment{
+		{WeekNumber: 1, Amount: NewMoney(110000), PaidAt: paidAt, Reference: "trx-1"},
+		{WeekNumber: 2, Amount: NewMoney(60000), PaidAt: paidAt},
+		{WeekNumber: 2, Amount: NewMoney(50000), PaidAt: paidAt.Add(time.Hour)},
+		// Out of sequence, which MakePayment would reject
+		{WeekNumber: 4, Amount: NewMoney(110000), PaidAt: paidAt},
+	}
+
+	loan := createTestLoan()
+	if err := loan.LoadPayments(payments); err != nil {
+		t.Fatalf("LoadPayments failed: %v", err)
+	}
+
+	if !loan.GetOutstanding().Equals(NewMoney(5170000)) {
+		t.Errorf("Expected outstanding 5170000, got %s", loan.GetOutstanding())
+	}
+	if loan.GetNextDueWeek() != 3 {
+		t.Errorf("Expected next due week 3, got %d", loan.GetNextDueWeek())
+	}
+	if loan.PaymentsMadeCount() != 4 {
+		t.Errorf("Expected 4 payments, got %d", loan.PaymentsMadeCount())
+	}
+	week2 := loan.GetSchedule()[1]
+	if !week2.IsPaid || !week2.PaidAt.Equal(paidAt.Add(time.Hour)) {
+		t.Errorf("Expected week 2 paid at the last payment's time, got %+v", week2)
+	}
+
+	t.Run("rejects duplicate weeks", func(t *testing.T) {
+		err := loan.LoadPayments([]Payment{
+			{WeekNumber: 3, Amount: NewMoney(110000), PaidAt: paidAt},
+			{WeekNumber: 1, Amount: NewMoney(110000), PaidAt: paidAt},
+		})
+		if !errors.Is(err, ErrWeekAlreadyPaid) {
+			t.Errorf("Expected ErrWeekAlreadyPaid, got %v", err)
+		}
+		if loan.GetNextDueWeek() != 3 || loan.PaymentsMadeCount() != 4 {
+			t.Errorf("Expected a failed load to leave the loan unchanged")
+		}
+	})
+
+	t.Run("rejects unscheduled weeks", func(t *testing.T) {
+		err := createTestLoan().LoadPayments([]Payment{{WeekNumber: 0, Amount: NewMoney(100000)}})
+		if !errors.Is(err, ErrInvalidWeekNumber) {
+			t.Errorf("Expected ErrInvalidWeekNumber, got %v", err)
+		}
+	})
+}
+
+func TestRebuild(t *testing.T) {
+	clock := newFakeClock()
+	loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms(), clock)
 
-	// Try week 0
-	err := loan.MakePayment(NewMoney(110000), 0)
-	if err != ErrInvalidWeekNumber {
-		t.Errorf("Expected ErrInvalidWeekNumber for week 0, got %v", err)
+	steps := []func() error{
+		func() error { return loan.MakePayment(NewMoney(110000), 1) },
+		func() error { return loan.MakePartialPayment(NewMoney(50000), 2) },
+		func() error { return loan.AdvanceToWeek(3) },
+		func() error { return loan.MakePartialPayment(NewMoney(60000), 2) },
+		func() error { return loan.MakePayment(NewMoney(110000), 3) },
+		func() error { return loan.ReversePayment(3) },
+		func() error { loan.AdvanceWeek(); return nil },
+		func() error { return loan.MakePayment(NewMoney(110000), 3) },
+	}
+	for i, step := range steps {
+		clock.Advance(24 * time.Hour)
+		if err := step(); err != nil {
+			t.Fatalf("Step %d failed: %v", i+1, err)
+		}
 	}
 
-	// Try week 51
-	err = loan.MakePayment(NewMoney(110000), 51)
-	if err != ErrInvalidWeekNumber {
-		t.Errorf("Expected ErrInvalidWeekNumber for week 51, got %v", err)
+	log := loan.PaymentLog()
+	kinds := []LogEntryKind{PaymentMade, PaymentMade, WeekAdvanced, PaymentMade, PaymentMade, PaymentReversed, WeekAdvanced, PaymentMade}
+	if len(log) != len(kinds) {
+		t.Fatalf("Expected %d log entries, got %d", len(kinds), len(log))
+	}
+	for i, entry := range log {
+		if entry.Sequence != i+1 || entry.Kind != kinds[i] {
+			t.Errorf("Entry %d: expected %s with sequence %d, got %s with %d", i+1, kinds[i], i+1, entry.Kind, entry.Sequence)
+		}
+	}
+
+	rebuilt, err := loan.Rebuild(log)
+	if err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if rebuilt.CurrentWeek != loan.CurrentWeek || !rebuilt.TotalPaid().Equals(loan.TotalPaid()) || len(rebuilt.Payments) != len(loan.Payments) {
+		t.Errorf("Expected week %d with %s paid in %d payments, got week %d with %s in %d", loan.CurrentWeek, loan.TotalPaid(), len(loan.Payments), rebuilt.CurrentWeek, rebuilt.TotalPaid(), len(rebuilt.Payments))
+	}
+	for i, entry := range loan.Schedule {
+		got := rebuilt.Schedule[i]
+		if got.IsPaid != entry.IsPaid || !got.PaidAmount.Equals(entry.PaidAmount) || (got.PaidAt == nil) != (entry.PaidAt == nil) ||
+			(entry.PaidAt != nil && !got.PaidAt.Equal(*entry.PaidAt)) {
+			t.Errorf("Week %d: expected %+v, got %+v", entry.WeekNumber, entry, got)
+		}
+	}
+	if len(rebuilt.PaymentLog()) != len(log) {
+		t.Errorf("Expected the rebuilt loan to log %d entries, got %d", len(log), len(rebuilt.PaymentLog()))
+	}
+	if err := rebuilt.Validate(); err != nil {
+		t.Errorf("Expected the rebuilt loan to be valid, got %v", err)
+	}
+
+	// Out of order or inapplicable entries are rejected
+	if _, err := loan.Rebuild(log[1:]); !errors.Is(err, ErrInvalidLog) {
+		t.Errorf("Expected ErrInvalidLog for a gap in the sequence, got %v", err)
+	}
+	duplicate := append(slices.Clone(log[:1]), LogEntry{Sequence: 2, Kind: PaymentMade, Payment: log[0].Payment})
+	if _, err := loan.Rebuild(duplicate); !errors.Is(err, ErrInvalidLog) || !errors.Is(err, ErrWeekAlreadyPaid) {
+		t.Errorf("Expected ErrInvalidLog wrapping ErrWeekAlreadyPaid, got %v", err)
 	}
 }
 
-func TestMakePayment_AlreadyPaid(t *testing.T) {
-	loan := createTestLoan()
+func TestRebuild_ScheduleChanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms func(*LoanTerms)
+		steps []func(*Loan) error
+		kinds []LogEntryKind
+	}{
+		{
+			name:  "Deferral",
+			terms: func(terms *LoanTerms) { terms.MaxDeferrals = 1 },
+			steps: []func(*Loan) error{
+				func(l *Loan) error { return l.MakePayment(NewMoney(110000), 1) },
+				func(l *Loan) error { return l.MakePartialPayment(NewMoney(30000), 2) },
+				func(l *Loan) error { return l.DeferWeek(2) },
+				func(l *Loan) error { return l.AdvanceToWeek(4) },
+			},
+			kinds: []LogEntryKind{PaymentMade, PaymentMade, WeekDeferred, WeekAdvanced},
+		},
+		{
+			name: "ReduceTerm",
+			steps: []func(*Loan) error{
+				func(l *Loan) error { return l.MakePayment(NewMoney(110000), 1) },
+				func(l *Loan) error { return l.PrepayPrincipal(NewMoney(220000), ReduceTerm) },
+			},
+			kinds: []LogEntryKind{PaymentMade, PrincipalPrepaid},
+		},
+		{
+			name: "ReducePayment",
+			steps: []func(*Loan) error{
+				func(l *Loan) error { return l.MakePayment(NewMoney(110000), 1) },
+				func(l *Loan) error { return l.PrepayPrincipal(NewMoney(490000), ReducePayment) },
+				func(l *Loan) error { return l.AdvanceToWeek(2) },
+				func(l *Loan) error { return l.MakePayment(NewMoney(100000), 2) },
+			},
+			kinds: []LogEntryKind{PaymentMade, PrincipalPrepaid, WeekAdvanced, PaymentMade},
+		},
+		{
+			name: "Restructure",
+			steps: []func(*Loan) error{
+				func(l *Loan) error { return l.MakePayment(NewMoney(110000), 1) },
+				func(l *Loan) error { return l.Restructure(60, decimal.NewFromFloat(0.08)) },
+			},
+			kinds: []LogEntryKind{PaymentMade, LoanRestructured},
+		},
+		{
+			name:  "PayoffWriteOff",
+			terms: func(terms *LoanTerms) { terms.RebateMethod = Actuarial },
+			steps: []func(*Loan) error{
+				func(l *Loan) error { return l.MakePayment(NewMoney(110000), 1) },
+				func(l *Loan) error { return l.PayOff(l.PayoffAmount(l.CurrentWeek)) },
+			},
+		},
+		{
+			name:  "HeldCredit",
+			terms: func(terms *LoanTerms) { terms.OverpaymentPolicy = HoldAsCredit },
+			steps: []func(*Loan) error{
+				func(l *Loan) error { return l.MakePayment(NewMoney(150000), 1) },
+				func(l *Loan) error { return l.AdvanceToWeek(2) },
+				func(l *Loan) error { return l.MakePayment(NewMoney(70000), 2) },
+			},
+			kinds: []LogEntryKind{PaymentMade, CreditHeld, WeekAdvanced, PaymentMade, PaymentMade},
+		},
+	}
 
-	// Pay week 1
-	loan.MakePayment(NewMoney(110000), 1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := DefaultTerms()
+			if tt.terms != nil {
+				tt.terms(&terms)
+			}
+			clock := newFakeClock()
+			loan, _ := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, clock)
+			for i, step := range tt.steps {
+				clock.Advance(24 * time.Hour)
+				if err := step(loan); err != nil {
+					t.Fatalf("Step %d failed: %v", i+1, err)
+				}
+			}
 
-	// Try to pay week 1 again
-	err := loan.MakePayment(NewMoney(110000), 1)
-	if err != ErrWeekAlreadyPaid {
-		t.Errorf("Expected ErrWeekAlreadyPaid, got %v", err)
+			log := loan.PaymentLog()
+			if tt.kinds != nil {
+				got := make([]LogEntryKind, len(log))
+				for i, entry := range log {
+					got[i] = entry.Kind
+				}
+				if !slices.Equal(got, tt.kinds) {
+					t.Errorf("Expected log %v, got %v", tt.kinds, got)
+				}
+			}
+
+			// Rebuild from the current time, not the time of each entry
+			clock.Advance(30 * 24 * time.Hour)
+			rebuilt, err := loan.Rebuild(log)
+			if err != nil {
+				t.Fatalf("Rebuild failed: %v", err)
+			}
+			want, _ := json.Marshal(loan.ToDTO())
+			got, _ := json.Marshal(rebuilt.ToDTO())
+			if string(got) != string(want) {
+				t.Errorf("Expected the rebuilt loan to equal the original\nwant %s\ngot  %s", want, got)
+			}
+			if !rebuilt.GetOutstanding().Equals(loan.GetOutstanding()) || rebuilt.GetNextDueWeek() != loan.GetNextDueWeek() ||
+				!rebuilt.CreditBalance().Equals(loan.CreditBalance()) || !rebuilt.WrittenOff.Equals(loan.WrittenOff) {
+				t.Errorf("Expected outstanding %s, next due week %d, credit %s and write-off %s, got %s, %d, %s and %s",
+					loan.GetOutstanding(), loan.GetNextDueWeek(), loan.CreditBalance(), loan.WrittenOff,
+					rebuilt.GetOutstanding(), rebuilt.GetNextDueWeek(), rebuilt.CreditBalance(), rebuilt.WrittenOff)
+			}
+			if !slices.EqualFunc(rebuilt.PaymentLog(), log, func(a, b LogEntry) bool {
+				return a.Sequence == b.Sequence && a.Kind == b.Kind && a.At.Equal(b.At)
+			}) {
+				t.Errorf("Expected the rebuilt loan to log the same entries")
+			}
+			if err := rebuilt.Validate(); err != nil {
+				t.Errorf("Expected the rebuilt loan to be valid, got %v", err)
+			}
+
+			// The rebuilt loan keeps the original schedule to rebuild from
+			again, err := rebuilt.Rebuild(rebuilt.PaymentLog())
+			if err != nil {
+				t.Fatalf("Rebuild of the rebuilt loan failed: %v", err)
+			}
+			if twice, _ := json.Marshal(again.ToDTO()); string(twice) != string(want) {
+				t.Errorf("Expected rebuilding twice to give the original\nwant %s\ngot  %s", want, twice)
+			}
+		})
 	}
 }
 
-func TestMakePayment_OutOfSequence(t *testing.T) {
+func TestRebuild_WithoutOrigin(t *testing.T) {
 	loan := createTestLoan()
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.PrepayPrincipal(NewMoney(220000), ReduceTerm)
 
-	// Try to pay week 2 before week 1
-	err := loan.MakePayment(NewMoney(110000), 2)
-	if err != ErrPaymentOutOfSequence {
-		t.Errorf("Expected ErrPaymentOutOfSequence, got %v", err)
+	// A loan decoded from JSON has no record of its original schedule
+	data, _ := json.Marshal(loan)
+	decoded := &Loan{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if _, err := decoded.Rebuild(loan.PaymentLog()); !errors.Is(err, ErrInvalidLog) {
+		t.Errorf("Expected ErrInvalidLog replaying a prepayment without the original schedule, got %v", err)
 	}
+	if _, err := decoded.Rebuild(loan.PaymentLog()[:1]); err != nil {
+		t.Errorf("Expected payments to replay on the current schedule, got %v", err)
+	}
+}
 
-	// Pay week 1
+func TestLoanString(t *testing.T) {
+	loan := createTestLoan()
 	loan.MakePayment(NewMoney(110000), 1)
+	loan.SetCurrentWeek(3)
 
-	// Try to skip week 2 and pay week 3
-	err = loan.MakePayment(NewMoney(110000), 3)
-	if err != ErrPaymentOutOfSequence {
-		t.Errorf("Expected ErrPaymentOutOfSequence, got %v", err)
+	summary := loan.String()
+	for _, want := range []string{"test-loan", "test-borrower", "IDR 5390000", "week 3", "delinquent"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected summary to contain %q, got %q", want, summary)
+		}
+	}
+	if fmt.Sprint(loan) != summary {
+		t.Errorf("Expected fmt to use String, got %q", fmt.Sprint(loan))
+	}
+
+	report := loan.Describe()
+	for _, want := range []string{"Loan test-loan", "Outstanding:  IDR 5390000", "1 of 50 weeks", "W1: IDR 110000 paid", "W2: IDR 110000 missed", "W4: IDR 110000 upcoming"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+		}
 	}
 }
 
-func TestMakePayment_FullyPaid(t *testing.T) {
-	loan := createTestLoan()
+func TestDeferWeek(t *testing.T) {
+	terms := DefaultTerms()
+	terms.MaxDeferrals = 1
+	loan := createTestLoanWithTerms(terms)
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.SetCurrentWeek(3)
+	if !loan.IsDelinquent() {
+		t.Fatalf("Expected the loan to be delinquent 2 weeks behind")
+	}
 
-	// Pay all 50 weeks
-	for i := 1; i <= 50; i++ {
-		err := loan.MakePayment(NewMoney(110000), i)
-		if err != nil {
-			t.Fatalf("Failed to make payment for week %d: %v", i, err)
+	if err := loan.DeferWeek(2); err != nil {
+		t.Fatalf("DeferWeek failed: %v", err)
+	}
+
+	schedule := loan.GetSchedule()
+	if len(schedule) != 51 || loan.DurationWeeks != 51 {
+		t.Fatalf("Expected the schedule to grow to 51 weeks, got %d entries and duration %d", len(schedule), loan.DurationWeeks)
+	}
+	if week2 := schedule[1]; !week2.IsPaid || !week2.Deferred || !week2.Amount.IsZero() {
+		t.Errorf("Expected week 2 settled as deferred, got %+v", week2)
+	}
+	if last := schedule[50]; last.WeekNumber != 51 || !last.Amount.Equals(NewMoney(110000)) || !last.InterestPortion.Equals(NewMoney(10000)) {
+		t.Errorf("Expected week 51 to carry the deferred installment, got %+v", last)
+	}
+	if loan.IsDelinquent() || loan.WeeksBehind() != 1 {
+		t.Errorf("Expected 1 week behind and not delinquent after deferring, got %d", loan.WeeksBehind())
+	}
+	if !loan.GetOutstanding().Equals(NewMoney(5390000)) {
+		t.Errorf("Expected outstanding unchanged at 5390000, got %s", loan.GetOutstanding())
+	}
+	if loan.GetNextDueWeek() != 3 {
+		t.Errorf("Expected week 3 due next, got %d", loan.GetNextDueWeek())
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected a valid loan after deferring, got %v", err)
+	}
+
+	if err := loan.DeferWeek(3); !errors.Is(err, ErrDeferralLimitReached) {
+		t.Errorf("Expected ErrDeferralLimitReached, got %v", err)
+	}
+
+	t.Run("partly paid week", func(t *testing.T) {
+		loan := createTestLoanWithTerms(terms)
+		loan.MakePartialPayment(NewMoney(40000), 1)
+
+		if err := loan.DeferWeek(1); err != nil {
+			t.Fatalf("DeferWeek failed: %v", err)
 		}
+		if last := loan.GetSchedule()[50]; !last.Amount.Equals(NewMoney(70000)) {
+			t.Errorf("Expected the unpaid 70000 deferred, got %s", last.Amount)
+		}
+		if err := loan.Validate(); err != nil {
+			t.Errorf("Expected a valid loan after deferring, got %v", err)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		loan := createTestLoan()
+		if err := loan.DeferWeek(1); !errors.Is(err, ErrDeferralLimitReached) {
+			t.Errorf("Expected ErrDeferralLimitReached without MaxDeferrals, got %v", err)
+		}
+
+		loan = createTestLoanWithTerms(terms)
+		loan.MakePayment(NewMoney(110000), 1)
+		if err := loan.DeferWeek(1); !errors.Is(err, ErrWeekAlreadyPaid) {
+			t.Errorf("Expected ErrWeekAlreadyPaid, got %v", err)
+		}
+		if err := loan.DeferWeek(51); !errors.Is(err, ErrInvalidWeekNumber) {
+			t.Errorf("Expected ErrInvalidWeekNumber, got %v", err)
+		}
+	})
+}
+
+func TestProgressPercent(t *testing.T) {
+	loan := createTestLoan()
+	if !loan.ProgressPercent().IsZero() || !loan.WeeksProgressPercent().IsZero() {
+		t.Errorf("Expected 0%% progress on a new loan, got %s and %s", loan.ProgressPercent(), loan.WeeksProgressPercent())
 	}
 
-	// Verify loan is closed
-	if !loan.IsClosed() {
-		t.Error("Expected loan to be closed after all payments")
+	for week := 1; week <= 3; week++ {
+		loan.MakePayment(NewMoney(110000), week)
+	}
+	loan.MakePartialPayment(NewMoney(55000), 4)
+	// 385000 of 5500000 paid; 3 of 50 weeks paid in full
+	if want := decimal.NewFromInt(7); !loan.ProgressPercent().Equal(want) {
+		t.Errorf("Expected %s%% paid, got %s", want, loan.ProgressPercent())
+	}
+	if want := decimal.NewFromInt(6); !loan.WeeksProgressPercent().Equal(want) {
+		t.Errorf("Expected %s%% of weeks paid, got %s", want, loan.WeeksProgressPercent())
 	}
 
-	// Verify outstanding is zero
-	if !loan.GetOutstanding().IsZero() {
-		t.Errorf("Expected outstanding to be zero, got %s", loan.GetOutstanding())
+	terms := DefaultTerms()
+	terms.DurationWeeks = 3
+	loan = createTestLoanWithTerms(terms)
+	loan.MakePayment(NewMoney(1833333), 1)
+	// 1833333 of 5500000
+	if want := decimal.RequireFromString("33.33"); !loan.ProgressPercent().Equal(want) {
+		t.Errorf("Expected %s%% paid, got %s", want, loan.ProgressPercent())
+	}
+	if want := decimal.RequireFromString("33.33"); !loan.WeeksProgressPercent().Equal(want) {
+		t.Errorf("Expected %s%% of weeks paid, got %s", want, loan.WeeksProgressPercent())
 	}
 
-	// Try to make another payment
-	err := loan.MakePayment(NewMoney(110000), 1)
-	if err != ErrLoanFullyPaid {
-		t.Errorf("Expected ErrLoanFullyPaid, got %v", err)
+	loan = createTestLoan()
+	loan.PayOff(loan.GetOutstanding())
+	hundred := decimal.NewFromInt(100)
+	if !loan.IsClosed() || !loan.ProgressPercent().Equal(hundred) || !loan.WeeksProgressPercent().Equal(hundred) {
+		t.Errorf("Expected 100%% progress on a closed loan, got %s and %s", loan.ProgressPercent(), loan.WeeksProgressPercent())
 	}
 }
 
-func TestGetNextDueWeek(t *testing.T) {
+func TestGetPayment(t *testing.T) {
 	loan := createTestLoan()
+	loan.MakePaymentWithDetails(NewMoney(110000), 1, "cash", "")
+	loan.MakePartialPayment(NewMoney(60000), 2)
+	loan.MakePaymentWithDetails(NewMoney(50000), 2, "bank_transfer", "trx-2")
 
-	// Initially, week 1 should be due
-	if loan.GetNextDueWeek() != 1 {
-		t.Errorf("Expected next due week to be 1, got %d", loan.GetNextDueWeek())
+	payment, ok := loan.GetPayment(1)
+	if !ok || !payment.Amount.Equals(NewMoney(110000)) || payment.Method != "cash" {
+		t.Errorf("Expected IDR 110000 cash for week 1, got %+v, %v", payment, ok)
 	}
 
-	// After paying week 1, week 2 should be due
-	loan.MakePayment(NewMoney(110000), 1)
-	if loan.GetNextDueWeek() != 2 {
-		t.Errorf("Expected next due week to be 2, got %d", loan.GetNextDueWeek())
+	// Partial payments are combined
+	payment, ok = loan.GetPayment(2)
+	if !ok || !payment.Amount.Equals(NewMoney(110000)) || payment.Reference != "trx-2" {
+		t.Errorf("Expected IDR 110000 for week 2 with the last reference, got %+v, %v", payment, ok)
+	}
+	if payments := loan.GetPaymentsForWeek(2); len(payments) != 2 || !payments[0].Amount.Equals(NewMoney(60000)) {
+		t.Errorf("Expected both week 2 payments, got %+v", payments)
 	}
 
-	// After paying all weeks, should return 0
-	for i := 2; i <= 50; i++ {
-		loan.MakePayment(NewMoney(110000), i)
+	if _, ok := loan.GetPayment(3); ok {
+		t.Errorf("Expected no payment for unpaid week 3")
 	}
-	if loan.GetNextDueWeek() != 0 {
-		t.Errorf("Expected next due week to be 0 (all paid), got %d", loan.GetNextDueWeek())
+	if payments := loan.GetPaymentsForWeek(3); len(payments) != 0 {
+		t.Errorf("Expected no payments for unpaid week 3, got %+v", payments)
 	}
 }
 
-func TestDelinquencyScenarios(t *testing.T) {
-	t.Run("New loan in week 1 is not delinquent", func(t *testing.T) {
-		loan := createTestLoan()
-		loan.SetCurrentWeek(1)
+func TestFirstPaymentDelay(t *testing.T) {
+	terms := DefaultTerms()
+	terms.FirstPaymentDelayWeeks = 2
+	terms.DelinquencyThreshold = 1
+	clock := newFakeClock()
+	start := clock.Now()
+	loan, err := NewLoanWithClock("loan-1", "borrower-1", NewMoney(5000000), terms, clock)
+	if err != nil {
+		t.Fatalf("NewLoanWithClock failed: %v", err)
+	}
 
-		// New loan in week 1 should NOT be delinquent
-		if loan.IsDelinquent() {
-			t.Error("Expected new loan in week 1 to NOT be delinquent")
+	if due, _ := loan.DueDateForWeek(1); !due.Equal(start.AddDate(0, 0, 14)) {
+		t.Errorf("Expected week 1 due 14 days after origination, got %s", due)
+	}
+	if due, _ := loan.DueDateForWeek(2); !due.Equal(start.AddDate(0, 0, 21)) {
+		t.Errorf("Expected week 2 due 21 days after origination, got %s", due)
+	}
+
+	// Nobody is behind during the delay, even with a threshold of 1
+	for _, days := range []int{0, 7, 13} {
+		clock.now = start.AddDate(0, 0, days)
+		loan.SyncCurrentWeek()
+		if loan.CurrentWeek != 1 || loan.WeeksBehind() != 0 || loan.IsDelinquent() || loan.IsDelinquentByDate() {
+			t.Errorf("Day %d: expected week 1 and not behind, got week %d and %d behind", days, loan.CurrentWeek, loan.WeeksBehind())
 		}
-	})
+	}
 
-	t.Run("Week 3 with no payments is delinquent", func(t *testing.T) {
-		loan := createTestLoan()
+	clock.now = start.AddDate(0, 0, 22)
+	loan.SyncCurrentWeek()
+	if loan.CurrentWeek != 2 {
+		t.Errorf("Expected week 2 a day after it is due, got %d", loan.CurrentWeek)
+	}
+	if loan.WeeksBehind() != 2 || !loan.IsDelinquent() || loan.WeeksBehindByDate() != 2 {
+		t.Errorf("Expected 2 weeks behind once the delay is over, got %d and %d by date", loan.WeeksBehind(), loan.WeeksBehindByDate())
+	}
+
+	terms.FirstPaymentDelayWeeks = -1
+	if _, err := NewLoan("loan-2", "borrower-1", NewMoney(5000000), terms); !errors.Is(err, ErrInvalidFirstPaymentDelay) {
+		t.Errorf("Expected ErrInvalidFirstPaymentDelay, got %v", err)
+	}
+}
+
+func TestRequireCatchUp(t *testing.T) {
+	terms := DefaultTerms()
+	terms.RequireCatchUp = true
+	loan := createTestLoanWithTerms(terms)
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.SetCurrentWeek(4)
+
+	// Weeks 2 and 3 are past due, 3 weeks behind
+	if _, err := loan.MakeNextPayment(NewMoney(110000)); !errors.Is(err, ErrMustCatchUp) {
+		t.Errorf("Expected ErrMustCatchUp for a single installment, got %v", err)
+	}
+	if err := loan.MakePayment(NewMoney(110000), 2); !errors.Is(err, ErrMustCatchUp) {
+		t.Errorf("Expected ErrMustCatchUp from MakePayment, got %v", err)
+	}
+	if err := loan.MakePartialPayment(NewMoney(50000), 2); !errors.Is(err, ErrMustCatchUp) {
+		t.Errorf("Expected ErrMustCatchUp from MakePartialPayment, got %v", err)
+	}
+
+	var paymentErr *PaymentError
+	if _, err := loan.PayOverdue(NewMoney(110000)); !errors.As(err, &paymentErr) || !paymentErr.ExpectedAmount.Equals(NewMoney(220000)) {
+		t.Errorf("Expected a PaymentError expecting IDR 220000, got %v", err)
+	}
+
+	payments, err := loan.PayOverdue(NewMoney(220000))
+	if err != nil {
+		t.Fatalf("PayOverdue failed: %v", err)
+	}
+	if len(payments) != 2 || payments[0].WeekNumber != 2 || payments[1].WeekNumber != 3 {
+		t.Errorf("Expected payments for weeks 2 and 3, got %+v", payments)
+	}
+	if loan.IsDelinquent() || !loan.AmountPastDue().IsZero() {
+		t.Errorf("Expected the loan to be caught up, got %d weeks behind", loan.WeeksBehind())
+	}
+
+	// Caught up, single installments are accepted again
+	if week, err := loan.MakeNextPayment(NewMoney(110000)); err != nil || week != 4 {
+		t.Errorf("Expected week 4 to be paid, got %d, %v", week, err)
+	}
+	if _, err := loan.PayOverdue(NewMoney(0)); !errors.As(err, &paymentErr) || !paymentErr.ExpectedAmount.IsZero() {
+		t.Errorf("Expected a PaymentError with nothing past due, got %v", err)
+	}
+
+	t.Run("CatchUp", func(t *testing.T) {
+		loan := createTestLoanWithTerms(terms)
 		loan.SetCurrentWeek(3)
 
-		// Week 3, no payments (behind by 3) - should be delinquent
-		if !loan.IsDelinquent() {
-			t.Error("Expected to be delinquent in week 3 with no payments")
+		payments, err := loan.CatchUp()
+		if err != nil || len(payments) != 3 {
+			t.Fatalf("Expected CatchUp to pay weeks 1 to 3, got %d payments, %v", len(payments), err)
 		}
 	})
 
-	t.Run("Paid week 1, now in week 3 - delinquent", func(t *testing.T) {
-		loan := createTestLoan()
+	t.Run("OldestFirst", func(t *testing.T) {
+		terms := terms
+		terms.PaymentStrategy = OldestFirst
+		loan := createTestLoanWithTerms(terms)
 		loan.MakePayment(NewMoney(110000), 1)
-		loan.SetCurrentWeek(3)
+		loan.SetCurrentWeek(4)
 
-		// Week 3, last paid week 1 (behind by 2) - should be delinquent
-		if !loan.IsDelinquent() {
-			t.Error("Expected to be delinquent when 2 weeks behind")
+		// Checked before the amount is compared with the overdue amount
+		if _, err := loan.MakeNextPayment(NewMoneyWithCurrency(10, USD)); !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+		}
+		if _, err := loan.MakeNextPayment(NewMoney(-110000)); !errors.Is(err, ErrNegativeAmount) {
+			t.Errorf("Expected ErrNegativeAmount, got %v", err)
+		}
+		if _, err := loan.MakeNextPayment(NewMoney(110000)); !errors.Is(err, ErrMustCatchUp) {
+			t.Errorf("Expected ErrMustCatchUp for a single installment, got %v", err)
+		}
+		if week, err := loan.MakeNextPayment(NewMoney(220000)); err != nil || week != 2 {
+			t.Errorf("Expected the overdue amount to pay from week 2, got %d, %v", week, err)
 		}
 	})
 
-	t.Run("Paid weeks 1-2, now in week 3 - not delinquent", func(t *testing.T) {
+	t.Run("without RequireCatchUp", func(t *testing.T) {
 		loan := createTestLoan()
-		loan.MakePayment(NewMoney(110000), 1)
-		loan.MakePayment(NewMoney(110000), 2)
-		loan.SetCurrentWeek(3)
-
-		// Week 3, last paid week 2 (behind by 1) - should NOT be delinquent
-		if loan.IsDelinquent() {
-			t.Error("Expected to NOT be delinquent when only 1 week behind")
+		loan.SetCurrentWeek(4)
+		if _, err := loan.MakeNextPayment(NewMoney(110000)); err != nil {
+			t.Errorf("Expected a single installment to be accepted, got %v", err)
 		}
 	})
+}
 
-	t.Run("Customer catches up from delinquency", func(t *testing.T) {
-		loan := createTestLoan()
+func TestFlatInterestCalculator(t *testing.T) {
+	tests := []struct {
+		principal Money
+		weeks     int
+		total     Money
+		first     Money
+		last      Money
+	}{
+		{NewMoney(5000000), 50, NewMoney(5500000), NewMoney(110000), NewMoney(110000)},
+		{NewMoney(5000001), 50, NewMoney(5500001), NewMoney(110000), NewMoney(110001)},
+		{NewMoney(1000000), 3, NewMoney(1100000), NewMoney(366666), NewMoney(366668)},
+	}
 
-		// Start at week 3, paid only week 1 (delinquent)
-		loan.MakePayment(NewMoney(110000), 1)
-		loan.SetCurrentWeek(3)
-		if !loan.IsDelinquent() {
-			t.Error("Expected to be delinquent (2 weeks behind)")
+	calculator := FlatInterestCalculator{}
+	rate := decimal.NewFromFloat(0.10)
+	for _, tt := range tests {
+		total := calculator.ComputeTotal(tt.principal, rate, tt.weeks)
+		if !total.Equals(tt.total) {
+			t.Errorf("%s over %d weeks: expected total %s, got %s", tt.principal, tt.weeks, tt.total, total)
 		}
 
-		// Pay week 2 to catch up
-		loan.MakePayment(NewMoney(110000), 2)
-		// Still in week 3, now only 1 week behind
-		if loan.IsDelinquent() {
-			t.Error("Expected to NOT be delinquent after catching up (1 week behind)")
+		schedule := calculator.BuildSchedule(tt.principal, rate, tt.weeks)
+		if len(schedule) != tt.weeks || !schedule[0].Amount.Equals(tt.first) || !schedule[tt.weeks-1].Amount.Equals(tt.last) {
+			t.Errorf("%s over %d weeks: expected installments %s to %s, got %+v", tt.principal, tt.weeks, tt.first, tt.last, schedule)
 		}
-	})
+		if !sumSchedule(schedule).Equals(total) {
+			t.Errorf("%s over %d weeks: expected the schedule to sum to %s, got %s", tt.principal, tt.weeks, total, sumSchedule(schedule))
+		}
+
+		// NewLoan defaults to the flat calculator
+		terms := DefaultTerms()
+		terms.DurationWeeks = tt.weeks
+		loan, _ := NewLoan("loan-1", "borrower-1", tt.principal, terms)
+		if !loan.TotalAmount.Equals(total) {
+			t.Errorf("%s over %d weeks: expected NewLoan's total to match the flat calculator, got %s", tt.principal, tt.weeks, loan.TotalAmount)
+		}
+		for i, entry := range loan.GetSchedule() {
+			if !entry.Amount.Equals(schedule[i].Amount) || !entry.InterestPortion.Equals(schedule[i].InterestPortion) {
+				t.Errorf("%s over %d weeks: expected NewLoan's week %d to match the flat calculator, got %+v", tt.principal, tt.weeks, entry.WeekNumber, entry)
+			}
+		}
+	}
 }
 
-// Helper functions
-func createTestLoan() *Loan {
-	principal := NewMoney(5000000)
-	interestRate := decimal.NewFromFloat(0.10)
-	return NewLoan("test-loan", "test-borrower", principal, interestRate)
+// fixedFeeCalculator charges a fixed fee per week, to test custom calculators
+type fixedFeeCalculator struct {
+	fee Money
 }
 
-func makeRange(min, max int) []int {
-	result := make([]int, max-min+1)
-	for i := range result {
-		result[i] = min + i
+func (c fixedFeeCalculator) ComputeTotal(principal Money, _ decimal.Decimal, weeks int) Money {
+	return sumSchedule(c.BuildSchedule(principal, decimal.Zero, weeks))
+}
+
+func (c fixedFeeCalculator) BuildSchedule(principal Money, _ decimal.Decimal, weeks int) []ScheduleEntry {
+	parts, _ := principal.Split(weeks)
+	schedule := make([]ScheduleEntry, weeks)
+	for i, part := range parts {
+		schedule[i] = ScheduleEntry{Amount: part.Add(c.fee), PrincipalPortion: part, InterestPortion: c.fee}
+	}
+	return schedule
+}
+
+func TestCustomInterestCalculator(t *testing.T) {
+	terms := DefaultTerms()
+	terms.DurationWeeks = 10
+	terms.InterestCalculator = fixedFeeCalculator{fee: NewMoney(1000)}
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoney(1000000), terms)
+	if err != nil {
+		t.Fatalf("NewLoan failed: %v", err)
+	}
+
+	if !loan.TotalAmount.Equals(NewMoney(1010000)) || !loan.WeeklyPayment.Equals(NewMoney(101000)) {
+		t.Errorf("Expected total IDR 1010000 in installments of 101000, got %s and %s", loan.TotalAmount, loan.WeeklyPayment)
+	}
+	if entry := loan.GetSchedule()[9]; entry.WeekNumber != 10 || !entry.PaidAmount.IsZero() {
+		t.Errorf("Expected week 10 numbered and unpaid, got %+v", entry)
+	}
+
+	// Restructuring keeps the calculator
+	loan.MakePayment(NewMoney(101000), 1)
+	if err := loan.Restructure(3, decimal.Zero); err != nil {
+		t.Fatalf("Restructure failed: %v", err)
+	}
+	// 909000 outstanding repaid as 303000 of principal plus the fee
+	if !loan.WeeklyPayment.Equals(NewMoney(304000)) {
+		t.Errorf("Expected restructured installments of 304000, got %s", loan.WeeklyPayment)
+	}
+	if err := loan.Validate(); err != nil {
+		t.Errorf("Expected a valid loan, got %v", err)
 	}
-	return result
 }
@@ -1,31 +1,99 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
 
+// Currency is an ISO 4217 currency code
+type Currency string
+
+const (
+	IDR Currency = "IDR"
+	USD Currency = "USD"
+
+	// DefaultCurrency is used by NewMoney and by Money values without a currency
+	DefaultCurrency = IDR
+)
+
+// DecimalPlaces returns the number of minor unit digits of the currency:
+// 0 for IDR, 2 for USD and for currencies not listed here
+func (c Currency) DecimalPlaces() int32 {
+	switch c {
+	case IDR:
+		return 0
+	default:
+		return 2
+	}
+}
+
+// Money is an amount in a single currency. Combining or comparing amounts in
+// different currencies panics with ErrCurrencyMismatch
 type Money struct {
-	amount decimal.Decimal
+	amount   decimal.Decimal
+	currency Currency
 }
 
-// NewMoney creates a new Money instance from an int64 value
+// NewMoney creates a new Money instance from an int64 value in DefaultCurrency
 func NewMoney(amount int64) Money {
-	return Money{
-		amount: decimal.NewFromInt(amount),
-	}
+	return NewMoneyWithCurrency(amount, DefaultCurrency)
 }
 
-// NewMoneyFromDecimal creates Money from a decimal value
+// NewMoneyWithCurrency creates Money from an int64 value in currency
+func NewMoneyWithCurrency(amount int64, currency Currency) Money {
+	return NewMoneyFromDecimalWithCurrency(decimal.NewFromInt(amount), currency)
+}
+
+// NewMoneyFromDecimal creates Money from a decimal value in DefaultCurrency
 func NewMoneyFromDecimal(amount decimal.Decimal) Money {
-	return Money{amount: amount}
+	return NewMoneyFromDecimalWithCurrency(amount, DefaultCurrency)
+}
+
+// NewMoneyFromDecimalWithCurrency creates Money from a decimal value in currency
+func NewMoneyFromDecimalWithCurrency(amount decimal.Decimal, currency Currency) Money {
+	return Money{amount: amount, currency: currency}
+}
+
+// NewMoneyFromString parses user or API input such as "110000" or
+// "110000.00" into Money in DefaultCurrency. Surrounding whitespace is ignored
+func NewMoneyFromString(s string) (Money, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return Money{}, fmt.Errorf("%w: empty amount", ErrInvalidMoneyFormat)
+	}
+
+	amount, err := decimal.NewFromString(trimmed)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w %q: %w", ErrInvalidMoneyFormat, s, err)
+	}
+
+	return NewMoneyFromDecimal(amount), nil
 }
 
 func (m Money) Amount() decimal.Decimal {
 	return m.amount
 }
 
+// Currency returns the currency of the amount, DefaultCurrency if none was set
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// mustMatch panics when other is in a different currency than m
+func (m Money) mustMatch(other Money) {
+	if m.Currency() != other.Currency() {
+		panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), other.Currency()))
+	}
+}
+
 func (m Money) IsZero() bool {
 	return m.amount.IsZero()
 }
@@ -35,33 +103,166 @@ func (m Money) IsNegative() bool {
 }
 
 func (m Money) Equals(other Money) bool {
+	m.mustMatch(other)
 	return m.amount.Equal(other.amount)
 }
 
 func (m Money) Add(other Money) Money {
-	return Money{amount: m.amount.Add(other.amount)}
+	m.mustMatch(other)
+	return Money{amount: m.amount.Add(other.amount), currency: m.currency}
 }
 
 func (m Money) Subtract(other Money) Money {
-	return Money{amount: m.amount.Sub(other.amount)}
+	m.mustMatch(other)
+	return Money{amount: m.amount.Sub(other.amount), currency: m.currency}
 }
 
 func (m Money) Multiply(multiplier decimal.Decimal) Money {
-	return Money{amount: m.amount.Mul(multiplier)}
+	return Money{amount: m.amount.Mul(multiplier), currency: m.currency}
+}
+
+// Divide returns m / divisor rounded to places decimal places, with halves
+// rounded away from zero (decimal.DivRound): 2/3 at 0 places is 1, -2/3 is -1.
+// It panics if divisor is zero
+func (m Money) Divide(divisor decimal.Decimal, places int32) Money {
+	return Money{amount: m.amount.DivRound(divisor, places), currency: m.currency}
+}
+
+// DivideDown returns m / divisor rounded down (towards negative infinity) to
+// places decimal places, so the result never exceeds the exact quotient. It is
+// exact however many digits the quotient has. It panics if divisor is zero
+func (m Money) DivideDown(divisor decimal.Decimal, places int32) Money {
+	quotient, remainder := m.amount.QuoRem(divisor, places)
+	if !remainder.IsZero() && remainder.Sign() != divisor.Sign() {
+		quotient = quotient.Sub(decimal.New(1, -places))
+	}
+	return Money{amount: quotient, currency: m.currency}
+}
+
+// Split divides m into n parts that sum exactly to m. Every part is m / n
+// rounded down to the currency's minor units except the last, which absorbs
+// the remainder: IDR 100 into 3 parts is 33, 33 and 34, USD 100 is 33.33,
+// 33.33 and 33.34. n must be at least 1
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, ErrInvalidSplit
+	}
+
+	part := m.DivideDown(decimal.NewFromInt(int64(n)), m.Currency().DecimalPlaces())
+	parts := make([]Money, n)
+	for i := range n - 1 {
+		parts[i] = part
+	}
+	parts[n-1] = m.Subtract(part.Multiply(decimal.NewFromInt(int64(n - 1))))
+	return parts, nil
+}
+
+// Round returns m rounded to places decimal places, halves away from zero
+func (m Money) Round(places int32) Money {
+	return Money{amount: m.amount.Round(places), currency: m.currency}
+}
+
+// RoundToCurrency rounds m to its currency's minor units, e.g. IDR 110000.004
+// to IDR 110000, so amounts can be compared with Equals without trailing
+// precision getting in the way
+func (m Money) RoundToCurrency() Money {
+	return m.Round(m.Currency().DecimalPlaces())
+}
+
+// Percentage returns m as a percentage of whole, e.g. 500 of 5000 is 10.
+// It returns zero when whole is zero and panics if the currencies differ
+func (m Money) Percentage(whole Money) decimal.Decimal {
+	m.mustMatch(whole)
+	if whole.amount.IsZero() {
+		return decimal.Zero
+	}
+	return m.amount.Div(whole.amount).Mul(decimal.NewFromInt(100))
 }
 
 func (m Money) GreaterThan(other Money) bool {
+	m.mustMatch(other)
 	return m.amount.GreaterThan(other.amount)
 }
 
 func (m Money) LessThan(other Money) bool {
+	m.mustMatch(other)
 	return m.amount.LessThan(other.amount)
 }
 
 func (m Money) String() string {
-	return fmt.Sprintf("IDR %s", m.amount.StringFixed(0))
+	return fmt.Sprintf("%s %s", m.Currency(), m.amount.StringFixed(m.Currency().DecimalPlaces()))
 }
 
 func (m Money) Int64() int64 {
 	return m.amount.IntPart()
 }
+
+// moneyJSON is the JSON encoding of Money
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency Currency        `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string, so no precision is lost
+// to float64, together with the currency: {"amount":"10.50","currency":"USD"}.
+// The amount shows at least the currency's minor units
+func (m Money) MarshalJSON() ([]byte, error) {
+	places := m.Currency().DecimalPlaces()
+	amount := m.amount.String()
+	if -m.amount.Exponent() <= places {
+		amount = m.amount.StringFixed(places)
+	}
+	return json.Marshal(moneyJSON{
+		Amount:   json.RawMessage(strconv.Quote(amount)),
+		Currency: m.Currency(),
+	})
+}
+
+// UnmarshalJSON accepts the object written by MarshalJSON, restoring its
+// currency (DefaultCurrency when omitted). A bare quoted decimal string or raw
+// JSON number is also accepted for the amount alone; the currency is then
+// left unchanged, DefaultCurrency for a zero Money
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := bytes.TrimSpace(data)
+	if len(raw) == 0 || raw[0] != '{' {
+		amount, err := parseJSONAmount(raw)
+		if err != nil {
+			return err
+		}
+		m.amount = amount
+		return nil
+	}
+
+	var encoded moneyJSON
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMoneyFormat, err)
+	}
+	if encoded.Amount == nil {
+		return fmt.Errorf("%w: missing amount", ErrInvalidMoneyFormat)
+	}
+	amount, err := parseJSONAmount(encoded.Amount)
+	if err != nil {
+		return err
+	}
+
+	m.amount = amount
+	m.currency = encoded.Currency
+	if m.currency == "" {
+		m.currency = DefaultCurrency
+	}
+	return nil
+}
+
+// parseJSONAmount parses a quoted decimal string or a raw JSON number
+func parseJSONAmount(raw []byte) (decimal.Decimal, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+		raw = raw[1 : len(raw)-1]
+	}
+
+	parsed, err := NewMoneyFromString(string(raw))
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return parsed.amount, nil
+}
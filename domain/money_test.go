@@ -0,0 +1,422 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoneyCurrency(t *testing.T) {
+	if c := NewMoney(100).Currency(); c != IDR {
+		t.Errorf("Expected NewMoney to default to IDR, got %s", c)
+	}
+	if c := (Money{}).Currency(); c != DefaultCurrency {
+		t.Errorf("Expected zero Money in %s, got %s", DefaultCurrency, c)
+	}
+
+	usd := NewMoneyWithCurrency(250, USD)
+	if s := usd.String(); s != "USD 250.00" {
+		t.Errorf("Expected \"USD 250.00\", got %q", s)
+	}
+	if s := NewMoneyFromDecimalWithCurrency(decimal.RequireFromString("10.5"), USD).String(); s != "USD 10.50" {
+		t.Errorf("Expected \"USD 10.50\", got %q", s)
+	}
+	if sum := usd.Add(NewMoneyWithCurrency(50, USD)); sum.Currency() != USD || sum.Int64() != 300 {
+		t.Errorf("Expected USD 300, got %s", sum)
+	}
+	if product := usd.Multiply(decimal.NewFromInt(2)); product.Currency() != USD {
+		t.Errorf("Expected Multiply to keep USD, got %s", product.Currency())
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd := NewMoneyWithCurrency(100, USD)
+	idr := NewMoney(100)
+
+	operations := map[string]func(){
+		"Add":         func() { usd.Add(idr) },
+		"Subtract":    func() { usd.Subtract(idr) },
+		"Equals":      func() { usd.Equals(idr) },
+		"GreaterThan": func() { usd.GreaterThan(idr) },
+		"LessThan":    func() { usd.LessThan(idr) },
+	}
+
+	for name, operation := range operations {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrCurrencyMismatch) {
+					t.Errorf("Expected panic with ErrCurrencyMismatch, got %v", err)
+				}
+			}()
+			operation()
+		})
+	}
+}
+
+func TestMoneyDivide(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		divisor  int64
+		places   int32
+		expected string
+		down     string
+	}{
+		{"Exact", "5500000", 50, 0, "110000", "110000"},
+		{"Repeating, rounds down", "100", 3, 2, "33.33", "33.33"},
+		{"Repeating, rounds up", "200", 3, 2, "66.67", "66.66"},
+		{"Whole units", "5500001", 50, 0, "110000", "110000"},
+		{"Half rounds away from zero", "1", 2, 0, "1", "0"},
+		{"Negative rounds away from zero", "-200", 3, 2, "-66.67", "-66.67"},
+		{"Negative half", "-1", 2, 0, "-1", "-1"},
+		{"Negative, nearest is towards zero", "-100", 3, 2, "-33.33", "-33.34"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMoneyFromDecimalWithCurrency(decimal.RequireFromString(tt.amount), USD)
+			divisor := decimal.NewFromInt(tt.divisor)
+
+			got := m.Divide(divisor, tt.places)
+			if !got.Amount().Equal(decimal.RequireFromString(tt.expected)) || got.Currency() != USD {
+				t.Errorf("Divide: expected USD %s, got %s %s", tt.expected, got.Currency(), got.Amount())
+			}
+
+			down := m.DivideDown(divisor, tt.places)
+			if !down.Amount().Equal(decimal.RequireFromString(tt.down)) || down.Currency() != USD {
+				t.Errorf("DivideDown: expected USD %s, got %s %s", tt.down, down.Currency(), down.Amount())
+			}
+		})
+	}
+}
+
+func TestMoneyDivideDown_Precise(t *testing.T) {
+	// The quotient is just below 1 beyond decimal's default division precision
+	m := NewMoneyFromDecimal(decimal.RequireFromString("99999999999999999999"))
+	divisor := decimal.RequireFromString("100000000000000000000")
+
+	if got := m.DivideDown(divisor, 0); !got.IsZero() {
+		t.Errorf("Expected 0, got %s", got.Amount())
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency Currency
+		expected string
+	}{
+		{"110000.004", IDR, "110000"},
+		{"110000.5", IDR, "110001"},
+		{"-110000.5", IDR, "-110001"},
+		{"110.004", USD, "110"},
+		{"110.005", USD, "110.01"},
+	}
+
+	for _, tt := range tests {
+		m := NewMoneyFromDecimalWithCurrency(decimal.RequireFromString(tt.amount), tt.currency)
+		got := m.RoundToCurrency()
+		if !got.Equals(NewMoneyFromDecimalWithCurrency(decimal.RequireFromString(tt.expected), tt.currency)) {
+			t.Errorf("%s %s: expected %s, got %s", tt.currency, tt.amount, tt.expected, got.Amount())
+		}
+	}
+
+	// Trailing precision no longer breaks Equals once rounded
+	m := NewMoneyFromDecimal(decimal.RequireFromString("110000.004"))
+	if m.Equals(NewMoney(110000)) || !m.RoundToCurrency().Equals(NewMoney(110000)) {
+		t.Errorf("Expected only the rounded amount to equal IDR 110000")
+	}
+	if got := m.Round(2); !got.Amount().Equal(decimal.RequireFromString("110000")) {
+		t.Errorf("Expected Round(2) to give 110000.00, got %s", got.Amount())
+	}
+}
+
+func TestMoneyPercentage(t *testing.T) {
+	if got := NewMoney(500).Percentage(NewMoney(5000)); !got.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Expected 10, got %s", got)
+	}
+	if got := NewMoney(1).Percentage(NewMoney(3)).Round(2); !got.Equal(decimal.RequireFromString("33.33")) {
+		t.Errorf("Expected 33.33, got %s", got)
+	}
+	if got := NewMoney(500).Percentage(NewMoney(0)); !got.IsZero() {
+		t.Errorf("Expected 0 for a zero whole, got %s", got)
+	}
+}
+
+func TestMoneySplit(t *testing.T) {
+	cents := func(s string) Money { return NewMoneyFromDecimalWithCurrency(decimal.RequireFromString(s), USD) }
+
+	tests := []struct {
+		name   string
+		amount Money
+		n      int
+		first  Money
+		last   Money
+	}{
+		{"IDR in whole units", NewMoney(5500001), 50, NewMoney(110000), NewMoney(110001)},
+		{"USD in cents", NewMoneyWithCurrency(100, USD), 3, cents("33.33"), cents("33.34")},
+		{"USD with a cent remainder", cents("5500.01"), 50, cents("110.00"), cents("110.01")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := tt.amount.Split(tt.n)
+			if err != nil {
+				t.Fatalf("Split failed: %v", err)
+			}
+
+			sum := NewMoneyWithCurrency(0, tt.amount.Currency())
+			for _, part := range parts {
+				sum = sum.Add(part)
+			}
+			if len(parts) != tt.n || !sum.Equals(tt.amount) {
+				t.Errorf("Expected %d parts summing to %s, got %d summing to %s", tt.n, tt.amount, len(parts), sum)
+			}
+			if !parts[0].Equals(tt.first) || !parts[tt.n-1].Equals(tt.last) {
+				t.Errorf("Expected %s with the remainder in the last part %s, got %s and %s", tt.first, tt.last, parts[0], parts[tt.n-1])
+			}
+		})
+	}
+
+	amount := NewMoney(100)
+
+	for _, n := range []int{0, -1} {
+		if _, err := amount.Split(n); !errors.Is(err, ErrInvalidSplit) {
+			t.Errorf("Split(%d): expected ErrInvalidSplit, got %v", n, err)
+		}
+	}
+}
+
+func TestNewMoneyFromString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Money
+	}{
+		{"110000", NewMoney(110000)},
+		{" 110000 ", NewMoney(110000)},
+		{"110000.00", NewMoney(110000)},
+		{"110000.25", NewMoneyFromDecimal(decimal.RequireFromString("110000.25"))},
+		{"-500", NewMoney(-500)},
+	}
+
+	for _, tt := range tests {
+		m, err := NewMoneyFromString(tt.input)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.input, err)
+		}
+		if !m.Equals(tt.expected) {
+			t.Errorf("Expected %s from %q, got %s", tt.expected, tt.input, m)
+		}
+	}
+}
+
+func TestNewMoneyFromString_Malformed(t *testing.T) {
+	for _, input := range []string{"", "   ", "abc", "12.3.4", "1,000", "IDR 100"} {
+		if m, err := NewMoneyFromString(input); !errors.Is(err, ErrInvalidMoneyFormat) {
+			t.Errorf("Expected ErrInvalidMoneyFormat parsing %q, got %s (err %v)", input, m, err)
+		}
+	}
+}
+
+func TestMoneyMarshalJSON(t *testing.T) {
+	tests := []struct {
+		money    Money
+		expected string
+	}{
+		{NewMoney(5000000), `{"amount":"5000000","currency":"IDR"}`},
+		{NewMoneyFromDecimal(decimal.RequireFromString("110000.25")), `{"amount":"110000.25","currency":"IDR"}`},
+		{NewMoney(-500), `{"amount":"-500","currency":"IDR"}`},
+		{NewMoneyFromDecimalWithCurrency(decimal.RequireFromString("10.5"), USD), `{"amount":"10.50","currency":"USD"}`},
+		{NewMoneyFromDecimalWithCurrency(decimal.RequireFromString("0.125"), USD), `{"amount":"0.125","currency":"USD"}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.money)
+		if err != nil {
+			t.Fatalf("Failed to marshal %s: %v", tt.money, err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, data)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Money
+	}{
+		{`"5000000"`, NewMoney(5000000)},
+		{`"110000.25"`, NewMoneyFromDecimal(decimal.RequireFromString("110000.25"))},
+		{`110000`, NewMoney(110000)},
+		{`110000.25`, NewMoneyFromDecimal(decimal.RequireFromString("110000.25"))},
+		{`{"amount":"110000","currency":"IDR"}`, NewMoney(110000)},
+		{`{"amount":110000}`, NewMoney(110000)},
+		{`{"amount":"10.50","currency":"USD"}`, NewMoneyFromDecimalWithCurrency(decimal.RequireFromString("10.50"), USD)},
+	}
+
+	for _, tt := range tests {
+		var m Money
+		if err := json.Unmarshal([]byte(tt.input), &m); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", tt.input, err)
+		}
+		if !m.Equals(tt.expected) {
+			t.Errorf("Expected %s from %s, got %s", tt.expected, tt.input, m)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON_Malformed(t *testing.T) {
+	for _, input := range []string{`"abc"`, `""`, `true`, `{}`, `"12.3.4"`, `{"amount":"abc"}`, `{"currency":"USD"}`, `{"amount":"1","currency":5}`} {
+		var m Money
+		if err := json.Unmarshal([]byte(input), &m); err == nil {
+			t.Errorf("Expected error unmarshaling %s, got %s", input, m)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip_USD(t *testing.T) {
+	original := NewMoneyFromDecimalWithCurrency(decimal.RequireFromString("10.50"), USD)
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Failed to marshal %s: %v", original, err)
+	}
+	var decoded Money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal %s: %v", data, err)
+	}
+
+	if decoded.Currency() != USD || !decoded.Equals(original) {
+		t.Errorf("Expected %s after a round trip, got %s", original, decoded)
+	}
+
+	// Arithmetic with other USD amounts works on the decoded value
+	if sum := decoded.Add(NewMoneyWithCurrency(1, USD)); sum.String() != "USD 11.50" {
+		t.Errorf("Expected USD 11.50, got %s", sum)
+	}
+}
+
+func TestLoanJSONRoundTrip(t *testing.T) {
+	principal := NewMoneyFromDecimal(decimal.RequireFromString("5000001.37"))
+	loan, err := NewLoan("loan-1", "borrower-1", principal, DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	loan.MakePayment(loan.Schedule[0].Amount, 1)
+
+	data, err := json.Marshal(loan)
+	if err != nil {
+		t.Fatalf("Failed to marshal loan: %v", err)
+	}
+
+	var decoded Loan
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal loan: %v", err)
+	}
+
+	if !decoded.Principal.Equals(loan.Principal) {
+		t.Errorf("Expected principal %s, got %s", loan.Principal.Amount(), decoded.Principal.Amount())
+	}
+	if !decoded.TotalAmount.Equals(loan.TotalAmount) {
+		t.Errorf("Expected total amount %s, got %s", loan.TotalAmount.Amount(), decoded.TotalAmount.Amount())
+	}
+	if !decoded.WeeklyPayment.Equals(loan.WeeklyPayment) {
+		t.Errorf("Expected weekly payment %s, got %s", loan.WeeklyPayment.Amount(), decoded.WeeklyPayment.Amount())
+	}
+	if !decoded.GetOutstanding().Equals(loan.GetOutstanding()) {
+		t.Errorf("Expected outstanding %s, got %s", loan.GetOutstanding().Amount(), decoded.GetOutstanding().Amount())
+	}
+}
+
+func TestLoanToDTO_JSON(t *testing.T) {
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoney(5000000), DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	loan.MakePayment(NewMoney(110000), 1)
+	loan.SetCurrentWeek(4)
+
+	data, err := json.Marshal(loan.ToDTO())
+	if err != nil {
+		t.Fatalf("Failed to marshal DTO: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Failed to unmarshal DTO: %v", err)
+	}
+
+	keys := []string{"id", "borrower_id", "currency", "principal", "total_amount", "weekly_payment", "outstanding", "current_week", "next_due_week", "is_delinquent", "schedule"}
+	if len(fields) != len(keys) {
+		t.Errorf("Expected %d keys, got %d: %s", len(keys), len(fields), data)
+	}
+	for _, key := range keys {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Missing key %q in %s", key, data)
+		}
+	}
+
+	amounts := map[string]string{"principal": "5000000", "total_amount": "5500000", "weekly_payment": "110000", "outstanding": "5390000"}
+	for key, want := range amounts {
+		if got, ok := fields[key].(map[string]any); !ok || got["amount"] != want || got["currency"] != "IDR" {
+			t.Errorf("Expected %s to be the decimal string %q in IDR, got %#v", key, want, fields[key])
+		}
+	}
+
+	if fields["currency"] != "IDR" {
+		t.Errorf("Expected currency IDR, got %v", fields["currency"])
+	}
+	if fields["next_due_week"] != float64(2) || fields["current_week"] != float64(4) || fields["is_delinquent"] != true {
+		t.Errorf("Unexpected computed fields in %s", data)
+	}
+
+	schedule, _ := fields["schedule"].([]any)
+	if len(schedule) != 50 {
+		t.Fatalf("Expected 50 schedule entries, got %d", len(schedule))
+	}
+	first, _ := schedule[0].(map[string]any)
+	if amount, _ := first["amount"].(map[string]any); amount["amount"] != "110000" || first["is_paid"] != true || first["paid_at"] == nil {
+		t.Errorf("Unexpected first schedule entry %v", first)
+	}
+	if second, _ := schedule[1].(map[string]any); second["paid_at"] != nil {
+		t.Errorf("Expected paid_at omitted for an unpaid week, got %v", second)
+	}
+}
+
+func TestLoanToDTO_RoundTripUSD(t *testing.T) {
+	loan, err := NewLoan("loan-1", "borrower-1", NewMoneyFromDecimalWithCurrency(decimal.RequireFromString("1000.50"), USD), DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	loan.MakePayment(loan.Schedule[0].Amount, 1)
+	dto := loan.ToDTO()
+
+	data, err := json.Marshal(dto)
+	if err != nil {
+		t.Fatalf("Failed to marshal DTO: %v", err)
+	}
+	var decoded LoanDTO
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal DTO: %v", err)
+	}
+
+	if decoded.Currency != USD {
+		t.Errorf("Expected currency USD, got %s", decoded.Currency)
+	}
+	amounts := map[string][2]Money{
+		"principal":      {dto.Principal, decoded.Principal},
+		"total amount":   {dto.TotalAmount, decoded.TotalAmount},
+		"weekly payment": {dto.WeeklyPayment, decoded.WeeklyPayment},
+		"outstanding":    {dto.Outstanding, decoded.Outstanding},
+		"week 1 paid":    {dto.Schedule[0].PaidAmount, decoded.Schedule[0].PaidAmount},
+	}
+	for name, pair := range amounts {
+		if pair[1].Currency() != USD || !pair[1].Equals(pair[0]) {
+			t.Errorf("Expected %s %s after a round trip, got %s", name, pair[0], pair[1])
+		}
+	}
+}
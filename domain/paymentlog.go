@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LogEntryKind is the type of state change a LogEntry records
+type LogEntryKind int
+
+const (
+	// PaymentMade records a payment applied to a week, including payments
+	// from the credit balance and those loaded by LoadPayments
+	PaymentMade LogEntryKind = iota
+
+	// PaymentReversed records ReversePayment undoing every payment for a week,
+	// returning any paid from the credit balance to it
+	PaymentReversed
+
+	// WeekAdvanced records the current week changing, usually forward
+	WeekAdvanced
+
+	// WeekDeferred records DeferWeek moving a week to the end of the loan
+	WeekDeferred
+
+	// PrincipalPrepaid records a PrepayPrincipal payment and its PrepayMode
+	PrincipalPrepaid
+
+	// LoanRestructured records Restructure rebuilding the unpaid schedule
+	LoanRestructured
+
+	// CreditHeld records an overpayment excess added to the credit balance
+	// under HoldAsCredit
+	CreditHeld
+
+	// RebateWrittenOff records PayOff writing off the interest rebate of a
+	// loan settled at its PayoffAmount
+	RebateWrittenOff
+)
+
+func (k LogEntryKind) String() string {
+	switch k {
+	case PaymentMade:
+		return "payment_made"
+	case PaymentReversed:
+		return "payment_reversed"
+	case WeekAdvanced:
+		return "week_advanced"
+	case WeekDeferred:
+		return "week_deferred"
+	case PrincipalPrepaid:
+		return "principal_prepaid"
+	case LoanRestructured:
+		return "loan_restructured"
+	case CreditHeld:
+		return "credit_held"
+	case RebateWrittenOff:
+		return "rebate_written_off"
+	default:
+		return "unknown"
+	}
+}
+
+// LogEntry is one state change in a loan's PaymentLog
+type LogEntry struct {
+	Sequence      int // 1 for the first entry, increasing by one per entry
+	Kind          LogEntryKind
+	At            time.Time       // When the change was made, from the loan's clock
+	Payment       Payment         // The payment applied, for PaymentMade and PrincipalPrepaid
+	WeekNumber    int             // The reversed week for PaymentReversed, the new current week for WeekAdvanced, the deferred week for WeekDeferred
+	PrepayMode    PrepayMode      // For PrincipalPrepaid
+	DurationWeeks int             // The new duration for LoanRestructured
+	InterestRate  decimal.Decimal // The new annual rate for LoanRestructured
+	Amount        Money           // The amount held for CreditHeld, returned to the credit balance for PaymentReversed, written off for RebateWrittenOff
+}
+
+// PaymentLog returns every change made to the loan since it was created, in
+// order: payments, reversals, current week changes, deferrals, prepayments,
+// restructures, held credit and write-offs
+func (l *Loan) PaymentLog() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Clone(l.log)
+}
+
+// record numbers entry, stamps it with the current time and appends it to the
+// loan's PaymentLog
+func (l *Loan) record(entry LogEntry) {
+	entry.Sequence = len(l.log) + 1
+	entry.At = l.now()
+	l.log = append(l.log, entry)
+}
+
+// fixedClock is a Clock stopped at a single instant, used to replay a
+// LogEntry at the time it was recorded
+type fixedClock time.Time
+
+// Now returns the instant the clock is stopped at
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// Rebuild returns a copy of the loan reconstructed by replaying log onto the
+// loan as it was created. The loan's own state isn't used, so replaying its
+// PaymentLog gives back an equal loan; only Suspended and Archived, which
+// aren't logged, are carried over. Each entry is replayed at the time it was
+// recorded. Entries must be numbered 1, 2, 3... and apply as recorded;
+// otherwise an error wrapping ErrInvalidLog and the entry's failure is
+// returned.
+//
+// Loans that weren't built by NewLoan (e.g. decoded ones) have no record of
+// their original schedule and are rebuilt on an unpaid copy of the current
+// one, so a log that changes the schedule is rejected for them
+func (l *Loan) Rebuild(log []LogEntry) (*Loan, error) {
+	var rebuilt *Loan
+	if l.origin != nil {
+		rebuilt = l.origin.clone()
+		rebuilt.origin = l.origin
+		rebuilt.clock = l.clock
+		rebuilt.Suspended = l.Suspended
+		rebuilt.Archived = false
+	} else {
+		rebuilt = l.unpaidCopy()
+	}
+	rebuilt.log = nil
+
+	clock := rebuilt.clock
+	for i, entry := range log {
+		if entry.Sequence != i+1 {
+			return nil, fmt.Errorf("%w: entry %d has sequence number %d", ErrInvalidLog, i+1, entry.Sequence)
+		}
+		if l.origin == nil && changesSchedule(entry.Kind) {
+			return nil, fmt.Errorf("%w: entry %d: %s can't be replayed without the original schedule", ErrInvalidLog, entry.Sequence, entry.Kind)
+		}
+		rebuilt.clock = fixedClock(entry.At)
+		err := rebuilt.replay(entry)
+		rebuilt.clock = clock
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %w", ErrInvalidLog, entry.Sequence, err)
+		}
+	}
+	rebuilt.Archived = l.Archived
+
+	return rebuilt, nil
+}
+
+// unpaidCopy returns a copy of the loan with every payment undone, at its
+// first week
+func (l *Loan) unpaidCopy() *Loan {
+	rebuilt := l.clone()
+	for i := range rebuilt.Schedule {
+		entry := &rebuilt.Schedule[i]
+		entry.PaidAmount = rebuilt.zero()
+		entry.IsPaid = false
+		entry.PaidAt = nil
+	}
+	rebuilt.Payments = nil
+	rebuilt.CurrentWeek = rebuilt.firstWeek()
+	rebuilt.credit = Money{}
+	rebuilt.paid, rebuilt.paidCount = Money{}, 0
+	return rebuilt
+}
+
+// changesSchedule reports whether entries of kind reshape the schedule rather
+// than pay it
+func changesSchedule(kind LogEntryKind) bool {
+	switch kind {
+	case WeekDeferred, PrincipalPrepaid, LoanRestructured, RebateWrittenOff:
+		return true
+	default:
+		return false
+	}
+}
+
+// replay applies a single LogEntry, recording it in the loan's own PaymentLog
+func (l *Loan) replay(entry LogEntry) error {
+	switch entry.Kind {
+	case PaymentMade:
+		if entry.Payment.Method == CreditPaymentMethod {
+			credit := l.creditBalance()
+			if credit.LessThan(entry.Payment.Amount) {
+				return fmt.Errorf("credit balance %s is less than the payment", credit)
+			}
+			l.credit = credit.Subtract(entry.Payment.Amount)
+		}
+		if err := l.loadPayment(l.Schedule, entry.Payment); err != nil {
+			return err
+		}
+		l.Payments = append(l.Payments, entry.Payment)
+		l.record(LogEntry{Kind: PaymentMade, Payment: entry.Payment})
+		return nil
+	case PaymentReversed:
+		return l.reversePayment(entry.WeekNumber)
+	case WeekAdvanced:
+		if entry.WeekNumber != l.clampWeek(entry.WeekNumber) {
+			return ErrInvalidWeekNumber
+		}
+		l.setCurrentWeek(entry.WeekNumber)
+		return nil
+	case WeekDeferred:
+		return l.deferWeek(entry.WeekNumber)
+	case PrincipalPrepaid:
+		return l.prepayPrincipal(entry.Payment.Amount, entry.PrepayMode)
+	case LoanRestructured:
+		return l.restructure(entry.DurationWeeks, entry.InterestRate)
+	case CreditHeld:
+		l.credit = l.creditBalance().Add(entry.Amount)
+		l.record(LogEntry{Kind: CreditHeld, Amount: entry.Amount})
+		return nil
+	case RebateWrittenOff:
+		l.writeOff(entry.Amount)
+		return nil
+	default:
+		return fmt.Errorf("unknown entry kind %d", entry.Kind)
+	}
+}
@@ -0,0 +1,157 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// PrepayMode selects how a principal prepayment changes the remaining schedule
+type PrepayMode int
+
+const (
+	// ReduceTerm removes installments from the end of the schedule, so the
+	// loan finishes earlier while the weekly amount stays the same
+	ReduceTerm PrepayMode = iota
+
+	// ReducePayment keeps the term and spreads the lower balance evenly over
+	// the remaining unpaid weeks
+	ReducePayment
+)
+
+func (m PrepayMode) String() string {
+	switch m {
+	case ReduceTerm:
+		return "reduce_term"
+	case ReducePayment:
+		return "reduce_payment"
+	default:
+		return "unknown"
+	}
+}
+
+// PrepayPrincipal applies an extra payment outside the regular schedule and
+// records it in the payment history with WeekNumber 0. The interest portions
+// it takes off the schedule are recorded as the payment's Interest.
+// With ReduceTerm the amount must equal the sum of one or more installments at
+// the end of the schedule; the week currently due is never removed.
+// With ReducePayment the amount must leave at least one minor unit of the
+// currency per remaining week
+func (l *Loan) PrepayPrincipal(amount Money, mode PrepayMode) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	return l.prepayPrincipal(amount, mode)
+}
+
+func (l *Loan) prepayPrincipal(amount Money, mode PrepayMode) error {
+	if amount.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+
+	if amount.IsNegative() {
+		return ErrNegativeAmount
+	}
+	if amount.IsZero() {
+		return ErrInvalidPrepaymentAmount
+	}
+
+	firstUnpaidWeek := l.findFirstUnpaidWeek()
+	if firstUnpaidWeek == 0 {
+		return ErrLoanFullyPaid
+	}
+
+	interestBefore := l.scheduledInterest()
+	var err error
+	switch mode {
+	case ReduceTerm:
+		err = l.reduceTerm(amount, firstUnpaidWeek)
+	case ReducePayment:
+		err = l.reducePayment(amount, firstUnpaidWeek)
+	default:
+		err = ErrInvalidPrepayMode
+	}
+	if err != nil {
+		return err
+	}
+
+	payment := Payment{
+		WeekNumber: 0,
+		Amount:     amount,
+		PaidAt:     l.now(),
+		Interest:   interestBefore.Subtract(l.scheduledInterest()),
+	}
+	l.Payments = append(l.Payments, payment)
+	l.record(LogEntry{Kind: PrincipalPrepaid, Payment: payment, PrepayMode: mode})
+
+	return nil
+}
+
+// scheduledInterest returns the sum of the schedule's interest portions
+func (l *Loan) scheduledInterest() Money {
+	interest := l.zero()
+	for _, entry := range l.Schedule {
+		interest = interest.Add(entry.InterestPortion)
+	}
+	return interest
+}
+
+// reduceTerm drops the trailing unpaid installments that amount covers exactly
+// and shortens the loan accordingly
+func (l *Loan) reduceTerm(amount Money, firstUnpaidWeek int) error {
+	keep := len(l.Schedule)
+	consumed := l.zero()
+	for keep > l.scheduleIndex(firstUnpaidWeek)+1 && !l.Schedule[keep-1].IsPaid && consumed.LessThan(amount) {
+		keep--
+		consumed = consumed.Add(l.Schedule[keep].Amount)
+	}
+
+	if !consumed.Equals(amount) {
+		return ErrInvalidPrepaymentAmount
+	}
+
+	l.Schedule = l.Schedule[:keep]
+	l.DurationWeeks = l.firstWeek() + keep - 1
+	l.CurrentWeek = l.clampWeek(l.CurrentWeek)
+
+	return nil
+}
+
+// reducePayment recasts the unpaid weeks so that what is left after amount is
+// split evenly between them. The last week absorbs the rounding remainder.
+// Each week keeps its interest portion, capped at the new amount, and the rest
+// of the installment is principal. Weeks already paid out of sequence are left
+// alone
+func (l *Loan) reducePayment(amount Money, firstUnpaidWeek int) error {
+	var unpaid []*ScheduleEntry
+	for i := l.scheduleIndex(firstUnpaidWeek); i < len(l.Schedule); i++ {
+		if !l.Schedule[i].IsPaid {
+			unpaid = append(unpaid, &l.Schedule[i])
+		}
+	}
+
+	remaining := l.zero()
+	for _, entry := range unpaid {
+		remaining = remaining.Add(entry.Remaining())
+	}
+
+	// Every week keeps at least one minor unit of the currency
+	places := l.Principal.Currency().DecimalPlaces()
+	left := remaining.Subtract(amount).Amount()
+	weeks := decimal.NewFromInt(int64(len(unpaid)))
+	if left.LessThan(decimal.New(1, -places).Mul(weeks)) {
+		return ErrInvalidPrepaymentAmount
+	}
+
+	share := remaining.Subtract(amount).DivideDown(weeks, places).Amount()
+	last := left.Sub(share.Mul(weeks.Sub(decimal.NewFromInt(1))))
+	for i, entry := range unpaid {
+		due := share
+		if i == len(unpaid)-1 {
+			due = last
+		}
+		entry.resize(entry.PaidAmount.Add(NewMoneyFromDecimalWithCurrency(due, l.Principal.Currency())))
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// RebateMethod determines how much unearned interest PayoffAmount refunds
+// when a loan is settled early
+type RebateMethod int
+
+const (
+	// NoRebate charges the full outstanding, interest included
+	NoRebate RebateMethod = iota
+
+	// RuleOf78 rebates the sum-of-the-digits share of the total interest
+	// belonging to the unpaid weeks after the payoff week: with k such weeks
+	// out of n scheduled, k(k+1) / n(n+1) of the interest
+	RuleOf78
+
+	// Actuarial rebates exactly the interest portions scheduled for the unpaid
+	// weeks after the payoff week, as if they were never charged
+	Actuarial
+)
+
+func (m RebateMethod) String() string {
+	switch m {
+	case NoRebate:
+		return "none"
+	case RuleOf78:
+		return "rule_of_78"
+	case Actuarial:
+		return "actuarial"
+	default:
+		return "unknown"
+	}
+}
+
+// PayoffAmount returns what settles the loan at asOfWeek under its
+// RebateMethod: the outstanding less a rebate of the interest on unpaid weeks
+// after asOfWeek. The asOfWeek installment itself is due in full. Weeks out of
+// range are clamped to the schedule, and the rebate is rounded down to the
+// currency's minor units. With NoRebate it equals GetOutstanding
+func (l *Loan) PayoffAmount(asOfWeek int) Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.outstanding().Subtract(l.interestRebate(l.clampWeek(asOfWeek)))
+}
+
+// InterestRebate returns the rebate included in PayoffAmount(asOfWeek)
+func (l *Loan) InterestRebate(asOfWeek int) Money {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.interestRebate(l.clampWeek(asOfWeek))
+}
+
+func (l *Loan) interestRebate(asOfWeek int) Money {
+	totalInterest, unearned, remaining := l.zero(), l.zero(), 0
+	for _, entry := range l.Schedule {
+		totalInterest = totalInterest.Add(entry.InterestPortion)
+		if entry.WeekNumber > asOfWeek && entry.PaidAmount.IsZero() {
+			unearned = unearned.Add(entry.InterestPortion)
+			remaining++
+		}
+	}
+
+	var rebate Money
+	switch l.RebateMethod {
+	case RuleOf78:
+		n := len(l.Schedule)
+		rebate = totalInterest.
+			Multiply(decimal.NewFromInt(int64(remaining*(remaining+1)))).
+			DivideDown(decimal.NewFromInt(int64(n*(n+1))), l.Principal.Currency().DecimalPlaces())
+	case Actuarial:
+		rebate = unearned
+	default:
+		return l.zero()
+	}
+
+	if outstanding := l.outstanding(); rebate.GreaterThan(outstanding) {
+		return outstanding
+	}
+	return rebate
+}
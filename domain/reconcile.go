@@ -0,0 +1,110 @@
+package domain
+
+import "slices"
+
+// DiscrepancyKind classifies a mismatch between the schedule and the recorded
+// payments found by Reconcile
+type DiscrepancyKind int
+
+const (
+	// PaidWithoutPayment is a week marked paid with no payments recorded for it
+	PaidWithoutPayment DiscrepancyKind = iota
+
+	// PaymentWithoutPaidWeek is a payment recorded for a week the schedule
+	// shows nothing paid towards, or for a week that isn't scheduled at all
+	PaymentWithoutPaidWeek
+
+	// AmountMismatch is a week whose recorded payments don't add up to its
+	// paid amount
+	AmountMismatch
+)
+
+func (k DiscrepancyKind) String() string {
+	switch k {
+	case PaidWithoutPayment:
+		return "paid_without_payment"
+	case PaymentWithoutPaidWeek:
+		return "payment_without_paid_week"
+	case AmountMismatch:
+		return "amount_mismatch"
+	default:
+		return "unknown"
+	}
+}
+
+// Discrepancy is a single week where the schedule and the payments disagree
+type Discrepancy struct {
+	WeekNumber int
+	Kind       DiscrepancyKind
+	Expected   Money // PaidAmount on the schedule entry; zero for unscheduled weeks
+	Recorded   Money // Sum of the payments recorded for the week
+}
+
+// ReconcileResult lists the discrepancies found by Reconcile in week order
+type ReconcileResult struct {
+	Discrepancies []Discrepancy
+}
+
+// Consistent reports whether no discrepancies were found
+func (r ReconcileResult) Consistent() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// Reconcile compares each week's paid amount and paid flag against the
+// payments recorded for it, reporting at most one discrepancy per week.
+// Principal prepayments (week 0) aren't tied to a week and are skipped, as are
+// deferred weeks settled at nothing. Unlike Validate it doesn't stop at the
+// first problem
+func (l *Loan) Reconcile() ReconcileResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recorded := make(map[int]Money)
+	for _, payment := range l.Payments {
+		if payment.WeekNumber == 0 {
+			continue
+		}
+		sum, ok := recorded[payment.WeekNumber]
+		if !ok {
+			sum = l.zero()
+		}
+		recorded[payment.WeekNumber] = sum.Add(payment.Amount)
+	}
+
+	var result ReconcileResult
+	for _, entry := range l.Schedule {
+		paid, ok := recorded[entry.WeekNumber]
+		delete(recorded, entry.WeekNumber)
+		if !ok {
+			paid = l.zero()
+		}
+
+		discrepancy := Discrepancy{WeekNumber: entry.WeekNumber, Expected: entry.PaidAmount, Recorded: paid}
+		switch {
+		case paid.IsZero() && entry.IsPaid && !entry.Amount.IsZero():
+			discrepancy.Kind = PaidWithoutPayment
+		case !paid.IsZero() && entry.PaidAmount.IsZero() && !entry.IsPaid:
+			discrepancy.Kind = PaymentWithoutPaidWeek
+		case !paid.Equals(entry.PaidAmount):
+			discrepancy.Kind = AmountMismatch
+		default:
+			continue
+		}
+		result.Discrepancies = append(result.Discrepancies, discrepancy)
+	}
+
+	// Whatever is left was recorded against weeks outside the schedule
+	for week, paid := range recorded {
+		result.Discrepancies = append(result.Discrepancies, Discrepancy{
+			WeekNumber: week,
+			Kind:       PaymentWithoutPaidWeek,
+			Expected:   l.zero(),
+			Recorded:   paid,
+		})
+	}
+	slices.SortStableFunc(result.Discrepancies, func(a, b Discrepancy) int {
+		return a.WeekNumber - b.WeekNumber
+	})
+
+	return result
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Restructure re-amortizes the outstanding balance over newDurationWeeks
+// weeks at newRate, using the loan's interest model or the InterestCalculator
+// it was created with. Interest is recomputed on the outstanding as if it were
+// a new principal and the fresh schedule starts after the last week with a
+// payment. Weeks up to there that aren't paid in full are settled at what was
+// paid; their remainder is part of the restructured balance. Payment history
+// is kept.
+//
+// Delinquency restarts from the new schedule: the current week becomes its
+// first week and, if that week's due date has already passed, StartDate moves
+// forward so it is due now.
+//
+// Closed loans are rejected with ErrLoanFullyPaid. Terms are validated as in
+// NewLoan, against the loan's RateBounds, and a balance too small for newDurationWeeks positive installments
+// is rejected with ErrInvalidPrincipal
+func (l *Loan) Restructure(newDurationWeeks int, newRate decimal.Decimal) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Archived {
+		return ErrLoanArchived
+	}
+
+	return l.restructure(newDurationWeeks, newRate)
+}
+
+func (l *Loan) restructure(newDurationWeeks int, newRate decimal.Decimal) error {
+	terms := LoanTerms{DurationWeeks: newDurationWeeks, AnnualInterestRate: newRate, InterestModel: l.InterestModel, RateBounds: l.RateBounds}
+	if err := terms.Validate(); err != nil {
+		return err
+	}
+
+	outstanding := l.outstanding()
+	if outstanding.IsZero() {
+		return ErrLoanFullyPaid
+	}
+
+	lastWeek := l.lastPaymentWeek()
+	schedule, err := buildSchedule(outstanding, l.interestCalculator(), newRate, newDurationWeeks, lastWeek+1, 0)
+	if err != nil {
+		return err
+	}
+
+	now := l.now()
+	kept := l.Schedule[:l.scheduleIndex(lastWeek+1)]
+	for i := range kept {
+		if !kept[i].IsPaid {
+			kept[i].resize(kept[i].PaidAmount)
+			kept[i].IsPaid = true
+			kept[i].PaidAt = &now
+		}
+	}
+
+	l.Schedule = append(kept, schedule...)
+	l.TotalAmount = l.totalPaid().Add(sumSchedule(schedule))
+	l.InterestRate = newRate
+	l.DurationWeeks = lastWeek + newDurationWeeks
+	l.WeeklyPayment = schedule[0].Amount
+	l.CurrentWeek = lastWeek + 1
+
+	if dueDate, _ := l.dueDateForWeek(l.CurrentWeek); dueDate.Before(now) {
+		l.StartDate = l.PaymentFrequency.addPeriods(now, l.firstWeek()-l.CurrentWeek).
+			Add(-time.Duration(l.FirstPaymentDelayWeeks) * weekDuration)
+	}
+	l.record(LogEntry{Kind: LoanRestructured, DurationWeeks: newDurationWeeks, InterestRate: newRate})
+
+	return nil
+}
+
+// lastPaymentWeek returns the highest week that has received any payment,
+// fully or partly. With none it is the week before the first scheduled week
+func (l *Loan) lastPaymentWeek() int {
+	last := l.firstWeek() - 1
+	for _, entry := range l.Schedule {
+		if !entry.PaidAmount.IsZero() {
+			last = entry.WeekNumber
+		}
+	}
+	return last
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// LoanSnapshot is a point-in-time summary of a loan's financial state for
+// audit logs. It only holds plain values, amounts as decimal strings in
+// Currency, so it shares nothing with the loan and serializes as is
+type LoanSnapshot struct {
+	LoanID        string    `json:"loan_id"`
+	Currency      string    `json:"currency"`
+	Outstanding   string    `json:"outstanding"`
+	PaidToDate    string    `json:"paid_to_date"` // Every payment so far, prepayments included
+	WeeksPaid     int       `json:"weeks_paid"`
+	CurrentWeek   int       `json:"current_week"`
+	IsDelinquent  bool      `json:"is_delinquent"`
+	WeeksBehind   int       `json:"weeks_behind"`
+	LastPaidWeek  int       `json:"last_paid_week"`
+	OverdueAmount string    `json:"overdue_amount"`
+	GeneratedAt   time.Time `json:"generated_at"`
+}
+
+// Snapshot captures the loan's current financial state under a single lock.
+// GeneratedAt comes from the loan's clock
+func (l *Loan) Snapshot() LoanSnapshot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	weeksPaid := 0
+	for _, entry := range l.Schedule {
+		if entry.IsPaid {
+			weeksPaid++
+		}
+	}
+
+	delinquency := l.delinquencyInfo()
+
+	return LoanSnapshot{
+		LoanID:        l.ID,
+		Currency:      string(l.Principal.Currency()),
+		Outstanding:   l.outstanding().Amount().String(),
+		PaidToDate:    l.totalPaid().Amount().String(),
+		WeeksPaid:     weeksPaid,
+		CurrentWeek:   l.CurrentWeek,
+		IsDelinquent:  delinquency.IsDelinquent,
+		WeeksBehind:   delinquency.WeeksBehind,
+		LastPaidWeek:  delinquency.LastPaidWeek,
+		OverdueAmount: delinquency.OverdueAmount.Amount().String(),
+		GeneratedAt:   l.now(),
+	}
+}
@@ -0,0 +1,145 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// DefaultThreshold is the number of weeks behind at which a loan with the
+// default DelinquencyThreshold is considered defaulted
+const DefaultThreshold = 12
+
+// LoanStatus is the lifecycle state of a loan, derived from its payments
+type LoanStatus int
+
+const (
+	// Active loans are open and less than the loan's DelinquencyThreshold weeks behind
+	Active LoanStatus = iota
+
+	// Delinquent loans are the loan's DelinquencyThreshold or more weeks behind
+	Delinquent
+
+	// PaidOff loans have no outstanding balance
+	PaidOff
+
+	// Defaulted loans are DefaultThreshold or more weeks behind, or as many
+	// weeks past their own DelinquencyThreshold when it is higher than the
+	// default, see defaultThreshold
+	Defaulted
+)
+
+func (s LoanStatus) String() string {
+	switch s {
+	case Active:
+		return "active"
+	case Delinquent:
+		return "delinquent"
+	case PaidOff:
+		return "paid_off"
+	case Defaulted:
+		return "defaulted"
+	default:
+		return "unknown"
+	}
+}
+
+// Suspend flags the loan as suspended, e.g. while a dispute is investigated
+func (l *Loan) Suspend() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Suspended = true
+}
+
+// Resume clears the suspended flag set by Suspend
+func (l *Loan) Resume() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Suspended = false
+}
+
+// Archive freezes the loan, e.g. once it is closed and reconciled. Payments
+// and schedule changes fail with ErrLoanArchived until Unarchive is called
+func (l *Loan) Archive() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Archived = true
+}
+
+// Unarchive lifts the freeze set by Archive. It is meant for administrators
+// correcting a loan that was archived by mistake
+func (l *Loan) Unarchive() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Archived = false
+}
+
+// IsAutoDebitEligible reports whether the loan is in good enough standing to be
+// auto-debited: open, not delinquent and not suspended
+func (l *Loan) IsAutoDebitEligible() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return !l.isClosed() && !l.isDelinquent() && !l.Suspended
+}
+
+// Status derives the loan's current state from its outstanding balance and
+// how far the current week is past the last paid week
+func (l *Loan) Status() LoanStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.status()
+}
+
+func (l *Loan) status() LoanStatus {
+	switch {
+	case l.isClosed():
+		return PaidOff
+	case l.weeksBehind() >= l.defaultThreshold():
+		return Defaulted
+	case l.isDelinquent():
+		return Delinquent
+	default:
+		return Active
+	}
+}
+
+// defaultThreshold returns how many weeks behind the loan defaults. Loans
+// with a higher DelinquencyThreshold than the default get the same number of
+// delinquent weeks before defaulting, so they are always delinquent first
+func (l *Loan) defaultThreshold() int {
+	return max(DefaultThreshold, l.delinquencyThreshold()+DefaultThreshold-DelinquencyThreshold)
+}
+
+// DelinquencyInfo describes how far behind a loan is, for dunning workflows
+type DelinquencyInfo struct {
+	IsDelinquent  bool
+	WeeksBehind   int   // Weeks past the last paid week; 0 when paid ahead
+	LastPaidWeek  int   // Highest paid week; the week before StartWeek if nothing is paid, 0 unless the loan starts mid-term
+	OverdueAmount Money // WeeksBehind × WeeklyPayment, at most the outstanding
+}
+
+// DelinquencyInfo returns the loan's delinquency details. IsDelinquent always
+// agrees with Loan.IsDelinquent
+func (l *Loan) DelinquencyInfo() DelinquencyInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.delinquencyInfo()
+}
+
+func (l *Loan) delinquencyInfo() DelinquencyInfo {
+	weeksBehind := max(0, l.weeksBehind())
+	overdue := l.WeeklyPayment.Multiply(decimal.NewFromInt(int64(weeksBehind)))
+	if outstanding := l.outstanding(); overdue.GreaterThan(outstanding) {
+		overdue = outstanding
+	}
+
+	return DelinquencyInfo{
+		IsDelinquent:  l.isDelinquent(),
+		WeeksBehind:   weeksBehind,
+		LastPaidWeek:  l.lastPaidWeek(),
+		OverdueAmount: overdue,
+	}
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateBounds is the inclusive range of annual interest rates a product
+// accepts. It catches typos such as 10 for 10%
+type RateBounds struct {
+	Min decimal.Decimal
+	Max decimal.Decimal
+}
+
+// DefaultRateBounds accepts rates from 0% to 100%
+var DefaultRateBounds = RateBounds{Min: decimal.Zero, Max: decimal.NewFromInt(1)}
+
+// Contains reports whether rate is within the bounds
+func (b RateBounds) Contains(rate decimal.Decimal) bool {
+	return !rate.LessThan(b.Min) && !rate.GreaterThan(b.Max)
+}
+
+// LoanTerms describes the product a loan is created under
+type LoanTerms struct {
+	DurationWeeks          int
+	AnnualInterestRate     decimal.Decimal // e.g. 0.10 for 10%
+	RateBounds             *RateBounds     // Allowed AnnualInterestRate range; nil uses DefaultRateBounds
+	InterestModel          InterestModel
+	InterestCalculator     InterestCalculator // Overrides InterestModel for custom products; nil uses InterestModel.Calculator
+	OriginationFee         Money              // Charged once on top of the schedule; zero when omitted
+	DelinquencyThreshold   int                // Weeks behind at which the loan is delinquent; zero uses the default of 2
+	StartWeek              int                // First week on the schedule, for loans migrated mid-term; zero means week 1
+	GracePeriodDays        int                // Days after a due date before the week counts as missed, see IsDelinquentByDate
+	FirstPaymentDelayWeeks int                // Calendar weeks between origination and the first due date; zero means due at origination
+	AllowNonSequential     bool               // Lets any unpaid week be paid, not only the first unpaid one
+	PaymentStrategy        PaymentStrategy    // How MakeNextPayment allocates an amount; Explicit when omitted
+	OverpaymentPolicy      OverpaymentPolicy  // What happens to a payment above what is due; Reject when omitted
+	AcceptAtLeastWeekly    bool               // Accepts any amount from what is due upwards, even under Reject, see Loan.AcceptAtLeastWeekly
+	RequireCatchUp         bool               // Delinquent loans only accept the full overdue amount, see Loan.PayOverdue
+	PromoFreeWeeks         int                // Leading weeks that repay only principal; their interest moves to the later weeks
+	PaymentFrequency       PaymentFrequency   // How often installments fall due; Weekly when omitted. DurationWeeks counts installments
+	RebateMethod           RebateMethod       // Interest rebated by PayoffAmount on early settlement; NoRebate when omitted
+	MaxDeferrals           int                // Weeks DeferWeek may move to the end of the loan; zero disables deferrals
+}
+
+// DefaultTerms returns the standard product: 50 weeks at 10% flat interest
+func DefaultTerms() LoanTerms {
+	return LoanTerms{
+		DurationWeeks:        LoanDurationWeeks,
+		AnnualInterestRate:   decimal.NewFromFloat(0.10),
+		InterestModel:        FlatInterest,
+		OriginationFee:       NewMoney(0),
+		DelinquencyThreshold: DelinquencyThreshold,
+	}
+}
+
+// Validate checks that the terms can produce a valid schedule
+func (t LoanTerms) Validate() error {
+	if t.DurationWeeks < 1 {
+		return ErrInvalidDuration
+	}
+
+	if t.AnnualInterestRate.IsNegative() {
+		return ErrInvalidInterestRate
+	}
+
+	bounds := t.rateBounds()
+	if bounds.Min.GreaterThan(bounds.Max) {
+		return fmt.Errorf("%w: min %s is above max %s", ErrInvalidRateBounds, bounds.Min, bounds.Max)
+	}
+	if !bounds.Contains(t.AnnualInterestRate) {
+		return fmt.Errorf("%w: %s is outside [%s, %s]", ErrInterestRateOutOfRange, t.AnnualInterestRate, bounds.Min, bounds.Max)
+	}
+
+	if t.OriginationFee.IsNegative() {
+		return ErrNegativeAmount
+	}
+
+	if t.DelinquencyThreshold < 0 {
+		return ErrInvalidDelinquencyThreshold
+	}
+
+	if t.StartWeek < 0 || t.StartWeek > t.DurationWeeks {
+		return ErrInvalidStartWeek
+	}
+
+	if t.GracePeriodDays < 0 {
+		return ErrInvalidGracePeriod
+	}
+
+	if t.FirstPaymentDelayWeeks < 0 {
+		return ErrInvalidFirstPaymentDelay
+	}
+
+	if t.PaymentFrequency < Weekly || t.PaymentFrequency > Monthly {
+		return ErrInvalidPaymentFrequency
+	}
+
+	if t.RebateMethod < NoRebate || t.RebateMethod > Actuarial {
+		return ErrInvalidRebateMethod
+	}
+
+	if t.MaxDeferrals < 0 {
+		return ErrInvalidMaxDeferrals
+	}
+
+	if t.PromoFreeWeeks < 0 || t.PromoFreeWeeks >= t.DurationWeeks {
+		return ErrInvalidPromoFreeWeeks
+	}
+
+	return nil
+}
+
+// rateBounds returns the terms' RateBounds, or DefaultRateBounds when unset
+func (t LoanTerms) rateBounds() RateBounds {
+	if t.RateBounds == nil {
+		return DefaultRateBounds
+	}
+	return *t.RateBounds
+}
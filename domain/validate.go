@@ -0,0 +1,101 @@
+package domain
+
+import "fmt"
+
+// Validate checks that the loan is internally consistent, e.g. after being
+// imported from another system. It returns an error wrapping ErrInvalidLoan
+// describing the first problem found
+func (l *Loan) Validate() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ID == "" {
+		return fmt.Errorf("%w: missing ID", ErrInvalidLoan)
+	}
+	if l.BorrowerID == "" {
+		return fmt.Errorf("%w: missing borrower ID", ErrInvalidLoan)
+	}
+	if l.Principal.IsNegative() || l.Principal.IsZero() {
+		return fmt.Errorf("%w: principal must be positive, got %s", ErrInvalidLoan, l.Principal)
+	}
+	if l.DurationWeeks < 1 || len(l.Schedule) != l.DurationWeeks-l.firstWeek()+1 {
+		return fmt.Errorf("%w: schedule has %d entries for weeks %d to %d", ErrInvalidLoan, len(l.Schedule), l.firstWeek(), l.DurationWeeks)
+	}
+	if l.CurrentWeek != l.clampWeek(l.CurrentWeek) {
+		return fmt.Errorf("%w: current week %d out of range", ErrInvalidLoan, l.CurrentWeek)
+	}
+
+	if err := l.validateCurrencies(); err != nil {
+		return err
+	}
+
+	prepaid := l.zero()
+	paidByWeek := make(map[int]Money)
+	for _, payment := range l.Payments {
+		if payment.WeekNumber == 0 {
+			prepaid = prepaid.Add(payment.Amount)
+			continue
+		}
+		if !l.isScheduledWeek(payment.WeekNumber) {
+			return fmt.Errorf("%w: payment for invalid week %d", ErrInvalidLoan, payment.WeekNumber)
+		}
+		if _, ok := paidByWeek[payment.WeekNumber]; !ok {
+			paidByWeek[payment.WeekNumber] = l.zero()
+		}
+		paidByWeek[payment.WeekNumber] = paidByWeek[payment.WeekNumber].Add(payment.Amount)
+	}
+
+	// Prepayments cover installments that are no longer in the schedule
+	total := prepaid
+	seenUnpaid := false
+	for i, entry := range l.Schedule {
+		if entry.WeekNumber != l.firstWeek()+i {
+			return fmt.Errorf("%w: schedule entry %d has week number %d", ErrInvalidLoan, i+1, entry.WeekNumber)
+		}
+		if entry.PaidAmount.IsNegative() || entry.PaidAmount.GreaterThan(entry.Amount) {
+			return fmt.Errorf("%w: week %d paid %s of %s", ErrInvalidLoan, entry.WeekNumber, entry.PaidAmount, entry.Amount)
+		}
+		if entry.IsPaid != entry.Remaining().IsZero() {
+			return fmt.Errorf("%w: week %d paid flag doesn't match its remaining amount", ErrInvalidLoan, entry.WeekNumber)
+		}
+		if entry.IsPaid != (entry.PaidAt != nil) {
+			return fmt.Errorf("%w: week %d paid flag doesn't match its paid timestamp", ErrInvalidLoan, entry.WeekNumber)
+		}
+		paid, ok := paidByWeek[entry.WeekNumber]
+		if !ok {
+			paid = l.zero()
+		}
+		if !paid.Equals(entry.PaidAmount) {
+			return fmt.Errorf("%w: week %d payments don't add up to its paid amount", ErrInvalidLoan, entry.WeekNumber)
+		}
+		if entry.IsPaid && seenUnpaid && !l.AllowNonSequential {
+			return fmt.Errorf("%w: week %d paid after an unpaid week", ErrInvalidLoan, entry.WeekNumber)
+		}
+		seenUnpaid = seenUnpaid || !entry.IsPaid
+		total = total.Add(entry.Amount)
+	}
+
+	if !total.Equals(l.TotalAmount) {
+		return fmt.Errorf("%w: schedule and prepayments add up to %s, total amount is %s", ErrInvalidLoan, total, l.TotalAmount)
+	}
+
+	return nil
+}
+
+// validateCurrencies checks every amount on the loan is in the principal's currency
+func (l *Loan) validateCurrencies() error {
+	amounts := []Money{l.TotalAmount, l.OriginationFee}
+	for _, entry := range l.Schedule {
+		amounts = append(amounts, entry.Amount, entry.PaidAmount)
+	}
+	for _, payment := range l.Payments {
+		amounts = append(amounts, payment.Amount)
+	}
+
+	for _, amount := range amounts {
+		if amount.Currency() != l.Principal.Currency() {
+			return fmt.Errorf("%w: %s amount on a %s loan", ErrInvalidLoan, amount.Currency(), l.Principal.Currency())
+		}
+	}
+	return nil
+}
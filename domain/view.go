@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// LoanView is a read-only, point-in-time copy of a Loan
+// It is detached from the original, so later payments don't affect it
+type LoanView struct {
+	loan *Loan
+}
+
+// View returns an immutable snapshot of the loan
+func (l *Loan) View() *LoanView {
+	return &LoanView{loan: l.clone()}
+}
+
+func (v *LoanView) ID() string {
+	return v.loan.ID
+}
+
+func (v *LoanView) BorrowerID() string {
+	return v.loan.BorrowerID
+}
+
+func (v *LoanView) Principal() Money {
+	return v.loan.Principal
+}
+
+func (v *LoanView) TotalAmount() Money {
+	return v.loan.TotalAmount
+}
+
+func (v *LoanView) WeeklyPayment() Money {
+	return v.loan.WeeklyPayment
+}
+
+func (v *LoanView) StartWeek() int {
+	return v.loan.firstWeek()
+}
+
+func (v *LoanView) CurrentWeek() int {
+	return v.loan.CurrentWeek
+}
+
+func (v *LoanView) StartDate() time.Time {
+	return v.loan.StartDate
+}
+
+func (v *LoanView) Outstanding() Money {
+	return v.loan.GetOutstanding()
+}
+
+func (v *LoanView) IsDelinquent() bool {
+	return v.loan.IsDelinquent()
+}
+
+func (v *LoanView) NextDueWeek() int {
+	return v.loan.GetNextDueWeek()
+}
+
+func (v *LoanView) IsClosed() bool {
+	return v.loan.IsClosed()
+}
+
+func (v *LoanView) DueDateForWeek(week int) (time.Time, error) {
+	return v.loan.DueDateForWeek(week)
+}
+
+func (v *LoanView) Schedule() []ScheduleEntry {
+	return v.loan.GetSchedule()
+}
+
+func (v *LoanView) Payments() []Payment {
+	return v.loan.GetPaymentHistory()
+}
@@ -0,0 +1,1036 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: billing.proto
+
+package billingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateLoanRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	LoanId     string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	BorrowerId string                 `protobuf:"bytes,2,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	Principal  string                 `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	// Optional, defaults to 50
+	DurationWeeks int32 `protobuf:"varint,4,opt,name=duration_weeks,json=durationWeeks,proto3" json:"duration_weeks,omitempty"`
+	// Optional decimal string, defaults to "0.10"
+	AnnualInterestRate string `protobuf:"bytes,5,opt,name=annual_interest_rate,json=annualInterestRate,proto3" json:"annual_interest_rate,omitempty"`
+	// Optional: flat, revolving or compound_weekly. Defaults to flat
+	InterestModel string `protobuf:"bytes,6,opt,name=interest_model,json=interestModel,proto3" json:"interest_model,omitempty"`
+	// Optional currency of the principal and the loan: IDR or USD. Defaults to IDR
+	Currency      string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLoanRequest) Reset() {
+	*x = CreateLoanRequest{}
+	mi := &file_billing_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLoanRequest) ProtoMessage() {}
+
+func (x *CreateLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLoanRequest.ProtoReflect.Descriptor instead.
+func (*CreateLoanRequest) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateLoanRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetDurationWeeks() int32 {
+	if x != nil {
+		return x.DurationWeeks
+	}
+	return 0
+}
+
+func (x *CreateLoanRequest) GetAnnualInterestRate() string {
+	if x != nil {
+		return x.AnnualInterestRate
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetInterestModel() string {
+	if x != nil {
+		return x.InterestModel
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Loan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	BorrowerId    string                 `protobuf:"bytes,2,opt,name=borrower_id,json=borrowerId,proto3" json:"borrower_id,omitempty"`
+	Principal     string                 `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	TotalAmount   string                 `protobuf:"bytes,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	WeeklyPayment string                 `protobuf:"bytes,5,opt,name=weekly_payment,json=weeklyPayment,proto3" json:"weekly_payment,omitempty"`
+	DurationWeeks int32                  `protobuf:"varint,6,opt,name=duration_weeks,json=durationWeeks,proto3" json:"duration_weeks,omitempty"`
+	InterestModel string                 `protobuf:"bytes,7,opt,name=interest_model,json=interestModel,proto3" json:"interest_model,omitempty"`
+	Currency      string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Loan) Reset() {
+	*x = Loan{}
+	mi := &file_billing_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Loan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Loan) ProtoMessage() {}
+
+func (x *Loan) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Loan.ProtoReflect.Descriptor instead.
+func (*Loan) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Loan) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *Loan) GetBorrowerId() string {
+	if x != nil {
+		return x.BorrowerId
+	}
+	return ""
+}
+
+func (x *Loan) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *Loan) GetTotalAmount() string {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return ""
+}
+
+func (x *Loan) GetWeeklyPayment() string {
+	if x != nil {
+		return x.WeeklyPayment
+	}
+	return ""
+}
+
+func (x *Loan) GetDurationWeeks() int32 {
+	if x != nil {
+		return x.DurationWeeks
+	}
+	return 0
+}
+
+func (x *Loan) GetInterestModel() string {
+	if x != nil {
+		return x.InterestModel
+	}
+	return ""
+}
+
+func (x *Loan) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetOutstandingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOutstandingRequest) Reset() {
+	*x = GetOutstandingRequest{}
+	mi := &file_billing_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOutstandingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutstandingRequest) ProtoMessage() {}
+
+func (x *GetOutstandingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutstandingRequest.ProtoReflect.Descriptor instead.
+func (*GetOutstandingRequest) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetOutstandingRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+type GetOutstandingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	Outstanding   string                 `protobuf:"bytes,2,opt,name=outstanding,proto3" json:"outstanding,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOutstandingResponse) Reset() {
+	*x = GetOutstandingResponse{}
+	mi := &file_billing_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOutstandingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutstandingResponse) ProtoMessage() {}
+
+func (x *GetOutstandingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutstandingResponse.ProtoReflect.Descriptor instead.
+func (*GetOutstandingResponse) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetOutstandingResponse) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *GetOutstandingResponse) GetOutstanding() string {
+	if x != nil {
+		return x.Outstanding
+	}
+	return ""
+}
+
+func (x *GetOutstandingResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type IsDelinquentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsDelinquentRequest) Reset() {
+	*x = IsDelinquentRequest{}
+	mi := &file_billing_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsDelinquentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsDelinquentRequest) ProtoMessage() {}
+
+func (x *IsDelinquentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsDelinquentRequest.ProtoReflect.Descriptor instead.
+func (*IsDelinquentRequest) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IsDelinquentRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+type IsDelinquentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	IsDelinquent  bool                   `protobuf:"varint,2,opt,name=is_delinquent,json=isDelinquent,proto3" json:"is_delinquent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IsDelinquentResponse) Reset() {
+	*x = IsDelinquentResponse{}
+	mi := &file_billing_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IsDelinquentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsDelinquentResponse) ProtoMessage() {}
+
+func (x *IsDelinquentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsDelinquentResponse.ProtoReflect.Descriptor instead.
+func (*IsDelinquentResponse) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IsDelinquentResponse) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *IsDelinquentResponse) GetIsDelinquent() bool {
+	if x != nil {
+		return x.IsDelinquent
+	}
+	return false
+}
+
+type MakePaymentRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	LoanId string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	Amount string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Week to pay; 0 pays the next due week
+	WeekNumber int32 `protobuf:"varint,3,opt,name=week_number,json=weekNumber,proto3" json:"week_number,omitempty"`
+	// Optional currency of amount: IDR or USD. Defaults to IDR and must match the loan
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MakePaymentRequest) Reset() {
+	*x = MakePaymentRequest{}
+	mi := &file_billing_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakePaymentRequest) ProtoMessage() {}
+
+func (x *MakePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakePaymentRequest.ProtoReflect.Descriptor instead.
+func (*MakePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MakePaymentRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *MakePaymentRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *MakePaymentRequest) GetWeekNumber() int32 {
+	if x != nil {
+		return x.WeekNumber
+	}
+	return 0
+}
+
+func (x *MakePaymentRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type MakePaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	Outstanding   string                 `protobuf:"bytes,2,opt,name=outstanding,proto3" json:"outstanding,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MakePaymentResponse) Reset() {
+	*x = MakePaymentResponse{}
+	mi := &file_billing_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakePaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakePaymentResponse) ProtoMessage() {}
+
+func (x *MakePaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakePaymentResponse.ProtoReflect.Descriptor instead.
+func (*MakePaymentResponse) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MakePaymentResponse) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *MakePaymentResponse) GetOutstanding() string {
+	if x != nil {
+		return x.Outstanding
+	}
+	return ""
+}
+
+func (x *MakePaymentResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_billing_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetScheduleRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+type ScheduleEntry struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	WeekNumber int32                  `protobuf:"varint,1,opt,name=week_number,json=weekNumber,proto3" json:"week_number,omitempty"`
+	Amount     string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaidAmount string                 `protobuf:"bytes,3,opt,name=paid_amount,json=paidAmount,proto3" json:"paid_amount,omitempty"`
+	IsPaid     bool                   `protobuf:"varint,4,opt,name=is_paid,json=isPaid,proto3" json:"is_paid,omitempty"`
+	// Unset while the week is unpaid
+	PaidAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleEntry) Reset() {
+	*x = ScheduleEntry{}
+	mi := &file_billing_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleEntry) ProtoMessage() {}
+
+func (x *ScheduleEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleEntry.ProtoReflect.Descriptor instead.
+func (*ScheduleEntry) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ScheduleEntry) GetWeekNumber() int32 {
+	if x != nil {
+		return x.WeekNumber
+	}
+	return 0
+}
+
+func (x *ScheduleEntry) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ScheduleEntry) GetPaidAmount() string {
+	if x != nil {
+		return x.PaidAmount
+	}
+	return ""
+}
+
+func (x *ScheduleEntry) GetIsPaid() bool {
+	if x != nil {
+		return x.IsPaid
+	}
+	return false
+}
+
+func (x *ScheduleEntry) GetPaidAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaidAt
+	}
+	return nil
+}
+
+type GetScheduleResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Entries []*ScheduleEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Currency of every amount in entries
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleResponse) Reset() {
+	*x = GetScheduleResponse{}
+	mi := &file_billing_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleResponse) ProtoMessage() {}
+
+func (x *GetScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetScheduleResponse) GetEntries() []*ScheduleEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetScheduleResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetPaymentHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentHistoryRequest) Reset() {
+	*x = GetPaymentHistoryRequest{}
+	mi := &file_billing_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentHistoryRequest) ProtoMessage() {}
+
+func (x *GetPaymentHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetPaymentHistoryRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+type Payment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WeekNumber    int32                  `protobuf:"varint,1,opt,name=week_number,json=weekNumber,proto3" json:"week_number,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaidAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_billing_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Payment) GetWeekNumber() int32 {
+	if x != nil {
+		return x.WeekNumber
+	}
+	return 0
+}
+
+func (x *Payment) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Payment) GetPaidAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaidAt
+	}
+	return nil
+}
+
+type GetPaymentHistoryResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Payments []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	// Currency of every amount in payments
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentHistoryResponse) Reset() {
+	*x = GetPaymentHistoryResponse{}
+	mi := &file_billing_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentHistoryResponse) ProtoMessage() {}
+
+func (x *GetPaymentHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_billing_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_billing_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetPaymentHistoryResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *GetPaymentHistoryResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+var File_billing_proto protoreflect.FileDescriptor
+
+const file_billing_proto_rawDesc = "" +
+	"\n" +
+	"\rbilling.proto\x12\n" +
+	"billing.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x87\x02\n" +
+	"\x11CreateLoanRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12\x1f\n" +
+	"\vborrower_id\x18\x02 \x01(\tR\n" +
+	"borrowerId\x12\x1c\n" +
+	"\tprincipal\x18\x03 \x01(\tR\tprincipal\x12%\n" +
+	"\x0eduration_weeks\x18\x04 \x01(\x05R\rdurationWeeks\x120\n" +
+	"\x14annual_interest_rate\x18\x05 \x01(\tR\x12annualInterestRate\x12%\n" +
+	"\x0einterest_model\x18\x06 \x01(\tR\rinterestModel\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\"\x92\x02\n" +
+	"\x04Loan\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12\x1f\n" +
+	"\vborrower_id\x18\x02 \x01(\tR\n" +
+	"borrowerId\x12\x1c\n" +
+	"\tprincipal\x18\x03 \x01(\tR\tprincipal\x12!\n" +
+	"\ftotal_amount\x18\x04 \x01(\tR\vtotalAmount\x12%\n" +
+	"\x0eweekly_payment\x18\x05 \x01(\tR\rweeklyPayment\x12%\n" +
+	"\x0eduration_weeks\x18\x06 \x01(\x05R\rdurationWeeks\x12%\n" +
+	"\x0einterest_model\x18\a \x01(\tR\rinterestModel\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\"0\n" +
+	"\x15GetOutstandingRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\"o\n" +
+	"\x16GetOutstandingResponse\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12 \n" +
+	"\voutstanding\x18\x02 \x01(\tR\voutstanding\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\".\n" +
+	"\x13IsDelinquentRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\"T\n" +
+	"\x14IsDelinquentResponse\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12#\n" +
+	"\ris_delinquent\x18\x02 \x01(\bR\fisDelinquent\"\x82\x01\n" +
+	"\x12MakePaymentRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x1f\n" +
+	"\vweek_number\x18\x03 \x01(\x05R\n" +
+	"weekNumber\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"l\n" +
+	"\x13MakePaymentResponse\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12 \n" +
+	"\voutstanding\x18\x02 \x01(\tR\voutstanding\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"-\n" +
+	"\x12GetScheduleRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\"\xb7\x01\n" +
+	"\rScheduleEntry\x12\x1f\n" +
+	"\vweek_number\x18\x01 \x01(\x05R\n" +
+	"weekNumber\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x1f\n" +
+	"\vpaid_amount\x18\x03 \x01(\tR\n" +
+	"paidAmount\x12\x17\n" +
+	"\ais_paid\x18\x04 \x01(\bR\x06isPaid\x123\n" +
+	"\apaid_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06paidAt\"f\n" +
+	"\x13GetScheduleResponse\x123\n" +
+	"\aentries\x18\x01 \x03(\v2\x19.billing.v1.ScheduleEntryR\aentries\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"3\n" +
+	"\x18GetPaymentHistoryRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\"w\n" +
+	"\aPayment\x12\x1f\n" +
+	"\vweek_number\x18\x01 \x01(\x05R\n" +
+	"weekNumber\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x123\n" +
+	"\apaid_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06paidAt\"h\n" +
+	"\x19GetPaymentHistoryResponse\x12/\n" +
+	"\bpayments\x18\x01 \x03(\v2\x13.billing.v1.PaymentR\bpayments\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency2\xfd\x03\n" +
+	"\x0eBillingService\x12=\n" +
+	"\n" +
+	"CreateLoan\x12\x1d.billing.v1.CreateLoanRequest\x1a\x10.billing.v1.Loan\x12W\n" +
+	"\x0eGetOutstanding\x12!.billing.v1.GetOutstandingRequest\x1a\".billing.v1.GetOutstandingResponse\x12Q\n" +
+	"\fIsDelinquent\x12\x1f.billing.v1.IsDelinquentRequest\x1a .billing.v1.IsDelinquentResponse\x12N\n" +
+	"\vMakePayment\x12\x1e.billing.v1.MakePaymentRequest\x1a\x1f.billing.v1.MakePaymentResponse\x12N\n" +
+	"\vGetSchedule\x12\x1e.billing.v1.GetScheduleRequest\x1a\x1f.billing.v1.GetScheduleResponse\x12`\n" +
+	"\x11GetPaymentHistory\x12$.billing.v1.GetPaymentHistoryRequest\x1a%.billing.v1.GetPaymentHistoryResponseB3Z1github.com/rendikr/billing-engine/proto/billingpbb\x06proto3"
+
+var (
+	file_billing_proto_rawDescOnce sync.Once
+	file_billing_proto_rawDescData []byte
+)
+
+func file_billing_proto_rawDescGZIP() []byte {
+	file_billing_proto_rawDescOnce.Do(func() {
+		file_billing_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_billing_proto_rawDesc), len(file_billing_proto_rawDesc)))
+	})
+	return file_billing_proto_rawDescData
+}
+
+var file_billing_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_billing_proto_goTypes = []any{
+	(*CreateLoanRequest)(nil),         // 0: billing.v1.CreateLoanRequest
+	(*Loan)(nil),                      // 1: billing.v1.Loan
+	(*GetOutstandingRequest)(nil),     // 2: billing.v1.GetOutstandingRequest
+	(*GetOutstandingResponse)(nil),    // 3: billing.v1.GetOutstandingResponse
+	(*IsDelinquentRequest)(nil),       // 4: billing.v1.IsDelinquentRequest
+	(*IsDelinquentResponse)(nil),      // 5: billing.v1.IsDelinquentResponse
+	(*MakePaymentRequest)(nil),        // 6: billing.v1.MakePaymentRequest
+	(*MakePaymentResponse)(nil),       // 7: billing.v1.MakePaymentResponse
+	(*GetScheduleRequest)(nil),        // 8: billing.v1.GetScheduleRequest
+	(*ScheduleEntry)(nil),             // 9: billing.v1.ScheduleEntry
+	(*GetScheduleResponse)(nil),       // 10: billing.v1.GetScheduleResponse
+	(*GetPaymentHistoryRequest)(nil),  // 11: billing.v1.GetPaymentHistoryRequest
+	(*Payment)(nil),                   // 12: billing.v1.Payment
+	(*GetPaymentHistoryResponse)(nil), // 13: billing.v1.GetPaymentHistoryResponse
+	(*timestamppb.Timestamp)(nil),     // 14: google.protobuf.Timestamp
+}
+var file_billing_proto_depIdxs = []int32{
+	14, // 0: billing.v1.ScheduleEntry.paid_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: billing.v1.GetScheduleResponse.entries:type_name -> billing.v1.ScheduleEntry
+	14, // 2: billing.v1.Payment.paid_at:type_name -> google.protobuf.Timestamp
+	12, // 3: billing.v1.GetPaymentHistoryResponse.payments:type_name -> billing.v1.Payment
+	0,  // 4: billing.v1.BillingService.CreateLoan:input_type -> billing.v1.CreateLoanRequest
+	2,  // 5: billing.v1.BillingService.GetOutstanding:input_type -> billing.v1.GetOutstandingRequest
+	4,  // 6: billing.v1.BillingService.IsDelinquent:input_type -> billing.v1.IsDelinquentRequest
+	6,  // 7: billing.v1.BillingService.MakePayment:input_type -> billing.v1.MakePaymentRequest
+	8,  // 8: billing.v1.BillingService.GetSchedule:input_type -> billing.v1.GetScheduleRequest
+	11, // 9: billing.v1.BillingService.GetPaymentHistory:input_type -> billing.v1.GetPaymentHistoryRequest
+	1,  // 10: billing.v1.BillingService.CreateLoan:output_type -> billing.v1.Loan
+	3,  // 11: billing.v1.BillingService.GetOutstanding:output_type -> billing.v1.GetOutstandingResponse
+	5,  // 12: billing.v1.BillingService.IsDelinquent:output_type -> billing.v1.IsDelinquentResponse
+	7,  // 13: billing.v1.BillingService.MakePayment:output_type -> billing.v1.MakePaymentResponse
+	10, // 14: billing.v1.BillingService.GetSchedule:output_type -> billing.v1.GetScheduleResponse
+	13, // 15: billing.v1.BillingService.GetPaymentHistory:output_type -> billing.v1.GetPaymentHistoryResponse
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_billing_proto_init() }
+func file_billing_proto_init() {
+	if File_billing_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_billing_proto_rawDesc), len(file_billing_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_billing_proto_goTypes,
+		DependencyIndexes: file_billing_proto_depIdxs,
+		MessageInfos:      file_billing_proto_msgTypes,
+	}.Build()
+	File_billing_proto = out.File
+	file_billing_proto_goTypes = nil
+	file_billing_proto_depIdxs = nil
+}
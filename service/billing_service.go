@@ -1,60 +1,423 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/rendikr/billing-engine/domain"
 	"github.com/shopspring/decimal"
 )
 
+// BillingService coordinates loans stored in a LoanRepository
+// Each loan guards its own state, so operations on different loans run in
+// parallel; the service lock only serializes loan creation
+// Methods that take a context return ctx.Err() without doing any work once
+// the context is done
 type BillingService struct {
-	loans map[string]*domain.Loan
-	mu    sync.RWMutex
+	repo        LoanRepository
+	mu          sync.Mutex
+	events      eventDispatcher
+	logger      Logger
+	metrics     Metrics
+	ids         IDGenerator
+	idValidator IDValidator
 }
 
-func NewBillingService() *BillingService {
+// NewBillingService creates a service that stores loans in repo
+func NewBillingService(repo LoanRepository) *BillingService {
 	return &BillingService{
-		loans: make(map[string]*domain.Loan),
+		repo:    repo,
+		logger:  nopLogger{},
+		metrics: nopMetrics{},
+		ids:     uuidGenerator{},
 	}
 }
 
+// SetLogger sends the service's log entries to logger; nil restores the
+// default no-op logger. Call it before the service is shared between goroutines
+func (s *BillingService) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	s.logger = logger
+}
+
+// SetMetrics reports the service's counters to metrics; nil restores the
+// default no-op recorder. Call it before the service is shared between goroutines
+func (s *BillingService) SetMetrics(metrics Metrics) {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+	s.metrics = metrics
+}
+
+// SetIDGenerator makes CreateLoanAuto take loan IDs from ids; nil restores the
+// default random UUIDs. Call it before the service is shared between goroutines
+func (s *BillingService) SetIDGenerator(ids IDGenerator) {
+	if ids == nil {
+		ids = uuidGenerator{}
+	}
+	s.ids = ids
+}
+
+// SetIDValidator makes loan creation check loan and borrower IDs with
+// validator as well as rejecting blank ones; nil removes the extra checks. Call
+// it before the service is shared between goroutines
+func (s *BillingService) SetIDValidator(validator IDValidator) {
+	s.idValidator = validator
+}
+
+// Subscribe registers a handler that is notified of every event emitted by the
+// service. Handlers are called after the loan's lock has been released
+func (s *BillingService) Subscribe(handler EventHandler) {
+	s.events.subscribe(handler)
+}
+
+// EnableAsyncEvents delivers events on a background goroutine through a queue
+// holding up to queueSize events, so slow handlers don't hold up payments.
+// When the queue is full, emitting blocks rather than dropping events.
+// By default events are delivered synchronously
+func (s *BillingService) EnableAsyncEvents(queueSize int) {
+	s.events.startAsync(queueSize)
+}
+
+// Close waits until every queued event has been delivered and stops the
+// background dispatcher. Events emitted afterwards are delivered synchronously
+func (s *BillingService) Close() error {
+	s.events.close()
+	return nil
+}
+
 // CreateLoan creates a new loan with specific terms
-// Terms: 50 weeks, 10% annual interest
-func (s *BillingService) CreateLoan(loanID, borrowerID string, principal domain.Money) (*domain.Loan, error) {
+// Use domain.DefaultTerms() for the standard 50 weeks, 10% annual interest.
+// A zero or negative principal is rejected with domain.ErrInvalidPrincipal,
+// and empty or whitespace-only IDs with ErrInvalidLoanID or ErrInvalidBorrowerID
+func (s *BillingService) CreateLoan(ctx context.Context, loanID, borrowerID string, principal domain.Money, terms domain.LoanTerms) (*domain.Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	loan, err := s.createLoan(loanID, borrowerID, principal, terms)
+	s.mu.Unlock()
+
+	s.loanCreated(loanID, borrowerID, principal, loan, err)
+	return loan, err
+}
+
+// CreateLoanAuto creates a loan like CreateLoan under an ID from the service's
+// IDGenerator (random UUIDs by default). A generated ID that is already in use
+// is replaced with a new one; after repeated collisions it returns
+// ErrLoanAlreadyExists
+func (s *BillingService) CreateLoanAuto(ctx context.Context, borrowerID string, principal domain.Money, terms domain.LoanTerms) (*domain.Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		loanID string
+		loan   *domain.Loan
+		err    error
+	)
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	for range maxIDAttempts {
+		loanID = s.ids.NewID()
+		if loan, err = s.createLoan(loanID, borrowerID, principal, terms); !errors.Is(err, ErrLoanAlreadyExists) {
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	s.loanCreated(loanID, borrowerID, principal, loan, err)
+	return loan, err
+}
+
+// CreateLoanRequest describes one loan of a CreateLoans batch
+type CreateLoanRequest struct {
+	LoanID     string
+	BorrowerID string
+	Principal  domain.Money
+	Terms      domain.LoanTerms
+}
+
+// CreateLoanResult is the outcome of one CreateLoanRequest: the created loan,
+// or the error that prevented it
+type CreateLoanResult struct {
+	LoanID string
+	Loan   *domain.Loan
+	Err    error
+}
+
+// CreateLoans creates a batch of loans under a single acquisition of the
+// service lock. Every request is attempted and nothing is rolled back; results
+// line up with requests and carry each loan or its error. err joins the
+// failures and is nil only when every loan was created
+func (s *BillingService) CreateLoans(requests []CreateLoanRequest) (results []CreateLoanResult, err error) {
+	results = make([]CreateLoanResult, len(requests))
+
+	s.mu.Lock()
+	for i, req := range requests {
+		loan, createErr := s.createLoan(req.LoanID, req.BorrowerID, req.Principal, req.Terms)
+		results[i] = CreateLoanResult{LoanID: req.LoanID, Loan: loan, Err: createErr}
+	}
+	s.mu.Unlock()
+
+	var failures []error
+	for i, req := range requests {
+		s.loanCreated(req.LoanID, req.BorrowerID, req.Principal, results[i].Loan, results[i].Err)
+		if results[i].Err != nil {
+			failures = append(failures, fmt.Errorf("loan %s: %w", req.LoanID, results[i].Err))
+		}
+	}
+
+	return results, errors.Join(failures...)
+}
+
+// loanCreated logs and records the outcome of creating a loan
+func (s *BillingService) loanCreated(loanID, borrowerID string, principal domain.Money, loan *domain.Loan, err error) {
+	if err != nil {
+		s.logger.Log(LevelError, "loan creation failed", map[string]any{"loan_id": loanID, "borrower_id": borrowerID, "error": err.Error()})
+		return
+	}
+
+	s.logger.Log(LevelInfo, "loan created", map[string]any{"loan_id": loanID, "borrower_id": borrowerID, "principal": principal.String()})
+	s.metrics.IncLoansCreated()
+	s.metrics.ObserveOutstanding(loanID, loan.GetOutstanding().Amount().InexactFloat64())
+}
+
+// createLoan builds and saves a loan; the caller must hold s.mu
+func (s *BillingService) createLoan(loanID, borrowerID string, principal domain.Money, terms domain.LoanTerms) (*domain.Loan, error) {
+	if err := validateID(loanID, s.idValidator, ErrInvalidLoanID); err != nil {
+		return nil, err
+	}
+	if err := validateID(borrowerID, s.idValidator, ErrInvalidBorrowerID); err != nil {
+		return nil, err
+	}
 
 	// Check if loan already exists
-	if _, exists := s.loans[loanID]; exists {
-		return nil, fmt.Errorf("loan with ID %s already exists", loanID)
+	if existing, _ := s.repo.FindByID(loanID); existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrLoanAlreadyExists, loanID)
 	}
 
 	// Create loan with the terms
-	annualInterestRate := decimal.NewFromFloat(0.10) // 10% per annum
-	loan := domain.NewLoan(loanID, borrowerID, principal, annualInterestRate)
+	loan, err := domain.NewLoan(loanID, borrowerID, principal, terms)
+	if err != nil {
+		return nil, err
+	}
 
-	s.loans[loanID] = loan
+	if err := s.repo.Save(loan); err != nil {
+		return nil, err
+	}
 
 	return loan, nil
 }
 
-// GetLoan retrieves a loan by ID
-func (s *BillingService) GetLoan(loanID string) (*domain.Loan, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// GetLoan retrieves a loan by ID. A missing loan is reported as ErrLoanNotFound
+// The loan is shared: call its methods, which lock it, but don't read fields
+// such as Schedule, Payments or CurrentWeek directly while other goroutines
+// may pay it, as that is a data race. Use GetLoanSnapshot for a copy to read
+func (s *BillingService) GetLoan(ctx context.Context, loanID string) (*domain.Loan, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(loanID)
+}
 
-	loan, exists := s.loans[loanID]
-	if !exists {
-		return nil, fmt.Errorf("loan with ID %s not found", loanID)
+// GetLoanSnapshot returns a deep copy of a loan, see Loan.Clone, whose fields
+// are safe to read while the loan keeps taking payments. Changes to the copy
+// don't affect the stored loan. It is a pointer because Loan holds a mutex
+func (s *BillingService) GetLoanSnapshot(loanID string) (*domain.Loan, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return nil, err
 	}
 
-	return loan, nil
+	return loan.Clone(), nil
+}
+
+// DeleteLoan removes a loan from the repository, e.g. to free memory once it
+// is settled. Only closed loans are deleted unless force is set; others are
+// refused with ErrLoanNotClosed
+func (s *BillingService) DeleteLoan(loanID string, force bool) error {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return err
+	}
+
+	if !force && !loan.IsClosed() {
+		return fmt.Errorf("%w: %s", ErrLoanNotClosed, loanID)
+	}
+
+	if err := s.repo.Delete(loanID); err != nil {
+		return err
+	}
+
+	s.logger.Log(LevelInfo, "loan deleted", map[string]any{"loan_id": loanID, "forced": force})
+	return nil
+}
+
+// LoanFilter selects loans returned by ListLoans
+// Nil or empty fields don't constrain the result
+type LoanFilter struct {
+	BorrowerID string
+	Delinquent *bool
+	Closed     *bool
+}
+
+// matches reports whether loan satisfies every constraint of the filter
+func (f LoanFilter) matches(loan *domain.Loan) bool {
+	if f.BorrowerID != "" && loan.BorrowerID != f.BorrowerID {
+		return false
+	}
+	if f.Delinquent != nil && loan.IsDelinquent() != *f.Delinquent {
+		return false
+	}
+	if f.Closed != nil && loan.IsClosed() != *f.Closed {
+		return false
+	}
+	return true
+}
+
+// ListLoans returns the loans matching filter, sorted by loan ID
+func (s *BillingService) ListLoans(filter LoanFilter) ([]*domain.Loan, error) {
+	var loans []*domain.Loan
+	var err error
+	if filter.BorrowerID != "" {
+		loans, err = s.repo.FindByBorrower(filter.BorrowerID)
+	} else {
+		loans, err = s.repo.FindAll()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*domain.Loan, 0, len(loans))
+	for _, loan := range loans {
+		if filter.matches(loan) {
+			matching = append(matching, loan)
+		}
+	}
+
+	return matching, nil
+}
+
+// LoanIDs returns the IDs of every loan in the repository, sorted
+func (s *BillingService) LoanIDs() []string {
+	ids := make([]string, 0)
+
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return ids
+	}
+
+	for _, loan := range loans {
+		ids = append(ids, loan.ID)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// AutoDebitCandidates returns the loans eligible for auto-debit, sorted by
+// loan ID. See domain.Loan.IsAutoDebitEligible
+func (s *BillingService) AutoDebitCandidates() []*domain.Loan {
+	candidates := make([]*domain.Loan, 0)
+
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return candidates
+	}
+
+	for _, loan := range loans {
+		if loan.IsAutoDebitEligible() {
+			candidates = append(candidates, loan)
+		}
+	}
+
+	return candidates
+}
+
+// AdvanceSummary reports the outcome of AdvanceWeek
+type AdvanceSummary struct {
+	Loans           int // Open loans that were advanced
+	AtFinalWeek     int // Open loans already at their last week, left where they were
+	NewlyDelinquent int // Loans that became delinquent during the advance
+}
+
+// AdvanceWeek moves every open loan forward one week, up to its duration, as a
+// batch billing job or simulation would. Closed loans are skipped and open
+// loans already at their last week are counted separately. A
+// BecameDelinquent event is emitted for every loan that becomes delinquent
+func (s *BillingService) AdvanceWeek() (AdvanceSummary, error) {
+	var summary AdvanceSummary
+
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return summary, err
+	}
+
+	delinquent := make([]*domain.Loan, 0)
+	for _, loan := range loans {
+		if loan.IsClosed() {
+			continue
+		}
+
+		advanced, becameDelinquent := loan.AdvanceWeek()
+		if becameDelinquent {
+			delinquent = append(delinquent, loan)
+		}
+		if !advanced {
+			summary.AtFinalWeek++
+			continue
+		}
+		if err := s.repo.Save(loan); err != nil {
+			return summary, err
+		}
+		summary.Loans++
+	}
+	summary.NewlyDelinquent = len(delinquent)
+
+	for _, loan := range delinquent {
+		s.events.publish(BecameDelinquent{LoanID: loan.ID, WeeksBehind: loan.WeeksBehind()})
+	}
+
+	return summary, nil
+}
+
+// LoanSummary holds the computed status of a loan at a point in time
+type LoanSummary struct {
+	Outstanding  domain.Money
+	IsDelinquent bool
+	NextDueWeek  int
+	IsClosed     bool
+}
+
+// GetLoanWithStatus returns an immutable view of a loan together with its
+// computed status. The status is derived from the view, so both are consistent
+func (s *BillingService) GetLoanWithStatus(loanID string) (*domain.LoanView, LoanSummary, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return nil, LoanSummary{}, err
+	}
+
+	view := loan.View()
+	summary := LoanSummary{
+		Outstanding:  view.Outstanding(),
+		IsDelinquent: view.IsDelinquent(),
+		NextDueWeek:  view.NextDueWeek(),
+		IsClosed:     view.IsClosed(),
+	}
+
+	return view, summary, nil
 }
 
 // GetOutstanding returns the outstanding amount for a loan
-func (s *BillingService) GetOutstanding(loanID string) (domain.Money, error) {
-	loan, err := s.GetLoan(loanID)
+func (s *BillingService) GetOutstanding(ctx context.Context, loanID string) (domain.Money, error) {
+	loan, err := s.GetLoan(ctx, loanID)
 	if err != nil {
 		return domain.Money{}, err
 	}
@@ -62,9 +425,63 @@ func (s *BillingService) GetOutstanding(loanID string) (domain.Money, error) {
 	return loan.GetOutstanding(), nil
 }
 
+// GetTotalPaid returns the sum of every payment made on a loan
+func (s *BillingService) GetTotalPaid(loanID string) (domain.Money, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return loan.TotalPaid(), nil
+}
+
+// GetPaidToDate splits what has been paid on a loan into principal and
+// interest, see domain.Loan.InterestPaidToDate
+func (s *BillingService) GetPaidToDate(loanID string) (principal, interest domain.Money, err error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.Money{}, domain.Money{}, err
+	}
+
+	return loan.PrincipalPaidToDate(), loan.InterestPaidToDate(), nil
+}
+
+// GetProgress returns how many payments have been made on a loan and its
+// duration in weeks
+func (s *BillingService) GetProgress(loanID string) (paid, durationWeeks int, err error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return loan.PaymentsMadeCount(), loan.DurationWeeks, nil
+}
+
+// GetUnpaidWeeks returns the week numbers of a loan not yet paid in full
+func (s *BillingService) GetUnpaidWeeks(loanID string) ([]int, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loan.UnpaidWeeks(), nil
+}
+
+// GetPaymentForWeek returns the payment recorded for a week of a loan and
+// whether there is one, see domain.Loan.GetPayment
+func (s *BillingService) GetPaymentForWeek(loanID string, week int) (domain.Payment, bool, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.Payment{}, false, err
+	}
+
+	payment, ok := loan.GetPayment(week)
+	return payment, ok, nil
+}
+
 // IsDelinquent checks if a borrower is delinquent on a loan
-func (s *BillingService) IsDelinquent(loanID string) (bool, error) {
-	loan, err := s.GetLoan(loanID)
+func (s *BillingService) IsDelinquent(ctx context.Context, loanID string) (bool, error) {
+	loan, err := s.GetLoan(ctx, loanID)
 	if err != nil {
 		return false, err
 	}
@@ -72,40 +489,330 @@ func (s *BillingService) IsDelinquent(loanID string) (bool, error) {
 	return loan.IsDelinquent(), nil
 }
 
+// GetStatus returns the lifecycle status of a loan
+func (s *BillingService) GetStatus(loanID string) (domain.LoanStatus, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.Active, err
+	}
+
+	return loan.Status(), nil
+}
+
+// GetDelinquencyDetails returns how far behind a loan is and how much is
+// overdue, see domain.Loan.DelinquencyInfo
+func (s *BillingService) GetDelinquencyDetails(loanID string) (domain.DelinquencyInfo, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.DelinquencyInfo{}, err
+	}
+
+	return loan.DelinquencyInfo(), nil
+}
+
+// GetSnapshot returns a point-in-time summary of a loan for audit logs, see
+// domain.Loan.Snapshot
+func (s *BillingService) GetSnapshot(loanID string) (domain.LoanSnapshot, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.LoanSnapshot{}, err
+	}
+
+	return loan.Snapshot(), nil
+}
+
 // MakePayment processes a payment on a loan
-func (s *BillingService) MakePayment(loanID string, amount domain.Money, weekNumber int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *BillingService) MakePayment(ctx context.Context, loanID string, amount domain.Money, weekNumber int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	loan, exists := s.loans[loanID]
-	if !exists {
-		return fmt.Errorf("loan with ID %s not found", loanID)
+	outstanding, err := s.makePayment(loanID, amount, weekNumber, "", "")
+	if err != nil {
+		s.paymentFailed(loanID, weekNumber, amount, err)
+		return err
 	}
 
-	return loan.MakePayment(amount, weekNumber)
+	s.paymentSucceeded(loanID, weekNumber, amount, outstanding)
+	return nil
+}
+
+// MakePaymentWithDetails processes a payment on a loan, recording how it was
+// paid. See domain.Loan.MakePaymentWithDetails
+func (s *BillingService) MakePaymentWithDetails(ctx context.Context, loanID string, amount domain.Money, weekNumber int, method, reference string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	outstanding, err := s.makePayment(loanID, amount, weekNumber, method, reference)
+	if err != nil {
+		s.paymentFailed(loanID, weekNumber, amount, err)
+		return err
+	}
+
+	s.paymentSucceeded(loanID, weekNumber, amount, outstanding)
+	return nil
+}
+
+// paymentSucceeded logs, counts and publishes the events of a recorded
+// payment that left outstanding on the loan. It is called after the loan's
+// lock has been released
+func (s *BillingService) paymentSucceeded(loanID string, week int, amount, outstanding domain.Money) {
+	s.logger.Log(LevelInfo, "payment made", map[string]any{"loan_id": loanID, "week": week, "amount": amount.String()})
+	s.metrics.IncPaymentsSucceeded()
+	s.metrics.ObserveOutstanding(loanID, outstanding.Amount().InexactFloat64())
+
+	s.events.publish(PaymentMade{LoanID: loanID, Week: week, Amount: amount})
+	if outstanding.IsZero() {
+		s.events.publish(LoanClosed{LoanID: loanID})
+	}
+}
+
+// paymentFailed logs and counts a rejected payment. week is 0 when it wasn't
+// determined
+func (s *BillingService) paymentFailed(loanID string, week int, amount domain.Money, err error) {
+	s.logger.Log(LevelError, "payment failed", map[string]any{"loan_id": loanID, "week": week, "amount": amount.String(), "error": err.Error()})
+	s.metrics.IncPaymentsFailed(failureReason(err))
+}
+
+func (s *BillingService) makePayment(loanID string, amount domain.Money, weekNumber int, method, reference string) (domain.Money, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	if err := loan.MakePaymentWithDetails(amount, weekNumber, method, reference); err != nil {
+		return domain.Money{}, err
+	}
+
+	return loan.GetOutstanding(), s.repo.Save(loan)
+}
+
+// ValidatePayment reports whether MakePayment would accept the payment,
+// without recording it. See domain.Loan.ValidatePayment
+func (s *BillingService) ValidatePayment(ctx context.Context, loanID string, amount domain.Money, weekNumber int) error {
+	loan, err := s.GetLoan(ctx, loanID)
+	if err != nil {
+		return err
+	}
+
+	return loan.ValidatePayment(amount, weekNumber)
 }
 
 // MakeNextPayment process a payment for the next due week
-func (s *BillingService) MakeNextPayment(loanID string, amount domain.Money) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *BillingService) MakeNextPayment(ctx context.Context, loanID string, amount domain.Money) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	loan, exists := s.loans[loanID]
-	if !exists {
-		return fmt.Errorf("loan with ID %s not found", loanID)
+	week, outstanding, err := s.makeNextPayment(loanID, amount)
+	if err != nil {
+		s.paymentFailed(loanID, week, amount, err)
+		return err
 	}
 
-	nextWeek := loan.GetNextDueWeek()
-	if nextWeek == 0 {
-		return domain.ErrLoanFullyPaid
+	s.paymentSucceeded(loanID, week, amount, outstanding)
+	return nil
+}
+
+func (s *BillingService) makeNextPayment(loanID string, amount domain.Money) (int, domain.Money, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return 0, domain.Money{}, err
 	}
 
-	return loan.MakePayment(amount, nextWeek)
+	nextWeek, err := loan.MakeNextPayment(amount)
+	if err != nil {
+		return 0, domain.Money{}, err
+	}
+
+	return nextWeek, loan.GetOutstanding(), s.repo.Save(loan)
+}
+
+// CatchUp pays every unpaid week of a loan up to its current week, see
+// Loan.CatchUp, and returns how many weeks it paid. On failure the weeks paid
+// before it are kept and counted. Each recorded payment is logged and emitted
+// as PaymentMade like a regular payment
+func (s *BillingService) CatchUp(loanID string) (weeksPaid int, err error) {
+	payments, outstanding, err := s.catchUp(loanID)
+	weeksPaid = s.paymentsSucceeded(loanID, payments, outstanding)
+
+	if err != nil {
+		var paymentErr *domain.PaymentError
+		if errors.As(err, &paymentErr) {
+			s.paymentFailed(loanID, paymentErr.Week, paymentErr.ProvidedAmount, err)
+		} else {
+			s.paymentFailed(loanID, 0, domain.Money{}, err)
+		}
+	}
+	return weeksPaid, err
+}
+
+// paymentsSucceeded reports each of payments, recorded together, as a
+// successful payment and returns how many distinct weeks they paid.
+// outstanding is the balance after the last of them
+func (s *BillingService) paymentsSucceeded(loanID string, payments []domain.Payment, outstanding domain.Money) (weeksPaid int) {
+	// Replay the outstanding after each payment for logging and events
+	for _, payment := range payments {
+		outstanding = outstanding.Add(payment.Amount)
+	}
+	for i, payment := range payments {
+		outstanding = outstanding.Subtract(payment.Amount)
+		s.paymentSucceeded(loanID, payment.WeekNumber, payment.Amount, outstanding)
+		if i == 0 || payment.WeekNumber != payments[i-1].WeekNumber {
+			weeksPaid++
+		}
+	}
+	return weeksPaid
+}
+
+// catchUp returns the payments recorded and the outstanding after them
+func (s *BillingService) catchUp(loanID string) ([]domain.Payment, domain.Money, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return nil, domain.Money{}, err
+	}
+
+	payments, err := loan.CatchUp()
+	if len(payments) > 0 {
+		if saveErr := s.repo.Save(loan); err == nil {
+			err = saveErr
+		}
+	}
+	return payments, loan.GetOutstanding(), err
+}
+
+// PayOverdue pays every past due week of a loan in one go, see
+// domain.Loan.PayOverdue. Each recorded payment is logged and emitted as
+// PaymentMade like a regular payment
+func (s *BillingService) PayOverdue(loanID string, amount domain.Money) error {
+	payments, outstanding, err := s.payOverdue(loanID, amount)
+	if err != nil {
+		s.paymentFailed(loanID, 0, amount, err)
+		return err
+	}
+
+	s.paymentsSucceeded(loanID, payments, outstanding)
+	return nil
+}
+
+func (s *BillingService) payOverdue(loanID string, amount domain.Money) ([]domain.Payment, domain.Money, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return nil, domain.Money{}, err
+	}
+
+	payments, err := loan.PayOverdue(amount)
+	if err != nil {
+		return nil, domain.Money{}, err
+	}
+
+	return payments, loan.GetOutstanding(), s.repo.Save(loan)
+}
+
+// PayOff settles the remaining balance of a loan in one payment
+func (s *BillingService) PayOff(loanID string, amount domain.Money) error {
+	if err := s.payOff(loanID, amount); err != nil {
+		s.logger.Log(LevelError, "payoff failed", map[string]any{"loan_id": loanID, "amount": amount.String(), "error": err.Error()})
+		s.metrics.IncPaymentsFailed(failureReason(err))
+		return err
+	}
+
+	s.logger.Log(LevelInfo, "loan paid off", map[string]any{"loan_id": loanID, "amount": amount.String()})
+	s.metrics.IncPaymentsSucceeded()
+	s.metrics.ObserveOutstanding(loanID, 0)
+	s.events.publish(LoanClosed{LoanID: loanID})
+	return nil
+}
+
+func (s *BillingService) payOff(loanID string, amount domain.Money) error {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return err
+	}
+
+	if err := loan.PayOff(amount); err != nil {
+		return err
+	}
+
+	return s.repo.Save(loan)
+}
+
+// RestructureLoan re-amortizes a loan's outstanding balance over
+// newDurationWeeks at newRate, see domain.Loan.Restructure. A
+// BecameDelinquent event is emitted if this makes the loan delinquent
+func (s *BillingService) RestructureLoan(loanID string, newDurationWeeks int, newRate decimal.Decimal) error {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return err
+	}
+
+	wasDelinquent := loan.IsDelinquent()
+	if err := loan.Restructure(newDurationWeeks, newRate); err != nil {
+		s.logger.Log(LevelError, "restructure failed", map[string]any{"loan_id": loanID, "error": err.Error()})
+		return err
+	}
+	if err := s.repo.Save(loan); err != nil {
+		return err
+	}
+	s.publishIfBecameDelinquent(loan, wasDelinquent)
+
+	outstanding := loan.GetOutstanding()
+	s.logger.Log(LevelInfo, "loan restructured", map[string]any{"loan_id": loanID, "duration_weeks": newDurationWeeks, "rate": newRate.String(), "outstanding": outstanding.String()})
+	s.metrics.ObserveOutstanding(loanID, outstanding.Amount().InexactFloat64())
+	return nil
+}
+
+// SetCurrentWeek moves a loan to week, see domain.Loan.SetCurrentWeek. A
+// BecameDelinquent event is emitted if this makes the loan delinquent
+func (s *BillingService) SetCurrentWeek(loanID string, week int) error {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return err
+	}
+
+	becameDelinquent := loan.MoveToWeek(week)
+	if err := s.repo.Save(loan); err != nil {
+		return err
+	}
+
+	if becameDelinquent {
+		s.events.publish(BecameDelinquent{LoanID: loanID, WeeksBehind: loan.WeeksBehind()})
+	}
+	return nil
+}
+
+// ReversePayment undoes the payments recorded for a week of a loan. A
+// BecameDelinquent event is emitted if this makes the loan delinquent
+func (s *BillingService) ReversePayment(loanID string, week int) error {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return err
+	}
+
+	wasDelinquent := loan.IsDelinquent()
+	if err := loan.ReversePayment(week); err != nil {
+		return err
+	}
+	if err := s.repo.Save(loan); err != nil {
+		return err
+	}
+
+	s.publishIfBecameDelinquent(loan, wasDelinquent)
+	return nil
+}
+
+// publishIfBecameDelinquent emits BecameDelinquent for a loan that is now
+// delinquent but wasn't before a change
+func (s *BillingService) publishIfBecameDelinquent(loan *domain.Loan, wasDelinquent bool) {
+	if !wasDelinquent && loan.IsDelinquent() {
+		s.events.publish(BecameDelinquent{LoanID: loan.ID, WeeksBehind: loan.WeeksBehind()})
+	}
 }
 
 // GetSchedule returns the payment schedule for a loan
-func (s *BillingService) GetSchedule(loanID string) ([]domain.ScheduleEntry, error) {
-	loan, err := s.GetLoan(loanID)
+func (s *BillingService) GetSchedule(ctx context.Context, loanID string) ([]domain.ScheduleEntry, error) {
+	loan, err := s.GetLoan(ctx, loanID)
 	if err != nil {
 		return nil, err
 	}
@@ -114,8 +821,8 @@ func (s *BillingService) GetSchedule(loanID string) ([]domain.ScheduleEntry, err
 }
 
 // GetPaymentHistory returns the payment history for a loan
-func (s *BillingService) GetPaymentHistory(loanID string) ([]domain.Payment, error) {
-	loan, err := s.GetLoan(loanID)
+func (s *BillingService) GetPaymentHistory(ctx context.Context, loanID string) ([]domain.Payment, error) {
+	loan, err := s.GetLoan(ctx, loanID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,850 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestGetLoanWithStatus(t *testing.T) {
+	svc := newTestService()
+	loan, err := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	loan.SetCurrentWeek(4)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	view, summary, err := svc.GetLoanWithStatus("loan-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	outstanding, _ := svc.GetOutstanding(t.Context(), "loan-1")
+	isDelinquent, _ := svc.IsDelinquent(t.Context(), "loan-1")
+
+	if !summary.Outstanding.Equals(outstanding) {
+		t.Errorf("Expected outstanding %s, got %s", outstanding, summary.Outstanding)
+	}
+	if summary.IsDelinquent != isDelinquent {
+		t.Errorf("Expected delinquent=%v, got %v", isDelinquent, summary.IsDelinquent)
+	}
+	if summary.NextDueWeek != loan.GetNextDueWeek() {
+		t.Errorf("Expected next due week %d, got %d", loan.GetNextDueWeek(), summary.NextDueWeek)
+	}
+	if summary.IsClosed != loan.IsClosed() {
+		t.Errorf("Expected closed=%v, got %v", loan.IsClosed(), summary.IsClosed)
+	}
+	if view.ID() != "loan-1" || view.CurrentWeek() != 4 {
+		t.Errorf("Expected view of loan-1 at week 4, got %s at week %d", view.ID(), view.CurrentWeek())
+	}
+
+	// The view is detached from later changes
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2)
+	if !view.Outstanding().Equals(outstanding) {
+		t.Errorf("Expected view outstanding to remain %s, got %s", outstanding, view.Outstanding())
+	}
+	if len(view.Payments()) != 1 {
+		t.Errorf("Expected view to keep 1 payment, got %d", len(view.Payments()))
+	}
+}
+
+func TestGetLoanWithStatus_NotFound(t *testing.T) {
+	svc := newTestService()
+
+	if _, _, err := svc.GetLoanWithStatus("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestLoanNotFound(t *testing.T) {
+	svc := newTestService()
+
+	if _, err := svc.GetLoan(t.Context(), "missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("GetLoan: expected ErrLoanNotFound, got %v", err)
+	}
+	if err := svc.MakePayment(t.Context(), "missing", domain.NewMoney(110000), 1); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("MakePayment: expected ErrLoanNotFound, got %v", err)
+	}
+	if err := svc.MakeNextPayment(t.Context(), "missing", domain.NewMoney(110000)); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("MakeNextPayment: expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	if _, err := svc.CreateLoan(ctx, "loan-2", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms()); !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateLoan: expected context.Canceled, got %v", err)
+	}
+	if _, err := svc.GetLoan(t.Context(), "loan-2"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected loan-2 not to be created, got %v", err)
+	}
+	if _, err := svc.GetOutstanding(ctx, "loan-1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetOutstanding: expected context.Canceled, got %v", err)
+	}
+	if err := svc.MakePayment(ctx, "loan-1", domain.NewMoney(110000), 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("MakePayment: expected context.Canceled, got %v", err)
+	}
+	if err := svc.MakeNextPayment(ctx, "loan-1", domain.NewMoney(110000)); !errors.Is(err, context.Canceled) {
+		t.Errorf("MakeNextPayment: expected context.Canceled, got %v", err)
+	}
+
+	// Nothing was paid
+	history, _ := svc.GetPaymentHistory(t.Context(), "loan-1")
+	if len(history) != 0 {
+		t.Errorf("Expected no payments, got %d", len(history))
+	}
+}
+
+func TestCreateLoan_Principal(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal domain.Money
+		expected  error
+	}{
+		{"Zero", domain.NewMoney(0), domain.ErrInvalidPrincipal},
+		{"Negative", domain.NewMoney(-5000000), domain.ErrInvalidPrincipal},
+		{"Normal", domain.NewMoney(5000000), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+
+			_, err := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", tt.principal, domain.DefaultTerms())
+			if err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+
+			// Rejected loans are not stored
+			if _, err := svc.GetLoan(t.Context(), "loan-1"); (err == nil) != (tt.expected == nil) {
+				t.Errorf("Expected loan stored=%v, got lookup error %v", tt.expected == nil, err)
+			}
+		})
+	}
+}
+
+func TestCreateLoan_IDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		loanID     string
+		borrowerID string
+		expected   error
+	}{
+		{"Valid", "loan-1", "borrower-1", nil},
+		{"Empty loan ID", "", "borrower-1", ErrInvalidLoanID},
+		{"Whitespace loan ID", " \t", "borrower-1", ErrInvalidLoanID},
+		{"Empty borrower ID", "loan-1", "", ErrInvalidBorrowerID},
+		{"Whitespace borrower ID", "loan-1", "   ", ErrInvalidBorrowerID},
+		{"Rejected by validator", "loan/1", "borrower-1", ErrInvalidLoanID},
+		{"Too long for validator", "loan-1", strings.Repeat("b", 33), ErrInvalidBorrowerID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+			svc.SetIDValidator(IDValidatorFunc(func(id string) error {
+				if len(id) > 32 || strings.ContainsRune(id, '/') {
+					return errors.New("at most 32 characters, no slashes")
+				}
+				return nil
+			}))
+
+			if _, err := svc.CreateLoan(t.Context(), tt.loanID, tt.borrowerID, domain.NewMoney(5000000), domain.DefaultTerms()); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// Without a validator only blank IDs are rejected
+	svc := newTestService()
+	if _, err := svc.CreateLoan(t.Context(), "loan/1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms()); err != nil {
+		t.Errorf("Expected loan/1 to be accepted, got %v", err)
+	}
+}
+
+func TestCreateLoan_AlreadyExists(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	_, err := svc.CreateLoan(t.Context(), "loan-1", "borrower-2", domain.NewMoney(1000000), domain.DefaultTerms())
+	if !errors.Is(err, ErrLoanAlreadyExists) {
+		t.Errorf("Expected ErrLoanAlreadyExists, got %v", err)
+	}
+}
+
+func TestCreateLoans(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "existing", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	results, err := svc.CreateLoans([]CreateLoanRequest{
+		{LoanID: "loan-1", BorrowerID: "borrower-1", Principal: domain.NewMoney(5000000), Terms: domain.DefaultTerms()},
+		{LoanID: "existing", BorrowerID: "borrower-2", Principal: domain.NewMoney(1000000), Terms: domain.DefaultTerms()},
+		{LoanID: "loan-2", BorrowerID: "borrower-2", Principal: domain.NewMoney(0), Terms: domain.DefaultTerms()},
+		{LoanID: "loan-1", BorrowerID: "borrower-3", Principal: domain.NewMoney(1000000), Terms: domain.DefaultTerms()},
+		{LoanID: "loan-3", BorrowerID: "borrower-3", Principal: domain.NewMoney(1000000), Terms: domain.DefaultTerms()},
+	})
+
+	if !errors.Is(err, ErrLoanAlreadyExists) || !errors.Is(err, domain.ErrInvalidPrincipal) {
+		t.Errorf("Expected joined batch errors, got %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(results))
+	}
+
+	wantErrs := []error{nil, ErrLoanAlreadyExists, domain.ErrInvalidPrincipal, ErrLoanAlreadyExists, nil}
+	for i, want := range wantErrs {
+		result := results[i]
+		if want == nil {
+			if result.Err != nil || result.Loan == nil {
+				t.Errorf("Result %d (%s): expected a loan, got %v", i, result.LoanID, result.Err)
+			}
+			continue
+		}
+		if !errors.Is(result.Err, want) || result.Loan != nil {
+			t.Errorf("Result %d (%s): expected %v, got %v", i, result.LoanID, want, result.Err)
+		}
+	}
+
+	// Successful loans are kept even though others in the batch failed
+	loan, err := svc.GetLoan(t.Context(), "loan-1")
+	if err != nil || loan.BorrowerID != "borrower-1" {
+		t.Errorf("Expected loan-1 to belong to borrower-1, got %v", err)
+	}
+	if _, err := svc.GetLoan(t.Context(), "loan-3"); err != nil {
+		t.Errorf("Expected loan-3 to be created, got %v", err)
+	}
+
+	if _, err := svc.CreateLoans(nil); err != nil {
+		t.Errorf("Expected empty batch to succeed, got %v", err)
+	}
+}
+
+func TestCreateLoanAuto(t *testing.T) {
+	svc := newTestService()
+
+	first, err := svc.CreateLoanAuto(t.Context(), "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	if err != nil {
+		t.Fatalf("CreateLoanAuto failed: %v", err)
+	}
+	second, err := svc.CreateLoanAuto(t.Context(), "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	if err != nil {
+		t.Fatalf("CreateLoanAuto failed: %v", err)
+	}
+	if first.ID == "" || first.ID == second.ID {
+		t.Errorf("Expected distinct IDs, got %q and %q", first.ID, second.ID)
+	}
+	if loan, _ := svc.GetLoan(t.Context(), second.ID); loan == nil {
+		t.Errorf("Expected %s to be saved", second.ID)
+	}
+
+	// Collisions are retried with a fresh ID
+	ids := []string{first.ID, second.ID, "loan-3"}
+	svc.SetIDGenerator(IDGeneratorFunc(func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}))
+	if loan, err := svc.CreateLoanAuto(t.Context(), "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms()); err != nil || loan.ID != "loan-3" {
+		t.Errorf("Expected loan-3 after two collisions, got %v (%v)", loan, err)
+	}
+
+	svc.SetIDGenerator(IDGeneratorFunc(func() string { return "loan-3" }))
+	if _, err := svc.CreateLoanAuto(t.Context(), "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms()); !errors.Is(err, ErrLoanAlreadyExists) {
+		t.Errorf("Expected ErrLoanAlreadyExists when every ID collides, got %v", err)
+	}
+}
+
+func TestPayOff(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	if err := svc.PayOff("loan-1", domain.NewMoney(5500000)); err != nil {
+		t.Fatalf("Expected payoff to succeed, got %v", err)
+	}
+
+	outstanding, _ := svc.GetOutstanding(t.Context(), "loan-1")
+	if !outstanding.IsZero() {
+		t.Errorf("Expected zero outstanding, got %s", outstanding)
+	}
+
+	if err := svc.PayOff("missing", domain.NewMoney(5500000)); err == nil {
+		t.Error("Expected error for missing loan")
+	}
+}
+
+func TestDeleteLoan(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "paid", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "active", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.PayOff("paid", domain.NewMoney(5500000))
+
+	if err := svc.DeleteLoan("paid", false); err != nil {
+		t.Errorf("Expected paid loan to be deleted, got %v", err)
+	}
+	if _, err := svc.GetLoan(t.Context(), "paid"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected deleted loan to be gone, got %v", err)
+	}
+
+	// Active loans are kept unless forced
+	if err := svc.DeleteLoan("active", false); !errors.Is(err, ErrLoanNotClosed) {
+		t.Errorf("Expected ErrLoanNotClosed, got %v", err)
+	}
+	if _, err := svc.GetLoan(t.Context(), "active"); err != nil {
+		t.Errorf("Expected active loan to remain, got %v", err)
+	}
+	if err := svc.DeleteLoan("active", true); err != nil {
+		t.Errorf("Expected forced delete to succeed, got %v", err)
+	}
+
+	if err := svc.DeleteLoan("missing", true); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+
+	// The ID can be reused once deleted
+	if _, err := svc.CreateLoan(t.Context(), "paid", "borrower-2", domain.NewMoney(1000000), domain.DefaultTerms()); err != nil {
+		t.Errorf("Expected deleted ID to be reusable, got %v", err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	svc := newTestService()
+	loan, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	status, err := svc.GetStatus("loan-1")
+	if err != nil || status != domain.Active {
+		t.Errorf("Expected active status, got %s (err %v)", status, err)
+	}
+
+	loan.SetCurrentWeek(3)
+	if status, _ := svc.GetStatus("loan-1"); status != domain.Delinquent {
+		t.Errorf("Expected delinquent status, got %s", status)
+	}
+
+	if _, err := svc.GetStatus("missing"); err == nil {
+		t.Error("Expected error for missing loan")
+	}
+}
+
+func TestValidatePayment(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	if err := svc.ValidatePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1); err != nil {
+		t.Errorf("Expected valid payment, got %v", err)
+	}
+	if err := svc.ValidatePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2); !errors.Is(err, domain.ErrPaymentOutOfSequence) {
+		t.Errorf("Expected ErrPaymentOutOfSequence, got %v", err)
+	}
+	if err := svc.ValidatePayment(t.Context(), "missing", domain.NewMoney(110000), 1); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+
+	if history, _ := svc.GetPaymentHistory(t.Context(), "loan-1"); len(history) != 0 {
+		t.Errorf("Expected no payments recorded, got %d", len(history))
+	}
+}
+
+func TestGetDelinquencyDetails(t *testing.T) {
+	svc := newTestService()
+	loan, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	loan.SetCurrentWeek(4)
+
+	info, err := svc.GetDelinquencyDetails("loan-1")
+	if err != nil {
+		t.Fatalf("GetDelinquencyDetails failed: %v", err)
+	}
+	if !info.IsDelinquent || info.WeeksBehind != 3 || info.LastPaidWeek != 1 || !info.OverdueAmount.Equals(domain.NewMoney(330000)) {
+		t.Errorf("Unexpected delinquency details %+v", info)
+	}
+
+	if _, err := svc.GetDelinquencyDetails("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestGetSnapshot(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	snapshot, err := svc.GetSnapshot("loan-1")
+	if err != nil {
+		t.Fatalf("GetSnapshot failed: %v", err)
+	}
+	if snapshot.LoanID != "loan-1" || snapshot.Outstanding != "5390000" || snapshot.WeeksPaid != 1 {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+
+	if _, err := svc.GetSnapshot("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestGetTotalPaidAndProgress(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2)
+
+	total, err := svc.GetTotalPaid("loan-1")
+	if err != nil || !total.Equals(domain.NewMoney(220000)) {
+		t.Errorf("Expected IDR 220000 paid, got %s (%v)", total, err)
+	}
+
+	paid, duration, err := svc.GetProgress("loan-1")
+	if err != nil || paid != 2 || duration != 50 {
+		t.Errorf("Expected progress 2 / 50, got %d / %d (%v)", paid, duration, err)
+	}
+
+	if _, err := svc.GetTotalPaid("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("GetTotalPaid: expected ErrLoanNotFound, got %v", err)
+	}
+	if _, _, err := svc.GetProgress("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("GetProgress: expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestGetPaidToDate(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2)
+
+	principal, interest, err := svc.GetPaidToDate("loan-1")
+	if err != nil || !principal.Equals(domain.NewMoney(200000)) || !interest.Equals(domain.NewMoney(20000)) {
+		t.Errorf("Expected IDR 200000 principal and IDR 20000 interest, got %s and %s (%v)", principal, interest, err)
+	}
+
+	if _, _, err := svc.GetPaidToDate("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestRestructureLoan(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	if err := svc.RestructureLoan("loan-1", 10, decimal.Zero); err != nil {
+		t.Fatalf("RestructureLoan failed: %v", err)
+	}
+	schedule, _ := svc.GetSchedule(t.Context(), "loan-1")
+	if len(schedule) != 11 || !schedule[1].Amount.Equals(domain.NewMoney(539000)) {
+		t.Errorf("Expected 10 installments of IDR 539000 after week 1, got %d entries", len(schedule))
+	}
+
+	if err := svc.RestructureLoan("missing", 10, decimal.Zero); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestGetUnpaidWeeks(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2)
+
+	weeks, err := svc.GetUnpaidWeeks("loan-1")
+	if err != nil {
+		t.Fatalf("GetUnpaidWeeks failed: %v", err)
+	}
+	if len(weeks) != 48 || weeks[0] != 3 || weeks[47] != 50 {
+		t.Errorf("Expected weeks 3 to 50 unpaid, got %v", weeks)
+	}
+
+	if _, err := svc.GetUnpaidWeeks("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestGetPaymentForWeek(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePaymentWithDetails(t.Context(), "loan-1", domain.NewMoney(110000), 1, "bank_transfer", "trx-1")
+
+	payment, ok, err := svc.GetPaymentForWeek("loan-1", 1)
+	if err != nil || !ok {
+		t.Fatalf("Expected week 1's payment, got %v, %v", ok, err)
+	}
+	if payment.WeekNumber != 1 || !payment.Amount.Equals(domain.NewMoney(110000)) || payment.Reference != "trx-1" {
+		t.Errorf("Expected IDR 110000 for week 1 with reference trx-1, got %+v", payment)
+	}
+
+	if _, ok, err := svc.GetPaymentForWeek("loan-1", 2); err != nil || ok {
+		t.Errorf("Expected no payment for unpaid week 2, got %v, %v", ok, err)
+	}
+
+	if _, _, err := svc.GetPaymentForWeek("missing", 1); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestPayOverdue(t *testing.T) {
+	svc := newTestService()
+	handler := &recordingHandler{}
+	svc.Subscribe(handler)
+
+	terms := domain.DefaultTerms()
+	terms.RequireCatchUp = true
+	loan, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), terms)
+	loan.SetCurrentWeek(3)
+
+	if err := svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1); !errors.Is(err, domain.ErrMustCatchUp) {
+		t.Errorf("Expected ErrMustCatchUp, got %v", err)
+	}
+
+	if err := svc.PayOverdue("loan-1", domain.NewMoney(220000)); err != nil {
+		t.Fatalf("PayOverdue failed: %v", err)
+	}
+	if outstanding, _ := svc.GetOutstanding(t.Context(), "loan-1"); !outstanding.Equals(domain.NewMoney(5280000)) {
+		t.Errorf("Expected outstanding IDR 5280000, got %s", outstanding)
+	}
+
+	payments := 0
+	for _, event := range handler.Events() {
+		if _, ok := event.(PaymentMade); ok {
+			payments++
+		}
+	}
+	if payments != 2 {
+		t.Errorf("Expected a PaymentMade event per week, got %d", payments)
+	}
+
+	if err := svc.PayOverdue("missing", domain.NewMoney(110000)); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestLoanIDs(t *testing.T) {
+	svc := newTestService()
+	if ids := svc.LoanIDs(); len(ids) != 0 {
+		t.Errorf("Expected no IDs, got %v", ids)
+	}
+
+	for _, id := range []string{"loan-b", "loan-c", "loan-a"} {
+		svc.CreateLoan(t.Context(), id, "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	}
+
+	if ids := svc.LoanIDs(); !slices.Equal(ids, []string{"loan-a", "loan-b", "loan-c"}) {
+		t.Errorf("Expected sorted IDs, got %v", ids)
+	}
+}
+
+func TestListLoans(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-3", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	delinquent, _ := svc.CreateLoan(t.Context(), "loan-2", "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms())
+	closed, _ := svc.CreateLoan(t.Context(), "loan-4", "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	delinquent.SetCurrentWeek(3)
+	closed.PayOff(domain.NewMoney(5500000))
+
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		filter   LoanFilter
+		expected []string
+	}{
+		{"No filter", LoanFilter{}, []string{"loan-1", "loan-2", "loan-3", "loan-4"}},
+		{"By borrower", LoanFilter{BorrowerID: "borrower-1"}, []string{"loan-1", "loan-3"}},
+		{"Delinquent", LoanFilter{Delinquent: &yes}, []string{"loan-2"}},
+		{"Closed", LoanFilter{Closed: &yes}, []string{"loan-4"}},
+		{"Open for borrower", LoanFilter{BorrowerID: "borrower-2", Closed: &no}, []string{"loan-2"}},
+		{"Unknown borrower", LoanFilter{BorrowerID: "borrower-9"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loans, err := svc.ListLoans(tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := loanIDs(loans); !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAutoDebitCandidates(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	delinquent, _ := svc.CreateLoan(t.Context(), "loan-2", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	suspended, _ := svc.CreateLoan(t.Context(), "loan-3", "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms())
+	closed, _ := svc.CreateLoan(t.Context(), "loan-4", "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-5", "borrower-3", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	delinquent.SetCurrentWeek(3)
+	suspended.Suspend()
+	closed.PayOff(domain.NewMoney(5500000))
+
+	candidates := svc.AutoDebitCandidates()
+	if got := loanIDs(candidates); !slices.Equal(got, []string{"loan-1", "loan-5"}) {
+		t.Errorf("Expected [loan-1 loan-5], got %v", got)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	svc := newTestService()
+	recorder := &recordingHandler{}
+	svc.Subscribe(recorder)
+	loan, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	// Weeks 2-4 unpaid at week 4
+	svc.SetCurrentWeek("loan-1", 4)
+	if delinquent, _ := svc.IsDelinquent(t.Context(), "loan-1"); !delinquent {
+		t.Fatalf("Expected the loan to be delinquent before catching up")
+	}
+
+	weeksPaid, err := svc.CatchUp("loan-1")
+	if err != nil || weeksPaid != 3 {
+		t.Fatalf("Expected 3 weeks paid, got %d (%v)", weeksPaid, err)
+	}
+	if delinquent, _ := svc.IsDelinquent(t.Context(), "loan-1"); delinquent {
+		t.Errorf("Expected the loan not to be delinquent after catching up")
+	}
+	if next := loan.GetNextDueWeek(); next != 5 {
+		t.Errorf("Expected week 5 to be next, got %d", next)
+	}
+	outstanding, _ := svc.GetOutstanding(t.Context(), "loan-1")
+	if !outstanding.Equals(domain.NewMoney(5500000 - 4*110000)) {
+		t.Errorf("Expected outstanding 5060000, got %s", outstanding)
+	}
+
+	var payments int
+	for _, event := range recorder.Events() {
+		if _, ok := event.(PaymentMade); ok {
+			payments++
+		}
+	}
+	if payments != 4 {
+		t.Errorf("Expected a PaymentMade event per week, got %d in total", payments)
+	}
+
+	// Nothing left to catch up on
+	if weeksPaid, err := svc.CatchUp("loan-1"); err != nil || weeksPaid != 0 {
+		t.Errorf("Expected nothing to pay, got %d (%v)", weeksPaid, err)
+	}
+
+	if _, err := svc.CatchUp("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestReversePayment(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2)
+
+	if err := svc.ReversePayment("loan-1", 1); err != domain.ErrReversalOutOfSequence {
+		t.Errorf("Expected ErrReversalOutOfSequence, got %v", err)
+	}
+
+	if err := svc.ReversePayment("loan-1", 2); err != nil {
+		t.Fatalf("Expected reversal to succeed, got %v", err)
+	}
+	outstanding, _ := svc.GetOutstanding(t.Context(), "loan-1")
+	if !outstanding.Equals(domain.NewMoney(5390000)) {
+		t.Errorf("Expected outstanding IDR 5390000, got %s", outstanding)
+	}
+
+	if err := svc.ReversePayment("missing", 1); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestReversePayment_BecameDelinquent(t *testing.T) {
+	svc := newTestService()
+	loan, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 2)
+	loan.SetCurrentWeek(3)
+
+	recorder := &recordingHandler{}
+	svc.Subscribe(recorder)
+
+	// One week behind at week 3, two once week 2 is reversed
+	if err := svc.ReversePayment("loan-1", 2); err != nil {
+		t.Fatalf("Expected reversal to succeed, got %v", err)
+	}
+	events := recorder.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if event, ok := events[0].(BecameDelinquent); !ok || event.LoanID != "loan-1" || event.WeeksBehind != 2 {
+		t.Errorf("Expected BecameDelinquent for loan-1 2 weeks behind, got %+v", events[0])
+	}
+
+	// Already delinquent: no new transition
+	if err := svc.ReversePayment("loan-1", 1); err != nil {
+		t.Fatalf("Expected reversal to succeed, got %v", err)
+	}
+	if events := recorder.Events(); len(events) != 1 {
+		t.Errorf("Expected no further events, got %d", len(events))
+	}
+}
+
+func TestAdvanceWeek(t *testing.T) {
+	svc := newTestService()
+	recorder := &recordingHandler{}
+	svc.Subscribe(recorder)
+
+	onTime, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	behind, _ := svc.CreateLoan(t.Context(), "loan-2", "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms())
+	closed, _ := svc.CreateLoan(t.Context(), "loan-3", "borrower-3", domain.NewMoney(5000000), domain.DefaultTerms())
+	final, _ := svc.CreateLoan(t.Context(), "loan-4", "borrower-4", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	onTime.MakePayment(domain.NewMoney(110000), 1)
+	onTime.MakePayment(domain.NewMoney(110000), 2)
+	behind.MakePayment(domain.NewMoney(110000), 1)
+	behind.SetCurrentWeek(2)
+	closed.PayOff(domain.NewMoney(5500000))
+	final.SetCurrentWeek(domain.LoanDurationWeeks)
+
+	summary, err := svc.AdvanceWeek()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Loans != 2 || summary.AtFinalWeek != 1 || summary.NewlyDelinquent != 1 {
+		t.Errorf("Expected 2 loans advanced, 1 at its final week and 1 newly delinquent, got %+v", summary)
+	}
+
+	if onTime.CurrentWeek != 2 || behind.CurrentWeek != 3 || closed.CurrentWeek != 1 || final.CurrentWeek != domain.LoanDurationWeeks {
+		t.Errorf("Expected weeks 2, 3, 1 and %d, got %d, %d, %d and %d", domain.LoanDurationWeeks, onTime.CurrentWeek, behind.CurrentWeek, closed.CurrentWeek, final.CurrentWeek)
+	}
+
+	events := recorder.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if event, ok := events[0].(BecameDelinquent); !ok || event.LoanID != "loan-2" {
+		t.Errorf("Expected BecameDelinquent for loan-2, got %+v", events[0])
+	}
+}
+
+func TestMakeNextPayment_Concurrent(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-2", "borrower-2", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	var wg sync.WaitGroup
+	for _, loanID := range []string{"loan-1", "loan-2"} {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := svc.MakeNextPayment(t.Context(), loanID, domain.NewMoney(110000)); err != nil {
+					t.Errorf("Unexpected error paying %s: %v", loanID, err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	// Each payment lands on its own week
+	for _, loanID := range []string{"loan-1", "loan-2"} {
+		history, _ := svc.GetPaymentHistory(t.Context(), loanID)
+		weeks := make(map[int]bool)
+		for _, payment := range history {
+			weeks[payment.WeekNumber] = true
+		}
+		if len(history) != 10 || len(weeks) != 10 {
+			t.Errorf("Expected 10 payments on distinct weeks for %s, got %d on %d weeks", loanID, len(history), len(weeks))
+		}
+	}
+}
+
+func TestGetLoanSnapshot_ConcurrentReads(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			svc.MakeNextPayment(t.Context(), "loan-1", domain.NewMoney(110000))
+		}
+	}()
+
+	// Reading the copy's fields directly races with nothing (run with -race)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				snapshot, err := svc.GetLoanSnapshot("loan-1")
+				if err != nil {
+					t.Errorf("GetLoanSnapshot failed: %v", err)
+					return
+				}
+
+				paid := 0
+				for _, entry := range snapshot.Schedule {
+					if entry.IsPaid {
+						paid++
+					}
+				}
+				if paid != len(snapshot.Payments) {
+					t.Errorf("Expected %d paid weeks to match %d payments", paid, len(snapshot.Payments))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The copy is detached from the stored loan
+	snapshot, _ := svc.GetLoanSnapshot("loan-1")
+	snapshot.Payments = nil
+	snapshot.Schedule[0].IsPaid = false
+	if history, _ := svc.GetPaymentHistory(t.Context(), "loan-1"); len(history) != 50 {
+		t.Errorf("Expected the stored loan to keep 50 payments, got %d", len(history))
+	}
+	if loan, _ := svc.GetLoan(t.Context(), "loan-1"); !loan.IsClosed() {
+		t.Errorf("Expected the stored loan to stay closed")
+	}
+
+	if _, err := svc.GetLoanSnapshot("missing"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+// Helper functions
+func newTestService() *BillingService {
+	return NewBillingService(NewInMemoryRepository())
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func newLoanStartingAt(t *testing.T, loanID, borrowerID string, start time.Time) *domain.Loan {
+	t.Helper()
+
+	loan, err := domain.NewLoanWithClock(loanID, borrowerID, domain.NewMoney(5000000), domain.DefaultTerms(), fixedClock{now: start})
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	return loan
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// BorrowerSummary aggregates a borrower's exposure across all their loans
+type BorrowerSummary struct {
+	BorrowerID       string
+	LoanCount        int
+	TotalOutstanding domain.Money
+	DelinquentCount  int
+	MaxWeeksBehind   int // Worst WeeksBehind among the loans, 0 when none is behind
+}
+
+// GetBorrowerSummary sums up every loan held by a borrower. A borrower without
+// loans gets an empty summary. Loans in different currencies can't be added
+// up and return an error wrapping domain.ErrCurrencyMismatch
+func (s *BillingService) GetBorrowerSummary(borrowerID string) (BorrowerSummary, error) {
+	loans, err := s.repo.FindByBorrower(borrowerID)
+	if err != nil {
+		return BorrowerSummary{}, err
+	}
+
+	summary := BorrowerSummary{
+		BorrowerID:       borrowerID,
+		LoanCount:        len(loans),
+		TotalOutstanding: domain.NewMoney(0),
+	}
+	if len(loans) > 0 {
+		summary.TotalOutstanding = domain.NewMoneyWithCurrency(0, loans[0].Principal.Currency())
+	}
+
+	for _, loan := range loans {
+		outstanding := loan.GetOutstanding()
+		if outstanding.Currency() != summary.TotalOutstanding.Currency() {
+			return BorrowerSummary{}, fmt.Errorf("%w: borrower %s has loans in %s and %s",
+				domain.ErrCurrencyMismatch, borrowerID, summary.TotalOutstanding.Currency(), outstanding.Currency())
+		}
+		summary.TotalOutstanding = summary.TotalOutstanding.Add(outstanding)
+
+		info := loan.DelinquencyInfo()
+		if info.IsDelinquent {
+			summary.DelinquentCount++
+		}
+		summary.MaxWeeksBehind = max(summary.MaxWeeksBehind, info.WeeksBehind)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestGetBorrowerSummary(t *testing.T) {
+	svc := newTestService()
+	current, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	behind, _ := svc.CreateLoan(t.Context(), "loan-2", "borrower-1", domain.NewMoney(1000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-3", "borrower-2", domain.NewMoney(2000000), domain.DefaultTerms())
+
+	// loan-1 is up to date, loan-2 is 4 weeks behind
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	current.SetCurrentWeek(2)
+	behind.SetCurrentWeek(4)
+
+	summary, err := svc.GetBorrowerSummary("borrower-1")
+	if err != nil {
+		t.Fatalf("GetBorrowerSummary failed: %v", err)
+	}
+	want := BorrowerSummary{
+		BorrowerID:       "borrower-1",
+		LoanCount:        2,
+		TotalOutstanding: domain.NewMoney(5390000 + 1100000),
+		DelinquentCount:  1,
+		MaxWeeksBehind:   4,
+	}
+	if summary.BorrowerID != want.BorrowerID || summary.LoanCount != want.LoanCount ||
+		!summary.TotalOutstanding.Equals(want.TotalOutstanding) ||
+		summary.DelinquentCount != want.DelinquentCount || summary.MaxWeeksBehind != want.MaxWeeksBehind {
+		t.Errorf("Expected %+v, got %+v", want, summary)
+	}
+
+	// Deleted loans drop out of the borrower's summary
+	svc.DeleteLoan("loan-2", true)
+	summary, _ = svc.GetBorrowerSummary("borrower-1")
+	if summary.LoanCount != 1 || summary.DelinquentCount != 0 || !summary.TotalOutstanding.Equals(domain.NewMoney(5390000)) {
+		t.Errorf("Expected only loan-1 after deleting loan-2, got %+v", summary)
+	}
+
+	empty, err := svc.GetBorrowerSummary("nobody")
+	if err != nil || empty.LoanCount != 0 || !empty.TotalOutstanding.IsZero() {
+		t.Errorf("Expected an empty summary, got %+v (%v)", empty, err)
+	}
+}
+
+func TestGetBorrowerSummary_MixedCurrencies(t *testing.T) {
+	svc := newTestService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-2", "borrower-1", domain.NewMoneyWithCurrency(5000, domain.USD), domain.DefaultTerms())
+
+	if _, err := svc.GetBorrowerSummary("borrower-1"); !errors.Is(err, domain.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
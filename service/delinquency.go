@@ -0,0 +1,47 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// DelinquencyReportEntry describes a delinquent loan for collections
+type DelinquencyReportEntry struct {
+	LoanID        string
+	BorrowerID    string
+	WeeksBehind   int
+	OverdueAmount domain.Money // See domain.DelinquencyInfo
+}
+
+// DelinquentLoans returns an entry for every currently delinquent loan, most
+// weeks behind first and then by loan ID. Each entry is read from a single
+// domain.Loan.DelinquencyInfo call, so it agrees with IsDelinquent
+func (s *BillingService) DelinquentLoans() []DelinquencyReportEntry {
+	report := make([]DelinquencyReportEntry, 0)
+
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return report
+	}
+
+	for _, loan := range loans {
+		info := loan.DelinquencyInfo()
+		if !info.IsDelinquent {
+			continue
+		}
+
+		report = append(report, DelinquencyReportEntry{
+			LoanID:        loan.ID,
+			BorrowerID:    loan.BorrowerID,
+			WeeksBehind:   info.WeeksBehind,
+			OverdueAmount: info.OverdueAmount,
+		})
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].WeeksBehind > report[j].WeeksBehind
+	})
+
+	return report
+}
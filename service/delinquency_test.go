@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestDelinquentLoans(t *testing.T) {
+	svc := newTestService()
+	create := func(loanID, borrowerID string, currentWeek int, paidWeeks int) {
+		loan, err := svc.CreateLoan(t.Context(), loanID, borrowerID, domain.NewMoney(5000000), domain.DefaultTerms())
+		if err != nil {
+			t.Fatalf("CreateLoan %s failed: %v", loanID, err)
+		}
+		for week := 1; week <= paidWeeks; week++ {
+			loan.MakePayment(domain.NewMoney(110000), week)
+		}
+		loan.SetCurrentWeek(currentWeek)
+	}
+
+	create("loan-a", "borrower-1", 3, 0)  // 3 behind
+	create("loan-b", "borrower-2", 5, 0)  // 5 behind
+	create("loan-c", "borrower-1", 3, 2)  // 1 behind, current
+	create("loan-d", "borrower-3", 6, 1)  // 5 behind
+	create("loan-e", "borrower-4", 1, 50) // closed
+
+	report := svc.DelinquentLoans()
+
+	want := []DelinquencyReportEntry{
+		{LoanID: "loan-b", BorrowerID: "borrower-2", WeeksBehind: 5, OverdueAmount: domain.NewMoney(550000)},
+		{LoanID: "loan-d", BorrowerID: "borrower-3", WeeksBehind: 5, OverdueAmount: domain.NewMoney(550000)},
+		{LoanID: "loan-a", BorrowerID: "borrower-1", WeeksBehind: 3, OverdueAmount: domain.NewMoney(330000)},
+	}
+	if len(report) != len(want) {
+		t.Fatalf("Expected %d delinquent loans, got %+v", len(want), report)
+	}
+	for i, entry := range report {
+		if entry.LoanID != want[i].LoanID || entry.BorrowerID != want[i].BorrowerID ||
+			entry.WeeksBehind != want[i].WeeksBehind || !entry.OverdueAmount.Equals(want[i].OverdueAmount) {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, want[i], entry)
+		}
+	}
+
+	if report := newTestService().DelinquentLoans(); len(report) != 0 {
+		t.Errorf("Expected an empty report without loans, got %+v", report)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// duplicateKey identifies loans that look like copies of each other
+type duplicateKey struct {
+	borrowerID string
+	principal  string
+	startDay   string
+}
+
+// FindPotentialDuplicates groups loans that share a borrower, principal and
+// start (creation) date, for manual review after migrations. Only groups with
+// at least two loans are returned, each sorted by loan ID. Nothing is modified
+func (s *BillingService) FindPotentialDuplicates() [][]*domain.Loan {
+	groups := make([][]*domain.Loan, 0)
+
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return groups
+	}
+
+	byKey := make(map[duplicateKey][]*domain.Loan)
+	for _, loan := range loans {
+		key := duplicateKey{
+			borrowerID: loan.BorrowerID,
+			principal:  loan.Principal.Amount().String(),
+			startDay:   loan.StartDate.UTC().Format("2006-01-02"),
+		}
+		byKey[key] = append(byKey[key], loan)
+	}
+
+	for _, group := range byKey {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].ID < group[j].ID
+		})
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0].ID < groups[j][0].ID
+	})
+
+	return groups
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestFindPotentialDuplicates(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	repo := NewInMemoryRepository()
+	svc := NewBillingService(repo)
+
+	// Same borrower, principal and creation day
+	repo.Save(newLoanStartingAt(t, "loan-1", "borrower-1", start))
+	repo.Save(newLoanStartingAt(t, "loan-1-migrated", "borrower-1", start.Add(2*time.Hour)))
+
+	// Same borrower, created on a different day
+	repo.Save(newLoanStartingAt(t, "loan-2", "borrower-1", start.AddDate(0, 0, 14)))
+
+	// Different borrower on the same day
+	repo.Save(newLoanStartingAt(t, "loan-3", "borrower-2", start))
+
+	groups := svc.FindPotentialDuplicates()
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d", len(groups))
+	}
+	if len(groups[0]) != 2 || groups[0][0].ID != "loan-1" || groups[0][1].ID != "loan-1-migrated" {
+		t.Errorf("Expected [loan-1 loan-1-migrated], got %v", loanIDs(groups[0]))
+	}
+
+	// Nothing is removed
+	if loans, _ := repo.FindAll(); len(loans) != 4 {
+		t.Errorf("Expected 4 loans to remain, got %d", len(loans))
+	}
+}
+
+func TestFindPotentialDuplicates_DifferentPrincipal(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	repo := NewInMemoryRepository()
+	svc := NewBillingService(repo)
+
+	repo.Save(newLoanStartingAt(t, "loan-1", "borrower-1", start))
+	other, _ := domain.NewLoanWithClock("loan-2", "borrower-1", domain.NewMoney(1000000), domain.DefaultTerms(), fixedClock{now: start})
+	repo.Save(other)
+
+	if groups := svc.FindPotentialDuplicates(); len(groups) != 0 {
+		t.Errorf("Expected no duplicate groups, got %d", len(groups))
+	}
+}
+
+func loanIDs(loans []*domain.Loan) []string {
+	ids := make([]string, len(loans))
+	for i, loan := range loans {
+		ids[i] = loan.ID
+	}
+	return ids
+}
@@ -0,0 +1,26 @@
+package service
+
+import "errors"
+
+var (
+	// ErrLoanNotFound indicates no loan exists with the requested ID
+	ErrLoanNotFound = errors.New("loan not found")
+
+	// ErrInvalidLoanID indicates an empty or whitespace-only loan ID, or one the service's IDValidator rejected
+	ErrInvalidLoanID = errors.New("invalid loan ID")
+
+	// ErrInvalidBorrowerID indicates an empty or whitespace-only borrower ID, or one the service's IDValidator rejected
+	ErrInvalidBorrowerID = errors.New("invalid borrower ID")
+
+	// ErrLoanAlreadyExists indicates a loan with the same ID was already created
+	ErrLoanAlreadyExists = errors.New("loan already exists")
+
+	// ErrDuplicateLoanID indicates the same loan ID appears more than once in a loan book
+	ErrDuplicateLoanID = errors.New("duplicate loan ID")
+
+	// ErrLoanLimitExceeded indicates a borrower holds more loans than allowed
+	ErrLoanLimitExceeded = errors.New("borrower exceeds the loan limit")
+
+	// ErrLoanNotClosed indicates an attempt to delete a loan that still has an outstanding balance
+	ErrLoanNotClosed = errors.New("loan is not closed")
+)
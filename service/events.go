@@ -0,0 +1,140 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// Event is a notification emitted by the BillingService after a state change
+type Event interface {
+	EventName() string
+}
+
+// PaymentMade is emitted after a payment is recorded on a loan
+type PaymentMade struct {
+	LoanID string
+	Week   int
+	Amount domain.Money
+}
+
+func (PaymentMade) EventName() string {
+	return "payment_made"
+}
+
+// LoanClosed is emitted when a payment settles a loan's outstanding balance
+type LoanClosed struct {
+	LoanID string
+}
+
+func (LoanClosed) EventName() string {
+	return "loan_closed"
+}
+
+// BecameDelinquent is emitted when a week change makes a loan delinquent
+// It is not repeated while the loan stays delinquent
+type BecameDelinquent struct {
+	LoanID      string
+	WeeksBehind int
+}
+
+func (BecameDelinquent) EventName() string {
+	return "became_delinquent"
+}
+
+// EventHandler receives events emitted by the BillingService
+type EventHandler interface {
+	HandleEvent(event Event)
+}
+
+// EventHandlerFunc adapts a function to the EventHandler interface
+type EventHandlerFunc func(event Event)
+
+func (f EventHandlerFunc) HandleEvent(event Event) {
+	f(event)
+}
+
+// eventDispatcher delivers events to handlers, either synchronously on the
+// caller's goroutine or through a bounded queue drained by a background worker
+type eventDispatcher struct {
+	handlersMu sync.RWMutex
+	handlers   []EventHandler
+
+	// queueMu guards switching between sync and async delivery. Publishers
+	// hold it for reading while enqueueing, so close waits for them to finish
+	queueMu sync.RWMutex
+	queue   chan Event
+	done    chan struct{}
+}
+
+// subscribe registers a handler for all future events
+func (d *eventDispatcher) subscribe(handler EventHandler) {
+	d.handlersMu.Lock()
+	defer d.handlersMu.Unlock()
+
+	d.handlers = append(d.handlers, handler)
+}
+
+// startAsync switches to asynchronous delivery through a queue of queueSize
+// events. Calling it again while async delivery is running has no effect
+func (d *eventDispatcher) startAsync(queueSize int) {
+	d.queueMu.Lock()
+	defer d.queueMu.Unlock()
+
+	if d.queue != nil {
+		return
+	}
+
+	d.queue = make(chan Event, max(queueSize, 1))
+	d.done = make(chan struct{})
+	go d.run(d.queue, d.done)
+}
+
+// run delivers queued events until the queue is closed and drained
+func (d *eventDispatcher) run(queue <-chan Event, done chan<- struct{}) {
+	defer close(done)
+
+	for event := range queue {
+		d.deliver(event)
+	}
+}
+
+// publish delivers an event to all handlers. In async mode it enqueues the
+// event, blocking while the queue is full so that no event is dropped
+func (d *eventDispatcher) publish(event Event) {
+	d.queueMu.RLock()
+	if d.queue != nil {
+		d.queue <- event
+		d.queueMu.RUnlock()
+		return
+	}
+	d.queueMu.RUnlock()
+
+	d.deliver(event)
+}
+
+func (d *eventDispatcher) deliver(event Event) {
+	d.handlersMu.RLock()
+	handlers := d.handlers
+	d.handlersMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler.HandleEvent(event)
+	}
+}
+
+// close stops async delivery after every queued event has been handled.
+// Later events are delivered synchronously
+func (d *eventDispatcher) close() {
+	d.queueMu.Lock()
+	queue, done := d.queue, d.done
+	d.queue, d.done = nil, nil
+	d.queueMu.Unlock()
+
+	if queue == nil {
+		return
+	}
+
+	close(queue)
+	<-done
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestEvents_Sync(t *testing.T) {
+	svc := newTestService()
+	recorder := &recordingHandler{}
+	svc.Subscribe(recorder)
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakeNextPayment(t.Context(), "loan-1", domain.NewMoney(110000))
+
+	// Delivered before the call returns
+	events := recorder.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	expected := []PaymentMade{
+		{LoanID: "loan-1", Week: 1, Amount: domain.NewMoney(110000)},
+		{LoanID: "loan-1", Week: 2, Amount: domain.NewMoney(110000)},
+	}
+	for i, event := range events {
+		payment, ok := event.(PaymentMade)
+		if !ok || payment.LoanID != expected[i].LoanID || payment.Week != expected[i].Week || !payment.Amount.Equals(expected[i].Amount) {
+			t.Errorf("Expected %+v, got %+v", expected[i], event)
+		}
+	}
+
+	// Failed payments emit nothing
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(1), 3)
+	if len(recorder.Events()) != 2 {
+		t.Errorf("Expected no event for a failed payment, got %d events", len(recorder.Events()))
+	}
+}
+
+func TestEvents_LoanClosed(t *testing.T) {
+	svc := newTestService()
+	recorder := &recordingHandler{}
+	svc.Subscribe(recorder)
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-2", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	for week := 1; week <= 50; week++ {
+		svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), week)
+	}
+
+	events := recorder.Events()
+	if len(events) != 51 {
+		t.Fatalf("Expected 50 payments and 1 closing event, got %d events", len(events))
+	}
+	if closed, ok := events[50].(LoanClosed); !ok || closed.LoanID != "loan-1" {
+		t.Errorf("Expected LoanClosed for loan-1 after the last payment, got %+v", events[50])
+	}
+
+	svc.PayOff("loan-2", domain.NewMoney(5500000))
+	if closed, ok := recorder.Events()[51].(LoanClosed); !ok || closed.LoanID != "loan-2" {
+		t.Errorf("Expected LoanClosed for loan-2 after payoff, got %+v", recorder.Events()[51])
+	}
+}
+
+func TestEvents_BecameDelinquent(t *testing.T) {
+	svc := newTestService()
+	recorder := &recordingHandler{}
+	svc.Subscribe(recorder)
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	// Week 3 with nothing paid flips the loan to delinquent
+	svc.SetCurrentWeek("loan-1", 3)
+	events := recorder.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if event, ok := events[0].(BecameDelinquent); !ok || event.LoanID != "loan-1" || event.WeeksBehind != 3 {
+		t.Errorf("Expected BecameDelinquent 3 weeks behind, got %+v", events[0])
+	}
+
+	// Still delinquent: no new transition
+	svc.SetCurrentWeek("loan-1", 4)
+	if len(recorder.Events()) != 1 {
+		t.Errorf("Expected no event while staying delinquent, got %d events", len(recorder.Events()))
+	}
+
+	// Catching up and falling behind again emits a second transition
+	for range 3 {
+		svc.MakeNextPayment(t.Context(), "loan-1", domain.NewMoney(110000))
+	}
+	svc.SetCurrentWeek("loan-1", 5)
+	svc.SetCurrentWeek("loan-1", 6)
+
+	var transitions int
+	for _, event := range recorder.Events() {
+		if _, ok := event.(BecameDelinquent); ok {
+			transitions++
+		}
+	}
+	if transitions != 2 {
+		t.Errorf("Expected 2 BecameDelinquent events, got %d", transitions)
+	}
+
+	if err := svc.SetCurrentWeek("missing", 3); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound, got %v", err)
+	}
+}
+
+func TestEvents_AsyncDoesNotBlockPayments(t *testing.T) {
+	svc := newTestService()
+	svc.EnableAsyncEvents(10)
+
+	release := make(chan struct{})
+	recorder := &recordingHandler{}
+	svc.Subscribe(EventHandlerFunc(func(event Event) {
+		<-release
+		recorder.HandleEvent(event)
+	}))
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	done := make(chan error)
+	go func() {
+		done <- svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected payment to succeed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected payment to complete while the handler is blocked")
+	}
+
+	if len(recorder.Events()) != 0 {
+		t.Error("Expected event to still be pending")
+	}
+
+	close(release)
+	svc.Close()
+	if len(recorder.Events()) != 1 {
+		t.Errorf("Expected 1 delivered event after close, got %d", len(recorder.Events()))
+	}
+}
+
+func TestEvents_AsyncCloseDrainsQueue(t *testing.T) {
+	svc := newTestService()
+	svc.EnableAsyncEvents(2)
+
+	recorder := &recordingHandler{}
+	svc.Subscribe(EventHandlerFunc(func(event Event) {
+		time.Sleep(time.Millisecond)
+		recorder.HandleEvent(event)
+	}))
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	// More payments than the queue holds
+	for range 10 {
+		if err := svc.MakeNextPayment(t.Context(), "loan-1", domain.NewMoney(110000)); err != nil {
+			t.Fatalf("Failed to make payment: %v", err)
+		}
+	}
+
+	if err := svc.Close(); err != nil {
+		t.Fatalf("Expected close to succeed, got %v", err)
+	}
+
+	events := recorder.Events()
+	if len(events) != 10 {
+		t.Fatalf("Expected all 10 events after close, got %d", len(events))
+	}
+	for i, event := range events {
+		if week := event.(PaymentMade).Week; week != i+1 {
+			t.Errorf("Expected events in order, got week %d at position %d", week, i)
+		}
+	}
+
+	// After close, delivery falls back to synchronous
+	svc.MakeNextPayment(t.Context(), "loan-1", domain.NewMoney(110000))
+	if len(recorder.Events()) != 11 {
+		t.Errorf("Expected synchronous delivery after close, got %d events", len(recorder.Events()))
+	}
+}
+
+// recordingHandler collects events for assertions
+type recordingHandler struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recordingHandler) HandleEvent(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recordingHandler) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Event(nil), r.events...)
+}
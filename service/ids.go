@@ -0,0 +1,62 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// maxIDAttempts bounds how many generated IDs CreateLoanAuto tries before
+// giving up with ErrLoanAlreadyExists
+const maxIDAttempts = 5
+
+// IDGenerator produces loan IDs for CreateLoanAuto
+type IDGenerator interface {
+	NewID() string
+}
+
+// IDGeneratorFunc adapts a function to the IDGenerator interface
+type IDGeneratorFunc func() string
+
+func (f IDGeneratorFunc) NewID() string {
+	return f()
+}
+
+// IDValidator applies extra rules, such as a maximum length or an allowed
+// character set, to the loan and borrower IDs of new loans. IDs that are
+// empty or only whitespace are always rejected before it is called
+type IDValidator interface {
+	ValidateID(id string) error
+}
+
+// IDValidatorFunc adapts a function to the IDValidator interface
+type IDValidatorFunc func(id string) error
+
+func (f IDValidatorFunc) ValidateID(id string) error {
+	return f(id)
+}
+
+// validateID checks id, wrapping any failure in invalid
+func validateID(id string, validator IDValidator, invalid error) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("%w: %q", invalid, id)
+	}
+	if validator == nil {
+		return nil
+	}
+	if err := validator.ValidateID(id); err != nil {
+		return fmt.Errorf("%w %q: %w", invalid, id, err)
+	}
+	return nil
+}
+
+// uuidGenerator produces random (version 4) UUIDs; it is the default IDGenerator
+type uuidGenerator struct{}
+
+func (uuidGenerator) NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// LoanBookRules configures the book-wide checks run by ValidateLoanBookWithRules
+type LoanBookRules struct {
+	// MaxLoansPerBorrower is the most loans a single borrower may hold; 0 means no limit
+	MaxLoansPerBorrower int
+}
+
+// DefaultLoanBookRules returns rules without a per-borrower loan limit
+func DefaultLoanBookRules() LoanBookRules {
+	return LoanBookRules{}
+}
+
+// LoanBookError describes one problem found while validating a loan book
+// LoanID is empty for problems that concern a borrower rather than a loan
+type LoanBookError struct {
+	LoanID     string
+	BorrowerID string
+	Err        error
+}
+
+func (e LoanBookError) Error() string {
+	if e.LoanID == "" {
+		return fmt.Sprintf("borrower %s: %v", e.BorrowerID, e.Err)
+	}
+	return fmt.Sprintf("loan %s: %v", e.LoanID, e.Err)
+}
+
+func (e LoanBookError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateLoanBook checks a set of loans, e.g. migrated data, before it goes
+// live, using DefaultLoanBookRules. See ValidateLoanBookWithRules
+func ValidateLoanBook(loans []*domain.Loan) []LoanBookError {
+	return ValidateLoanBookWithRules(loans, DefaultLoanBookRules())
+}
+
+// ValidateLoanBookWithRules runs each loan's Validate, reports every repeat of
+// a loan ID after its first occurrence and flags borrowers holding more loans
+// than rules allow. An empty result means the book is consistent
+func ValidateLoanBookWithRules(loans []*domain.Loan, rules LoanBookRules) []LoanBookError {
+	problems := make([]LoanBookError, 0)
+
+	seen := make(map[string]bool)
+	loansByBorrower := make(map[string]int)
+	for _, loan := range loans {
+		if err := loan.Validate(); err != nil {
+			problems = append(problems, LoanBookError{LoanID: loan.ID, BorrowerID: loan.BorrowerID, Err: err})
+		}
+
+		if seen[loan.ID] {
+			problems = append(problems, LoanBookError{LoanID: loan.ID, BorrowerID: loan.BorrowerID, Err: ErrDuplicateLoanID})
+			continue
+		}
+		seen[loan.ID] = true
+		loansByBorrower[loan.BorrowerID]++
+	}
+
+	if rules.MaxLoansPerBorrower > 0 {
+		borrowers := make([]string, 0)
+		for borrowerID, count := range loansByBorrower {
+			if count > rules.MaxLoansPerBorrower {
+				borrowers = append(borrowers, borrowerID)
+			}
+		}
+		sort.Strings(borrowers)
+
+		for _, borrowerID := range borrowers {
+			problems = append(problems, LoanBookError{
+				BorrowerID: borrowerID,
+				Err:        fmt.Errorf("%w: %d loans, limit is %d", ErrLoanLimitExceeded, loansByBorrower[borrowerID], rules.MaxLoansPerBorrower),
+			})
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestValidateLoanBook(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	invalid := newLoanStartingAt(t, "loan-3", "borrower-2", start)
+	invalid.TotalAmount = domain.NewMoney(1)
+
+	book := []*domain.Loan{
+		newLoanStartingAt(t, "loan-1", "borrower-1", start),
+		newLoanStartingAt(t, "loan-2", "borrower-1", start),
+		newLoanStartingAt(t, "loan-1", "borrower-1", start),
+		invalid,
+	}
+
+	problems := ValidateLoanBook(book)
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %d: %v", len(problems), problems)
+	}
+
+	if problems[0].LoanID != "loan-1" || !errors.Is(problems[0], ErrDuplicateLoanID) {
+		t.Errorf("Expected duplicate loan-1, got %v", problems[0])
+	}
+	if problems[1].LoanID != "loan-3" || !errors.Is(problems[1], domain.ErrInvalidLoan) {
+		t.Errorf("Expected invalid loan-3, got %v", problems[1])
+	}
+}
+
+func TestValidateLoanBook_LoanLimit(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	book := []*domain.Loan{
+		newLoanStartingAt(t, "loan-1", "borrower-1", start),
+		newLoanStartingAt(t, "loan-2", "borrower-1", start),
+		newLoanStartingAt(t, "loan-3", "borrower-1", start),
+		newLoanStartingAt(t, "loan-4", "borrower-2", start),
+	}
+
+	if problems := ValidateLoanBook(book); len(problems) != 0 {
+		t.Errorf("Expected no problems without a limit, got %v", problems)
+	}
+
+	problems := ValidateLoanBookWithRules(book, LoanBookRules{MaxLoansPerBorrower: 2})
+	if len(problems) != 1 {
+		t.Fatalf("Expected 1 problem, got %d: %v", len(problems), problems)
+	}
+	if problems[0].BorrowerID != "borrower-1" || problems[0].LoanID != "" || !errors.Is(problems[0], ErrLoanLimitExceeded) {
+		t.Errorf("Expected borrower-1 over the limit, got %v", problems[0])
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+// Log levels passed to Logger.Log
+const (
+	LevelInfo  = "info"
+	LevelError = "error"
+)
+
+// Logger receives structured log entries from the BillingService. Adapt slog,
+// zap or any other logger to it with SetLogger
+type Logger interface {
+	Log(level, msg string, fields map[string]any)
+}
+
+// LoggerFunc adapts a function to the Logger interface
+type LoggerFunc func(level, msg string, fields map[string]any)
+
+func (f LoggerFunc) Log(level, msg string, fields map[string]any) {
+	f(level, msg, fields)
+}
+
+// nopLogger discards every entry; it is the default Logger
+type nopLogger struct{}
+
+func (nopLogger) Log(level, msg string, fields map[string]any) {}
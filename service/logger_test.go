@@ -0,0 +1,89 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestLogger_Payment(t *testing.T) {
+	svc := newTestService()
+	logger := &capturingLogger{}
+	svc.SetLogger(logger)
+
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	entries := logger.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].level != LevelInfo || entries[0].msg != "loan created" || entries[0].fields["loan_id"] != "loan-1" {
+		t.Errorf("Unexpected creation entry %+v", entries[0])
+	}
+
+	payment := entries[1]
+	if payment.level != LevelInfo || payment.msg != "payment made" {
+		t.Errorf("Expected info \"payment made\", got %s %q", payment.level, payment.msg)
+	}
+	if payment.fields["loan_id"] != "loan-1" || payment.fields["week"] != 1 || payment.fields["amount"] != "IDR 110000" {
+		t.Errorf("Unexpected payment fields %v", payment.fields)
+	}
+}
+
+func TestLogger_Errors(t *testing.T) {
+	svc := newTestService()
+	logger := &capturingLogger{}
+	svc.SetLogger(logger)
+
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(0), domain.DefaultTerms())
+	svc.MakeNextPayment(t.Context(), "missing", domain.NewMoney(110000))
+
+	entries := logger.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d: %+v", len(entries), entries)
+	}
+	for i, msg := range []string{"loan creation failed", "payment failed"} {
+		if entries[i].level != LevelError || entries[i].msg != msg || entries[i].fields["error"] == nil {
+			t.Errorf("Expected error %q with an error field, got %+v", msg, entries[i])
+		}
+	}
+}
+
+func TestLogger_Default(t *testing.T) {
+	svc := newTestService()
+	svc.SetLogger(nil)
+
+	// The no-op logger is used without panicking
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	if err := svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1); err != nil {
+		t.Errorf("Expected payment to succeed, got %v", err)
+	}
+}
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// capturingLogger records every log entry it receives
+type capturingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *capturingLogger) Log(level, msg string, fields map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l *capturingLogger) Entries() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]logEntry(nil), l.entries...)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// Metrics receives counters and gauges from the BillingService, e.g. to be
+// exported through a Prometheus adapter. Attach it with SetMetrics
+type Metrics interface {
+	IncLoansCreated()
+	IncPaymentsSucceeded()
+	// IncPaymentsFailed counts a rejected payment; reason is a short, stable
+	// label such as "invalid_amount", see failureReason
+	IncPaymentsFailed(reason string)
+	// ObserveOutstanding reports a loan's outstanding balance after it changes
+	ObserveOutstanding(loanID string, amount float64)
+}
+
+// nopMetrics discards everything; it is the default Metrics
+type nopMetrics struct{}
+
+func (nopMetrics) IncLoansCreated()                                 {}
+func (nopMetrics) IncPaymentsSucceeded()                            {}
+func (nopMetrics) IncPaymentsFailed(reason string)                  {}
+func (nopMetrics) ObserveOutstanding(loanID string, amount float64) {}
+
+// failureReasons maps payment errors to metric labels
+var failureReasons = []struct {
+	err    error
+	reason string
+}{
+	{ErrLoanNotFound, "loan_not_found"},
+	{domain.ErrInvalidPaymentAmount, "invalid_amount"},
+	{domain.ErrInvalidPayoffAmount, "invalid_amount"},
+	{domain.ErrNegativeAmount, "invalid_amount"},
+	{domain.ErrCurrencyMismatch, "currency_mismatch"},
+	{domain.ErrInvalidWeekNumber, "invalid_week"},
+	{domain.ErrWeekAlreadyPaid, "week_already_paid"},
+	{domain.ErrPaymentOutOfSequence, "out_of_sequence"},
+	{domain.ErrMustCatchUp, "must_catch_up"},
+	{domain.ErrLoanFullyPaid, "loan_fully_paid"},
+	{domain.ErrLoanArchived, "loan_archived"},
+}
+
+// failureReason returns the metric label for a payment error, "other" for
+// errors without one. Labels stay few so they are safe as metric dimensions
+func failureReason(err error) string {
+	for _, known := range failureReasons {
+		if errors.Is(err, known.err) {
+			return known.reason
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestMetrics(t *testing.T) {
+	svc := newTestService()
+	metrics := newFakeMetrics()
+	svc.SetMetrics(metrics)
+
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms()) // Duplicate
+	if metrics.loansCreated != 1 {
+		t.Errorf("Expected 1 loan created, got %d", metrics.loansCreated)
+	}
+	if metrics.outstanding["loan-1"] != 5500000 {
+		t.Errorf("Expected outstanding 5500000 observed on creation, got %v", metrics.outstanding["loan-1"])
+	}
+
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.MakeNextPayment(t.Context(), "loan-1", domain.NewMoney(110000))
+	if metrics.paymentsSucceeded != 2 {
+		t.Errorf("Expected 2 successful payments, got %d", metrics.paymentsSucceeded)
+	}
+	if metrics.outstanding["loan-1"] != 5280000 {
+		t.Errorf("Expected outstanding 5280000 observed, got %v", metrics.outstanding["loan-1"])
+	}
+
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(1), 3)
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 5)
+	svc.MakeNextPayment(t.Context(), "missing", domain.NewMoney(110000))
+	expected := map[string]int{"invalid_amount": 1, "out_of_sequence": 1, "loan_not_found": 1}
+	for reason, count := range expected {
+		if metrics.paymentsFailed[reason] != count {
+			t.Errorf("Expected %d failures for %q, got %d", count, reason, metrics.paymentsFailed[reason])
+		}
+	}
+	if metrics.paymentsSucceeded != 2 {
+		t.Errorf("Expected failures not to count as successes, got %d", metrics.paymentsSucceeded)
+	}
+
+	if err := svc.PayOff("loan-1", domain.NewMoney(5280000)); err != nil {
+		t.Fatalf("PayOff failed: %v", err)
+	}
+	if metrics.paymentsSucceeded != 3 || metrics.outstanding["loan-1"] != 0 {
+		t.Errorf("Expected payoff counted with zero outstanding, got %d and %v", metrics.paymentsSucceeded, metrics.outstanding["loan-1"])
+	}
+}
+
+// fakeMetrics records every counter and gauge update
+type fakeMetrics struct {
+	mu                sync.Mutex
+	loansCreated      int
+	paymentsSucceeded int
+	paymentsFailed    map[string]int
+	outstanding       map[string]float64
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		paymentsFailed: make(map[string]int),
+		outstanding:    make(map[string]float64),
+	}
+}
+
+func (m *fakeMetrics) IncLoansCreated() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loansCreated++
+}
+
+func (m *fakeMetrics) IncPaymentsSucceeded() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paymentsSucceeded++
+}
+
+func (m *fakeMetrics) IncPaymentsFailed(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paymentsFailed[reason]++
+}
+
+func (m *fakeMetrics) ObserveOutstanding(loanID string, amount float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outstanding[loanID] = amount
+}
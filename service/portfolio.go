@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/shopspring/decimal"
+)
+
+// PortfolioStats aggregates every loan in the repository
+type PortfolioStats struct {
+	LoanCount        int
+	TotalPrincipal   domain.Money // Disbursed across all loans
+	TotalOutstanding domain.Money
+	TotalCollected   domain.Money // Every payment received, prepayments included
+	DelinquentCount  int
+	DelinquentRatio  decimal.Decimal // DelinquentCount / LoanCount, 0 without loans
+}
+
+// PortfolioStats sums up every loan in the repository, closed loans included.
+// The totals come from each loan's running payment total, so this doesn't
+// re-add payment histories. Loans in different currencies can't be added up
+// and return an error wrapping domain.ErrCurrencyMismatch
+func (s *BillingService) PortfolioStats() (PortfolioStats, error) {
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return PortfolioStats{}, err
+	}
+
+	currency := domain.DefaultCurrency
+	if len(loans) > 0 {
+		currency = loans[0].Principal.Currency()
+	}
+	zero := domain.NewMoneyWithCurrency(0, currency)
+	stats := PortfolioStats{
+		LoanCount:        len(loans),
+		TotalPrincipal:   zero,
+		TotalOutstanding: zero,
+		TotalCollected:   zero,
+		DelinquentRatio:  decimal.Zero,
+	}
+
+	for _, loan := range loans {
+		if loan.Principal.Currency() != currency {
+			return PortfolioStats{}, fmt.Errorf("%w: portfolio has loans in %s and %s",
+				domain.ErrCurrencyMismatch, currency, loan.Principal.Currency())
+		}
+
+		stats.TotalPrincipal = stats.TotalPrincipal.Add(loan.Principal)
+		stats.TotalOutstanding = stats.TotalOutstanding.Add(loan.GetOutstanding())
+		stats.TotalCollected = stats.TotalCollected.Add(loan.TotalPaid())
+		if loan.IsDelinquent() {
+			stats.DelinquentCount++
+		}
+	}
+
+	if stats.LoanCount > 0 {
+		stats.DelinquentRatio = decimal.NewFromInt(int64(stats.DelinquentCount)).Div(decimal.NewFromInt(int64(stats.LoanCount)))
+	}
+	return stats, nil
+}
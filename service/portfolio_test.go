@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/shopspring/decimal"
+)
+
+func TestPortfolioStats(t *testing.T) {
+	svc := newTestService()
+
+	empty, err := svc.PortfolioStats()
+	if err != nil || empty.LoanCount != 0 || !empty.TotalOutstanding.IsZero() || !empty.DelinquentRatio.IsZero() {
+		t.Errorf("Expected empty stats, got %+v (%v)", empty, err)
+	}
+
+	// loan-1 current, loan-2 delinquent, loan-3 paid off, loan-4 untouched
+	current, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	behind, _ := svc.CreateLoan(t.Context(), "loan-2", "borrower-2", domain.NewMoney(1000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-3", "borrower-3", domain.NewMoney(2000000), domain.DefaultTerms())
+	svc.CreateLoan(t.Context(), "loan-4", "borrower-4", domain.NewMoney(2000000), domain.DefaultTerms())
+
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	current.SetCurrentWeek(2)
+	behind.SetCurrentWeek(3)
+	svc.PayOff("loan-3", domain.NewMoney(2200000))
+
+	stats, err := svc.PortfolioStats()
+	if err != nil {
+		t.Fatalf("PortfolioStats failed: %v", err)
+	}
+	if stats.LoanCount != 4 || stats.DelinquentCount != 1 || !stats.DelinquentRatio.Equal(decimal.NewFromFloat(0.25)) {
+		t.Errorf("Expected 4 loans with 1 delinquent (0.25), got %+v", stats)
+	}
+	if !stats.TotalPrincipal.Equals(domain.NewMoney(10000000)) {
+		t.Errorf("Expected principal 10000000, got %s", stats.TotalPrincipal)
+	}
+	if !stats.TotalOutstanding.Equals(domain.NewMoney(5390000 + 1100000 + 2200000)) {
+		t.Errorf("Expected outstanding 8690000, got %s", stats.TotalOutstanding)
+	}
+	if !stats.TotalCollected.Equals(domain.NewMoney(110000 + 2200000)) {
+		t.Errorf("Expected collected 2310000, got %s", stats.TotalCollected)
+	}
+
+	svc.CreateLoan(t.Context(), "loan-5", "borrower-5", domain.NewMoneyWithCurrency(5000, domain.USD), domain.DefaultTerms())
+	if _, err := svc.PortfolioStats(); !errors.Is(err, domain.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// Reminder describes an upcoming installment a borrower should be notified about
+type Reminder struct {
+	LoanID     string
+	BorrowerID string
+	WeekNumber int
+	Amount     domain.Money // Still due for the week, net of any partial payment
+	DueDate    time.Time
+}
+
+// UpcomingReminders returns, for every active loan, the next unpaid installment
+// when it falls due within [now, now+within]. Results are sorted by due date
+// then loan ID. Overdue installments are not included; see IsDelinquent
+func (s *BillingService) UpcomingReminders(now time.Time, within time.Duration) []Reminder {
+	reminders := make([]Reminder, 0)
+
+	loans, err := s.repo.FindAll()
+	if err != nil {
+		return reminders
+	}
+
+	deadline := now.Add(within)
+	for _, loan := range loans {
+		view := loan.View()
+		if view.IsClosed() {
+			continue
+		}
+
+		week := view.NextDueWeek()
+		dueDate, err := view.DueDateForWeek(week)
+		if err != nil || dueDate.Before(now) || dueDate.After(deadline) {
+			continue
+		}
+
+		reminders = append(reminders, Reminder{
+			LoanID:     loan.ID,
+			BorrowerID: loan.BorrowerID,
+			WeekNumber: week,
+			Amount:     view.Schedule()[week-view.StartWeek()].Remaining(),
+			DueDate:    dueDate,
+		})
+	}
+
+	sort.SliceStable(reminders, func(i, j int) bool {
+		return reminders[i].DueDate.Before(reminders[j].DueDate)
+	})
+
+	return reminders
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestUpcomingReminders(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	repo := NewInMemoryRepository()
+	svc := NewBillingService(repo)
+
+	// Week 1 paid, week 2 due in 2 days
+	paidAhead := newLoanStartingAt(t, "loan-a", "borrower-a", now.Add(-5*day))
+	paidAhead.MakePayment(domain.NewMoney(110000), 1)
+	repo.Save(paidAhead)
+
+	// Week 1 due in 3 days
+	repo.Save(newLoanStartingAt(t, "loan-b", "borrower-b", now.Add(3*day)))
+
+	// Week 1 due in 10 days, outside the window
+	repo.Save(newLoanStartingAt(t, "loan-c", "borrower-c", now.Add(10*day)))
+
+	// Week 1 was due yesterday, overdue rather than upcoming
+	repo.Save(newLoanStartingAt(t, "loan-d", "borrower-d", now.Add(-day)))
+
+	// Fully paid loans get no reminders
+	closed := newLoanStartingAt(t, "loan-e", "borrower-e", now.Add(day))
+	for week := 1; week <= closed.DurationWeeks; week++ {
+		closed.MakePayment(domain.NewMoney(110000), week)
+	}
+	repo.Save(closed)
+
+	reminders := svc.UpcomingReminders(now, 7*day)
+	if len(reminders) != 2 {
+		t.Fatalf("Expected 2 reminders, got %d: %+v", len(reminders), reminders)
+	}
+
+	first := reminders[0]
+	if first.LoanID != "loan-a" || first.BorrowerID != "borrower-a" || first.WeekNumber != 2 {
+		t.Errorf("Expected loan-a week 2 first, got %s week %d", first.LoanID, first.WeekNumber)
+	}
+	if !first.DueDate.Equal(now.Add(2 * day)) {
+		t.Errorf("Expected due date %v, got %v", now.Add(2*day), first.DueDate)
+	}
+	if !first.Amount.Equals(domain.NewMoney(110000)) {
+		t.Errorf("Expected amount IDR 110000, got %s", first.Amount)
+	}
+
+	if reminders[1].LoanID != "loan-b" || reminders[1].WeekNumber != 1 {
+		t.Errorf("Expected loan-b week 1 second, got %s week %d", reminders[1].LoanID, reminders[1].WeekNumber)
+	}
+}
+
+func TestUpcomingReminders_PartlyPaid(t *testing.T) {
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	repo := NewInMemoryRepository()
+	svc := NewBillingService(repo)
+
+	// Week 1 due tomorrow with IDR 40000 already paid
+	loan := newLoanStartingAt(t, "loan-a", "borrower-a", now.Add(24*time.Hour))
+	loan.MakePartialPayment(domain.NewMoney(40000), 1)
+	repo.Save(loan)
+
+	reminders := svc.UpcomingReminders(now, 7*24*time.Hour)
+	if len(reminders) != 1 || reminders[0].WeekNumber != 1 {
+		t.Fatalf("Expected a reminder for week 1, got %+v", reminders)
+	}
+	if !reminders[0].Amount.Equals(domain.NewMoney(70000)) {
+		t.Errorf("Expected the remaining IDR 70000, got %s", reminders[0].Amount)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// LoanRepository persists loans for the BillingService
+// Implementations must be safe for concurrent use
+type LoanRepository interface {
+	// Save inserts or updates a loan
+	Save(loan *domain.Loan) error
+
+	// FindByID returns the loan with the given ID or an error wrapping
+	// ErrLoanNotFound if it doesn't exist
+	FindByID(id string) (*domain.Loan, error)
+
+	// FindByBorrower returns all loans belonging to a borrower, sorted by loan ID
+	FindByBorrower(borrowerID string) ([]*domain.Loan, error)
+
+	// FindAll returns every loan, sorted by loan ID
+	FindAll() ([]*domain.Loan, error)
+
+	// Delete removes the loan with the given ID or returns an error wrapping
+	// ErrLoanNotFound if it doesn't exist
+	Delete(id string) error
+}
+
+// InMemoryRepository is a LoanRepository backed by a map, with a secondary
+// index of loan IDs by borrower. State is lost when the process exits
+type InMemoryRepository struct {
+	loans      map[string]*domain.Loan
+	byBorrower map[string]map[string]struct{} // borrower ID -> set of loan IDs
+	mu         sync.RWMutex
+}
+
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		loans:      make(map[string]*domain.Loan),
+		byBorrower: make(map[string]map[string]struct{}),
+	}
+}
+
+// Save stores the loan keyed by its ID
+func (r *InMemoryRepository) Save(loan *domain.Loan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.loans[loan.ID]; ok {
+		r.unindex(existing)
+	}
+	r.loans[loan.ID] = loan
+
+	ids, ok := r.byBorrower[loan.BorrowerID]
+	if !ok {
+		ids = make(map[string]struct{})
+		r.byBorrower[loan.BorrowerID] = ids
+	}
+	ids[loan.ID] = struct{}{}
+	return nil
+}
+
+// unindex removes loan from the borrower index; the caller must hold r.mu
+func (r *InMemoryRepository) unindex(loan *domain.Loan) {
+	ids := r.byBorrower[loan.BorrowerID]
+	delete(ids, loan.ID)
+	if len(ids) == 0 {
+		delete(r.byBorrower, loan.BorrowerID)
+	}
+}
+
+// FindByID retrieves a loan by ID
+func (r *InMemoryRepository) FindByID(id string) (*domain.Loan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	loan, exists := r.loans[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrLoanNotFound, id)
+	}
+
+	return loan, nil
+}
+
+// Delete removes a loan by ID
+func (r *InMemoryRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	loan, exists := r.loans[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrLoanNotFound, id)
+	}
+
+	r.unindex(loan)
+	delete(r.loans, id)
+	return nil
+}
+
+// FindByBorrower retrieves all loans for a borrower, sorted by loan ID
+func (r *InMemoryRepository) FindByBorrower(borrowerID string) ([]*domain.Loan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := r.byBorrower[borrowerID]
+	loans := make([]*domain.Loan, 0, len(ids))
+	for id := range ids {
+		loans = append(loans, r.loans[id])
+	}
+
+	sort.Slice(loans, func(i, j int) bool {
+		return loans[i].ID < loans[j].ID
+	})
+
+	return loans, nil
+}
+
+// FindAll retrieves every loan, sorted by loan ID
+func (r *InMemoryRepository) FindAll() ([]*domain.Loan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	loans := make([]*domain.Loan, 0, len(r.loans))
+	for _, loan := range r.loans {
+		loans = append(loans, loan)
+	}
+
+	sort.Slice(loans, func(i, j int) bool {
+		return loans[i].ID < loans[j].ID
+	})
+
+	return loans, nil
+}
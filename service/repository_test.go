@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestInMemoryRepository_Save(t *testing.T) {
+	repo := NewInMemoryRepository()
+	loan := newRepositoryTestLoan(t, "loan-1", "borrower-1")
+
+	if err := repo.Save(loan); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	found, err := repo.FindByID("loan-1")
+	if err != nil || found != loan {
+		t.Fatalf("Expected the saved loan back, got %v, %v", found, err)
+	}
+
+	// Saving again updates the loan in place
+	if err := repo.Save(loan); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if loans, _ := repo.FindByBorrower("borrower-1"); len(loans) != 1 {
+		t.Errorf("Expected 1 loan for borrower-1 after saving twice, got %d", len(loans))
+	}
+
+	// A loan saved under another borrower moves to that borrower's index
+	moved := newRepositoryTestLoan(t, "loan-1", "borrower-2")
+	if err := repo.Save(moved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if loans, _ := repo.FindByBorrower("borrower-1"); len(loans) != 0 {
+		t.Errorf("Expected no loans left for borrower-1, got %d", len(loans))
+	}
+	if loans, _ := repo.FindByBorrower("borrower-2"); len(loans) != 1 || loans[0] != moved {
+		t.Errorf("Expected loan-1 under borrower-2, got %v", loans)
+	}
+	if found, _ := repo.FindByID("loan-1"); found != moved {
+		t.Errorf("Expected FindByID to return the latest save")
+	}
+}
+
+func TestInMemoryRepository_FindByBorrower(t *testing.T) {
+	repo := NewInMemoryRepository()
+	for _, id := range []string{"loan-3", "loan-1", "loan-2"} {
+		repo.Save(newRepositoryTestLoan(t, id, "borrower-1"))
+	}
+	repo.Save(newRepositoryTestLoan(t, "loan-4", "borrower-2"))
+
+	loans, err := repo.FindByBorrower("borrower-1")
+	if err != nil {
+		t.Fatalf("FindByBorrower failed: %v", err)
+	}
+	if ids := loanIDs(loans); len(ids) != 3 || ids[0] != "loan-1" || ids[1] != "loan-2" || ids[2] != "loan-3" {
+		t.Errorf("Expected loan-1, loan-2 and loan-3 in ID order, got %v", ids)
+	}
+
+	if loans, err := repo.FindByBorrower("unknown"); err != nil || len(loans) != 0 {
+		t.Errorf("Expected no loans for an unknown borrower, got %v, %v", loans, err)
+	}
+}
+
+func TestInMemoryRepository_FindAll(t *testing.T) {
+	repo := NewInMemoryRepository()
+	if loans, err := repo.FindAll(); err != nil || len(loans) != 0 {
+		t.Errorf("Expected an empty repository, got %v, %v", loans, err)
+	}
+
+	repo.Save(newRepositoryTestLoan(t, "loan-2", "borrower-1"))
+	repo.Save(newRepositoryTestLoan(t, "loan-3", "borrower-2"))
+	repo.Save(newRepositoryTestLoan(t, "loan-1", "borrower-2"))
+
+	loans, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if ids := loanIDs(loans); len(ids) != 3 || ids[0] != "loan-1" || ids[1] != "loan-2" || ids[2] != "loan-3" {
+		t.Errorf("Expected every loan in ID order, got %v", ids)
+	}
+}
+
+func TestInMemoryRepository_Delete(t *testing.T) {
+	repo := NewInMemoryRepository()
+	repo.Save(newRepositoryTestLoan(t, "loan-1", "borrower-1"))
+
+	if err := repo.Delete("loan-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.FindByID("loan-1"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound after delete, got %v", err)
+	}
+	if loans, _ := repo.FindByBorrower("borrower-1"); len(loans) != 0 {
+		t.Errorf("Expected the borrower index to be cleared, got %d loans", len(loans))
+	}
+
+	if err := repo.Delete("loan-1"); !errors.Is(err, ErrLoanNotFound) {
+		t.Errorf("Expected ErrLoanNotFound deleting a missing loan, got %v", err)
+	}
+}
+
+func newRepositoryTestLoan(t *testing.T, id, borrowerID string) *domain.Loan {
+	t.Helper()
+
+	loan, err := domain.NewLoan(id, borrowerID, domain.NewMoney(5000000), domain.DefaultTerms())
+	if err != nil {
+		t.Fatalf("Failed to create loan: %v", err)
+	}
+	return loan
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// SimulatedPayment is a payment of Amount for Week made while the loan is at
+// week AtWeek
+type SimulatedPayment struct {
+	Week   int
+	Amount domain.Money
+	AtWeek int
+}
+
+// SimulatedWeek is the loan's state at the end of a simulated week, after
+// that week's payments
+type SimulatedWeek struct {
+	Week        int
+	Outstanding domain.Money
+	WeeksBehind int
+	Delinquent  bool
+}
+
+// SimulationError is a payment the loan rejected during a simulation
+type SimulationError struct {
+	Payment SimulatedPayment
+	Err     error
+}
+
+// SimulationResult is the outcome of SimulateLoan. Loan is the simulated loan
+// in its final state, for inspecting the schedule and payment history
+type SimulationResult struct {
+	Loan        *domain.Loan
+	Outstanding domain.Money
+	Timeline    []SimulatedWeek
+	Errors      []SimulationError
+}
+
+// SimulateLoan creates a standalone loan for principal under terms and walks it
+// week by week through the whole term, applying each payment at its AtWeek in
+// the order given. Rejected payments are collected in Errors rather than
+// stopping the run; payments with an AtWeek outside the term are rejected with
+// ErrInvalidWeekNumber. Nothing is saved to a repository, so simulations don't
+// affect any BillingService. The error is only set when the loan can't be
+// created
+func SimulateLoan(principal domain.Money, terms domain.LoanTerms, payments []SimulatedPayment) (SimulationResult, error) {
+	loan, err := domain.NewLoan("simulation", "simulation", principal, terms)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	firstWeek := loan.GetSchedule()[0].WeekNumber
+	ordered := make([]SimulatedPayment, len(payments))
+	copy(ordered, payments)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].AtWeek < ordered[j].AtWeek })
+
+	result := SimulationResult{Loan: loan}
+	next := 0
+	for ; next < len(ordered) && ordered[next].AtWeek < firstWeek; next++ {
+		result.Errors = append(result.Errors, SimulationError{Payment: ordered[next], Err: domain.ErrInvalidWeekNumber})
+	}
+
+	for week := firstWeek; week <= loan.DurationWeeks; week++ {
+		loan.SetCurrentWeek(week)
+		for ; next < len(ordered) && ordered[next].AtWeek == week; next++ {
+			payment := ordered[next]
+			if err := loan.MakePayment(payment.Amount, payment.Week); err != nil {
+				result.Errors = append(result.Errors, SimulationError{Payment: payment, Err: err})
+			}
+		}
+
+		result.Timeline = append(result.Timeline, SimulatedWeek{
+			Week:        week,
+			Outstanding: loan.GetOutstanding(),
+			WeeksBehind: loan.WeeksBehind(),
+			Delinquent:  loan.IsDelinquent(),
+		})
+	}
+
+	for ; next < len(ordered); next++ {
+		result.Errors = append(result.Errors, SimulationError{Payment: ordered[next], Err: domain.ErrInvalidWeekNumber})
+	}
+
+	result.Outstanding = loan.GetOutstanding()
+	return result, nil
+}
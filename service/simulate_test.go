@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+func TestSimulateLoan_OnTime(t *testing.T) {
+	var payments []SimulatedPayment
+	for week := 1; week <= 50; week++ {
+		payments = append(payments, SimulatedPayment{Week: week, Amount: domain.NewMoney(110000), AtWeek: week})
+	}
+
+	result, err := SimulateLoan(domain.NewMoney(5000000), domain.DefaultTerms(), payments)
+	if err != nil {
+		t.Fatalf("SimulateLoan failed: %v", err)
+	}
+
+	if !result.Outstanding.IsZero() || !result.Loan.IsClosed() {
+		t.Errorf("Expected a closed loan, got outstanding %s", result.Outstanding)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no errors, got %v", result.Errors)
+	}
+	if len(result.Timeline) != 50 {
+		t.Fatalf("Expected 50 timeline weeks, got %d", len(result.Timeline))
+	}
+	for _, week := range result.Timeline {
+		if week.Delinquent {
+			t.Errorf("Week %d: expected not delinquent", week.Week)
+		}
+	}
+	if got := result.Timeline[9].Outstanding; !got.Equals(domain.NewMoney(5500000 - 10*110000)) {
+		t.Errorf("Week 10: expected outstanding 4400000, got %s", got)
+	}
+}
+
+func TestSimulateLoan_DelinquentThenRecovers(t *testing.T) {
+	installment := domain.NewMoney(110000)
+	payments := []SimulatedPayment{
+		// Catch-up listed first; payments are applied in AtWeek order
+		{Week: 2, Amount: installment, AtWeek: 4},
+		{Week: 3, Amount: installment, AtWeek: 4},
+		{Week: 4, Amount: installment, AtWeek: 4},
+		{Week: 1, Amount: installment, AtWeek: 1},
+		{Week: 5, Amount: domain.NewMoney(100000), AtWeek: 5},
+		{Week: 6, Amount: installment, AtWeek: 60},
+	}
+
+	result, err := SimulateLoan(domain.NewMoney(5000000), domain.DefaultTerms(), payments)
+	if err != nil {
+		t.Fatalf("SimulateLoan failed: %v", err)
+	}
+
+	wantDelinquent := []bool{false, false, true, false, false, true}
+	for i, want := range wantDelinquent {
+		if got := result.Timeline[i]; got.Delinquent != want {
+			t.Errorf("Week %d: expected delinquent=%v, got %+v", i+1, want, got)
+		}
+	}
+
+	if len(result.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", result.Errors)
+	}
+	if !errors.Is(result.Errors[0].Err, domain.ErrInvalidPaymentAmount) || result.Errors[0].Payment.Week != 5 {
+		t.Errorf("Expected ErrInvalidPaymentAmount for week 5, got %+v", result.Errors[0])
+	}
+	if !errors.Is(result.Errors[1].Err, domain.ErrInvalidWeekNumber) {
+		t.Errorf("Expected ErrInvalidWeekNumber for AtWeek 60, got %+v", result.Errors[1])
+	}
+	if !result.Outstanding.Equals(domain.NewMoney(5500000 - 4*110000)) {
+		t.Errorf("Expected outstanding 5060000, got %s", result.Outstanding)
+	}
+}
+
+func TestSimulateLoan_InvalidTerms(t *testing.T) {
+	terms := domain.DefaultTerms()
+	terms.DurationWeeks = 0
+	if _, err := SimulateLoan(domain.NewMoney(5000000), terms, nil); !errors.Is(err, domain.ErrInvalidDuration) {
+		t.Errorf("Expected ErrInvalidDuration, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults used by NewWebhookNotifier
+const (
+	DefaultWebhookTimeout     = 5 * time.Second
+	DefaultWebhookMaxAttempts = 3
+	DefaultWebhookBackoff     = 500 * time.Millisecond
+)
+
+// WebhookPayload is the JSON body POSTed by WebhookNotifier
+type WebhookPayload struct {
+	Event       string `json:"event"` // EventName() of the event
+	LoanID      string `json:"loan_id"`
+	WeeksBehind int    `json:"weeks_behind,omitempty"` // Set for became_delinquent
+}
+
+// WebhookNotifier is an EventHandler that POSTs BecameDelinquent and
+// LoanClosed events to URL as a WebhookPayload. Other events are ignored.
+//
+// Each event is delivered on its own goroutine so HandleEvent never blocks
+// the service; deliveries may therefore arrive out of order. Network errors
+// and 5xx responses are retried up to MaxAttempts times, waiting Backoff
+// before the first retry and doubling it each time; other responses are
+// final. Deliveries that still fail are logged to Logger at LevelError
+type WebhookNotifier struct {
+	URL         string
+	Client      *http.Client // Its Timeout bounds each attempt
+	MaxAttempts int
+	Backoff     time.Duration
+	Logger      Logger
+
+	wg sync.WaitGroup
+}
+
+// NewWebhookNotifier returns a notifier for url using the default timeout,
+// attempts and backoff, logging failures to logger (nil discards them)
+func NewWebhookNotifier(url string, logger Logger) *WebhookNotifier {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	return &WebhookNotifier{
+		URL:         url,
+		Client:      &http.Client{Timeout: DefaultWebhookTimeout},
+		MaxAttempts: DefaultWebhookMaxAttempts,
+		Backoff:     DefaultWebhookBackoff,
+		Logger:      logger,
+	}
+}
+
+// HandleEvent starts delivering event in the background
+func (n *WebhookNotifier) HandleEvent(event Event) {
+	var payload WebhookPayload
+	switch e := event.(type) {
+	case BecameDelinquent:
+		payload = WebhookPayload{Event: e.EventName(), LoanID: e.LoanID, WeeksBehind: e.WeeksBehind}
+	case LoanClosed:
+		payload = WebhookPayload{Event: e.EventName(), LoanID: e.LoanID}
+	default:
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		if err := n.deliver(payload); err != nil {
+			n.Logger.Log(LevelError, "webhook delivery failed", map[string]any{
+				"event": payload.Event, "loan_id": payload.LoanID, "url": n.URL, "error": err.Error(),
+			})
+		}
+	}()
+}
+
+// Wait blocks until every delivery started so far has succeeded or given up
+func (n *WebhookNotifier) Wait() {
+	n.wg.Wait()
+}
+
+// deliver POSTs payload, retrying network errors and 5xx responses
+func (n *WebhookNotifier) deliver(payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.Backoff
+	for attempt := 1; ; attempt++ {
+		err = n.post(body)
+		if err == nil || attempt >= n.MaxAttempts {
+			return err
+		}
+		if _, retryable := err.(retryableError); !retryable {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// retryableError marks delivery failures worth another attempt
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (n *WebhookNotifier) post(body []byte) error {
+	resp, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return retryableError{err}
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return retryableError{fmt.Errorf("webhook returned %s", resp.Status)}
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
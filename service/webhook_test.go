@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+// webhookServer records the payloads it receives, failing the first
+// failures requests with a 500
+type webhookServer struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	payloads []WebhookPayload
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts++
+	if s.attempts <= s.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var payload WebhookPayload
+	json.NewDecoder(r.Body).Decode(&payload)
+	s.payloads = append(s.payloads, payload)
+}
+
+func newTestNotifier(url string, logger Logger) *WebhookNotifier {
+	notifier := NewWebhookNotifier(url, logger)
+	notifier.Backoff = time.Millisecond
+	return notifier
+}
+
+func TestWebhookNotifier_DeliversEvents(t *testing.T) {
+	receiver := &webhookServer{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	notifier := newTestNotifier(server.URL, nil)
+	svc := newTestService()
+	svc.Subscribe(notifier)
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+	svc.SetCurrentWeek("loan-1", 3)
+	notifier.Wait()
+	svc.PayOff("loan-1", domain.NewMoney(5390000))
+	notifier.Wait()
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	// The payment itself isn't sent
+	if len(receiver.payloads) != 2 {
+		t.Fatalf("Expected 2 payloads, got %+v", receiver.payloads)
+	}
+	want := []WebhookPayload{
+		{Event: "became_delinquent", LoanID: "loan-1", WeeksBehind: 2},
+		{Event: "loan_closed", LoanID: "loan-1"},
+	}
+	for i, payload := range receiver.payloads {
+		if payload != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], payload)
+		}
+	}
+}
+
+func TestWebhookNotifier_RetriesServerErrors(t *testing.T) {
+	receiver := &webhookServer{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	logger := &capturingLogger{}
+	notifier := newTestNotifier(server.URL, logger)
+	notifier.HandleEvent(LoanClosed{LoanID: "loan-1"})
+	notifier.Wait()
+
+	receiver.mu.Lock()
+	if receiver.attempts != 3 || len(receiver.payloads) != 1 {
+		t.Errorf("Expected delivery on the 3rd attempt, got %d attempts and %d payloads", receiver.attempts, len(receiver.payloads))
+	}
+	receiver.mu.Unlock()
+	if len(logger.Entries()) != 0 {
+		t.Errorf("Expected no errors logged, got %+v", logger.Entries())
+	}
+
+	// Giving up after MaxAttempts is logged
+	receiver.mu.Lock()
+	receiver.attempts, receiver.failures = 0, 5
+	receiver.mu.Unlock()
+	notifier.HandleEvent(LoanClosed{LoanID: "loan-2"})
+	notifier.Wait()
+
+	entries := logger.Entries()
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if receiver.attempts != 3 || len(entries) != 1 || entries[0].level != LevelError || entries[0].fields["loan_id"] != "loan-2" {
+		t.Errorf("Expected one error after 3 attempts, got %d attempts and %+v", receiver.attempts, entries)
+	}
+}
@@ -0,0 +1,254 @@
+// Package grpcserver implements the billing gRPC API defined in proto/billing.proto
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/rendikr/billing-engine/proto/billingpb"
+	"github.com/rendikr/billing-engine/service"
+)
+
+// Server implements billingpb.BillingServiceServer on top of a BillingService
+// Register it with billingpb.RegisterBillingServiceServer
+type Server struct {
+	billingpb.UnimplementedBillingServiceServer
+
+	svc *service.BillingService
+}
+
+// NewServer creates a gRPC server backed by svc
+func NewServer(svc *service.BillingService) *Server {
+	return &Server{svc: svc}
+}
+
+// CreateLoan creates a loan, applying DefaultTerms for omitted fields
+func (s *Server) CreateLoan(ctx context.Context, req *billingpb.CreateLoanRequest) (*billingpb.Loan, error) {
+	if req.GetLoanId() == "" || req.GetBorrowerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "loan_id and borrower_id are required")
+	}
+
+	principal, err := parseMoney(req.GetPrincipal(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	terms := domain.DefaultTerms()
+	if req.GetDurationWeeks() != 0 {
+		terms.DurationWeeks = int(req.GetDurationWeeks())
+	}
+	if req.GetAnnualInterestRate() != "" {
+		rate, err := decimal.NewFromString(req.GetAnnualInterestRate())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid annual_interest_rate %q", req.GetAnnualInterestRate())
+		}
+		terms.AnnualInterestRate = rate
+	}
+	if req.GetInterestModel() != "" {
+		model, ok := domain.ParseInterestModel(req.GetInterestModel())
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown interest model %q", req.GetInterestModel())
+		}
+		terms.InterestModel = model
+	}
+
+	loan, err := s.svc.CreateLoan(ctx, req.GetLoanId(), req.GetBorrowerId(), principal, terms)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &billingpb.Loan{
+		LoanId:        loan.ID,
+		BorrowerId:    loan.BorrowerID,
+		Principal:     moneyString(loan.Principal),
+		TotalAmount:   moneyString(loan.TotalAmount),
+		WeeklyPayment: moneyString(loan.WeeklyPayment),
+		DurationWeeks: int32(loan.DurationWeeks),
+		InterestModel: loan.InterestModel.String(),
+		Currency:      string(loan.Principal.Currency()),
+	}, nil
+}
+
+// GetOutstanding returns the outstanding balance of a loan
+func (s *Server) GetOutstanding(ctx context.Context, req *billingpb.GetOutstandingRequest) (*billingpb.GetOutstandingResponse, error) {
+	outstanding, err := s.svc.GetOutstanding(ctx, req.GetLoanId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &billingpb.GetOutstandingResponse{
+		LoanId:      req.GetLoanId(),
+		Outstanding: moneyString(outstanding),
+		Currency:    string(outstanding.Currency()),
+	}, nil
+}
+
+// IsDelinquent reports whether a loan is delinquent
+func (s *Server) IsDelinquent(ctx context.Context, req *billingpb.IsDelinquentRequest) (*billingpb.IsDelinquentResponse, error) {
+	delinquent, err := s.svc.IsDelinquent(ctx, req.GetLoanId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &billingpb.IsDelinquentResponse{
+		LoanId:       req.GetLoanId(),
+		IsDelinquent: delinquent,
+	}, nil
+}
+
+// MakePayment pays the requested week, or the next due week when it is 0
+func (s *Server) MakePayment(ctx context.Context, req *billingpb.MakePaymentRequest) (*billingpb.MakePaymentResponse, error) {
+	amount, err := parseMoney(req.GetAmount(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetWeekNumber() == 0 {
+		err = s.svc.MakeNextPayment(ctx, req.GetLoanId(), amount)
+	} else {
+		err = s.svc.MakePayment(ctx, req.GetLoanId(), amount, int(req.GetWeekNumber()))
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	outstanding, err := s.svc.GetOutstanding(ctx, req.GetLoanId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &billingpb.MakePaymentResponse{
+		LoanId:      req.GetLoanId(),
+		Outstanding: moneyString(outstanding),
+		Currency:    string(outstanding.Currency()),
+	}, nil
+}
+
+// GetSchedule returns the payment schedule of a loan
+func (s *Server) GetSchedule(ctx context.Context, req *billingpb.GetScheduleRequest) (*billingpb.GetScheduleResponse, error) {
+	loan, err := s.svc.GetLoan(ctx, req.GetLoanId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	schedule := loan.GetSchedule()
+
+	entries := make([]*billingpb.ScheduleEntry, 0, len(schedule))
+	for _, entry := range schedule {
+		pbEntry := &billingpb.ScheduleEntry{
+			WeekNumber: int32(entry.WeekNumber),
+			Amount:     moneyString(entry.Amount),
+			PaidAmount: moneyString(entry.PaidAmount),
+			IsPaid:     entry.IsPaid,
+		}
+		if entry.PaidAt != nil {
+			pbEntry.PaidAt = timestamppb.New(*entry.PaidAt)
+		}
+		entries = append(entries, pbEntry)
+	}
+
+	return &billingpb.GetScheduleResponse{Entries: entries, Currency: string(loan.Principal.Currency())}, nil
+}
+
+// GetPaymentHistory returns every payment recorded on a loan
+func (s *Server) GetPaymentHistory(ctx context.Context, req *billingpb.GetPaymentHistoryRequest) (*billingpb.GetPaymentHistoryResponse, error) {
+	loan, err := s.svc.GetLoan(ctx, req.GetLoanId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	history := loan.GetPaymentHistory()
+
+	payments := make([]*billingpb.Payment, 0, len(history))
+	for _, payment := range history {
+		payments = append(payments, &billingpb.Payment{
+			WeekNumber: int32(payment.WeekNumber),
+			Amount:     moneyString(payment.Amount),
+			PaidAt:     timestamppb.New(payment.PaidAt),
+		})
+	}
+
+	return &billingpb.GetPaymentHistoryResponse{Payments: payments, Currency: string(loan.Principal.Currency())}, nil
+}
+
+// moneyString formats an amount as a plain decimal string in its currency's
+// minor units; the currency travels in the message's currency field
+func moneyString(m domain.Money) string {
+	return m.Amount().StringFixed(m.Currency().DecimalPlaces())
+}
+
+// parseMoney parses a request amount in currency, DefaultCurrency when empty,
+// returning an InvalidArgument status for malformed input
+func parseMoney(amount, currency string) (domain.Money, error) {
+	parsed, err := domain.NewMoneyFromString(amount)
+	if err != nil {
+		return domain.Money{}, toStatus(err)
+	}
+
+	switch c := domain.Currency(currency); c {
+	case "":
+		return parsed, nil
+	case domain.IDR, domain.USD:
+		return domain.NewMoneyFromDecimalWithCurrency(parsed.Amount(), c), nil
+	default:
+		return domain.Money{}, status.Errorf(codes.InvalidArgument, "unsupported currency %q", currency)
+	}
+}
+
+// invalidArgumentErrors are errors caused by a malformed request
+var invalidArgumentErrors = []error{
+	domain.ErrInvalidPaymentAmount,
+	domain.ErrNegativeAmount,
+	domain.ErrInvalidWeekNumber,
+	domain.ErrInvalidPrincipal,
+	domain.ErrInvalidDuration,
+	domain.ErrInvalidInterestRate,
+	domain.ErrInterestRateOutOfRange,
+	domain.ErrInvalidRateBounds,
+	domain.ErrInvalidDelinquencyThreshold,
+	domain.ErrInvalidStartWeek,
+	domain.ErrInvalidGracePeriod,
+	domain.ErrInvalidMoneyFormat,
+	domain.ErrCurrencyMismatch,
+}
+
+// failedPreconditionErrors are errors caused by the loan's current state
+var failedPreconditionErrors = []error{
+	domain.ErrLoanFullyPaid,
+	domain.ErrWeekAlreadyPaid,
+	domain.ErrPaymentOutOfSequence,
+	domain.ErrMustCatchUp,
+	domain.ErrLoanArchived,
+}
+
+// toStatus maps a service or domain error to a gRPC status error
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, service.ErrLoanNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrLoanAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrInvalidLoanID), errors.Is(err, service.ErrInvalidBorrowerID):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+
+	for _, target := range invalidArgumentErrors {
+		if errors.Is(err, target) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	for _, target := range failedPreconditionErrors {
+		if errors.Is(err, target) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+
+	return status.Error(codes.Internal, fmt.Sprintf("internal error: %v", err))
+}
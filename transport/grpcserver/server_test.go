@@ -0,0 +1,184 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/rendikr/billing-engine/proto/billingpb"
+	"github.com/rendikr/billing-engine/service"
+)
+
+func TestCreateLoanAndPay(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	loan, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-1", BorrowerId: "borrower-1", Principal: "5000000"})
+	if err != nil {
+		t.Fatalf("CreateLoan failed: %v", err)
+	}
+	if loan.GetTotalAmount() != "5500000" || loan.GetWeeklyPayment() != "110000" || loan.GetDurationWeeks() != 50 || loan.GetCurrency() != "IDR" {
+		t.Errorf("Unexpected loan %+v", loan)
+	}
+
+	paid, err := client.MakePayment(ctx, &billingpb.MakePaymentRequest{LoanId: "loan-1", Amount: "110000"})
+	if err != nil {
+		t.Fatalf("MakePayment failed: %v", err)
+	}
+	if paid.GetOutstanding() != "5390000" {
+		t.Errorf("Expected outstanding 5390000, got %s", paid.GetOutstanding())
+	}
+
+	outstanding, err := client.GetOutstanding(ctx, &billingpb.GetOutstandingRequest{LoanId: "loan-1"})
+	if err != nil || outstanding.GetOutstanding() != "5390000" {
+		t.Errorf("Expected outstanding 5390000, got %v (err %v)", outstanding.GetOutstanding(), err)
+	}
+
+	delinquent, err := client.IsDelinquent(ctx, &billingpb.IsDelinquentRequest{LoanId: "loan-1"})
+	if err != nil || delinquent.GetIsDelinquent() {
+		t.Errorf("Expected loan not delinquent, got %v (err %v)", delinquent.GetIsDelinquent(), err)
+	}
+
+	schedule, err := client.GetSchedule(ctx, &billingpb.GetScheduleRequest{LoanId: "loan-1"})
+	if err != nil {
+		t.Fatalf("GetSchedule failed: %v", err)
+	}
+	if len(schedule.GetEntries()) != 50 {
+		t.Fatalf("Expected 50 schedule entries, got %d", len(schedule.GetEntries()))
+	}
+	if first := schedule.GetEntries()[0]; !first.GetIsPaid() || first.GetPaidAt() == nil {
+		t.Errorf("Expected week 1 paid with a timestamp, got %+v", first)
+	}
+	if second := schedule.GetEntries()[1]; second.GetIsPaid() || second.GetPaidAt() != nil {
+		t.Errorf("Expected week 2 unpaid, got %+v", second)
+	}
+
+	history, err := client.GetPaymentHistory(ctx, &billingpb.GetPaymentHistoryRequest{LoanId: "loan-1"})
+	if err != nil {
+		t.Fatalf("GetPaymentHistory failed: %v", err)
+	}
+	if len(history.GetPayments()) != 1 || history.GetPayments()[0].GetWeekNumber() != 1 {
+		t.Errorf("Expected one payment for week 1, got %+v", history.GetPayments())
+	}
+}
+
+func TestCreateLoanAndPay_USD(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	loan, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-1", BorrowerId: "borrower-1", Principal: "1001", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("CreateLoan failed: %v", err)
+	}
+	if loan.GetCurrency() != "USD" || loan.GetPrincipal() != "1001.00" || loan.GetTotalAmount() != "1101.10" || loan.GetWeeklyPayment() != "22.02" {
+		t.Errorf("Unexpected loan %+v", loan)
+	}
+
+	paid, err := client.MakePayment(ctx, &billingpb.MakePaymentRequest{LoanId: "loan-1", Amount: "22.02", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("MakePayment failed: %v", err)
+	}
+	if paid.GetOutstanding() != "1079.08" || paid.GetCurrency() != "USD" {
+		t.Errorf("Expected outstanding USD 1079.08, got %s %s", paid.GetCurrency(), paid.GetOutstanding())
+	}
+
+	// An amount without a currency is IDR, which doesn't match the loan
+	if _, err := client.MakePayment(ctx, &billingpb.MakePaymentRequest{LoanId: "loan-1", Amount: "22.02"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an IDR payment, got %v", err)
+	}
+
+	schedule, err := client.GetSchedule(ctx, &billingpb.GetScheduleRequest{LoanId: "loan-1"})
+	if err != nil || schedule.GetCurrency() != "USD" || schedule.GetEntries()[0].GetAmount() != "22.02" {
+		t.Errorf("Expected a USD schedule of 22.02 installments, got %v (err %v)", schedule, err)
+	}
+	history, err := client.GetPaymentHistory(ctx, &billingpb.GetPaymentHistoryRequest{LoanId: "loan-1"})
+	if err != nil || history.GetCurrency() != "USD" || history.GetPayments()[0].GetAmount() != "22.02" {
+		t.Errorf("Expected a USD 22.02 payment, got %v (err %v)", history, err)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-1", BorrowerId: "borrower-1", Principal: "5000000"}); err != nil {
+		t.Fatalf("CreateLoan failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"Unknown loan", func() error {
+			_, err := client.GetOutstanding(ctx, &billingpb.GetOutstandingRequest{LoanId: "missing"})
+			return err
+		}, codes.NotFound},
+		{"Duplicate loan", func() error {
+			_, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-1", BorrowerId: "borrower-1", Principal: "5000000"})
+			return err
+		}, codes.AlreadyExists},
+		{"Malformed principal", func() error {
+			_, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-2", BorrowerId: "borrower-1", Principal: "abc"})
+			return err
+		}, codes.InvalidArgument},
+		{"Unknown interest model", func() error {
+			_, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-2", BorrowerId: "borrower-1", Principal: "5000000", InterestModel: "daily"})
+			return err
+		}, codes.InvalidArgument},
+		{"Unsupported currency", func() error {
+			_, err := client.CreateLoan(ctx, &billingpb.CreateLoanRequest{LoanId: "loan-2", BorrowerId: "borrower-1", Principal: "5000000", Currency: "EUR"})
+			return err
+		}, codes.InvalidArgument},
+		{"Wrong currency", func() error {
+			_, err := client.MakePayment(ctx, &billingpb.MakePaymentRequest{LoanId: "loan-1", Amount: "110000", Currency: "USD"})
+			return err
+		}, codes.InvalidArgument},
+		{"Wrong amount", func() error {
+			_, err := client.MakePayment(ctx, &billingpb.MakePaymentRequest{LoanId: "loan-1", Amount: "1"})
+			return err
+		}, codes.InvalidArgument},
+		{"Out of sequence", func() error {
+			_, err := client.MakePayment(ctx, &billingpb.MakePaymentRequest{LoanId: "loan-1", Amount: "110000", WeekNumber: 3})
+			return err
+		}, codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// newTestClient serves a fresh BillingService over an in-memory listener
+func newTestClient(t *testing.T) billingpb.BillingServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	billingpb.RegisterBillingServiceServer(server, NewServer(service.NewBillingService(service.NewInMemoryRepository())))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return billingpb.NewBillingServiceClient(conn)
+}
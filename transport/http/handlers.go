@@ -0,0 +1,193 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/rendikr/billing-engine/domain"
+)
+
+type createLoanRequest struct {
+	LoanID             string           `json:"loan_id"`
+	BorrowerID         string           `json:"borrower_id"`
+	Principal          domain.Money     `json:"principal"`
+	DurationWeeks      int              `json:"duration_weeks,omitempty"`       // Defaults to 50
+	AnnualInterestRate *decimal.Decimal `json:"annual_interest_rate,omitempty"` // Defaults to 0.10
+	InterestModel      string           `json:"interest_model,omitempty"`       // flat, revolving or compound_weekly
+}
+
+type loanResponse struct {
+	LoanID        string       `json:"loan_id"`
+	BorrowerID    string       `json:"borrower_id"`
+	Principal     domain.Money `json:"principal"`
+	TotalAmount   domain.Money `json:"total_amount"`
+	WeeklyPayment domain.Money `json:"weekly_payment"`
+	DurationWeeks int          `json:"duration_weeks"`
+	InterestModel string       `json:"interest_model"`
+}
+
+type outstandingResponse struct {
+	LoanID      string       `json:"loan_id"`
+	Outstanding domain.Money `json:"outstanding"`
+}
+
+type delinquentResponse struct {
+	LoanID       string `json:"loan_id"`
+	IsDelinquent bool   `json:"is_delinquent"`
+}
+
+type paymentRequest struct {
+	Amount     domain.Money `json:"amount"`
+	WeekNumber int          `json:"week_number,omitempty"` // Omit to pay the next due week
+}
+
+type scheduleEntryResponse struct {
+	WeekNumber int          `json:"week_number"`
+	Amount     domain.Money `json:"amount"`
+	PaidAmount domain.Money `json:"paid_amount"`
+	IsPaid     bool         `json:"is_paid"`
+	PaidAt     *time.Time   `json:"paid_at,omitempty"`
+}
+
+func (h *handler) createLoan(w http.ResponseWriter, r *http.Request) {
+	var req createLoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if req.LoanID == "" || req.BorrowerID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("loan_id and borrower_id are required"))
+		return
+	}
+
+	terms := domain.DefaultTerms()
+	if req.DurationWeeks != 0 {
+		terms.DurationWeeks = req.DurationWeeks
+	}
+	if req.AnnualInterestRate != nil {
+		terms.AnnualInterestRate = *req.AnnualInterestRate
+	}
+	if req.InterestModel != "" {
+		model, ok := domain.ParseInterestModel(req.InterestModel)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown interest model %q", req.InterestModel))
+			return
+		}
+		terms.InterestModel = model
+	}
+
+	loan, err := h.svc.CreateLoan(r.Context(), req.LoanID, req.BorrowerID, req.Principal, terms)
+	if err != nil {
+		writeError(w, statusForError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, loanResponse{
+		LoanID:        loan.ID,
+		BorrowerID:    loan.BorrowerID,
+		Principal:     loan.Principal,
+		TotalAmount:   loan.TotalAmount,
+		WeeklyPayment: loan.WeeklyPayment,
+		DurationWeeks: loan.DurationWeeks,
+		InterestModel: loan.InterestModel.String(),
+	})
+}
+
+func (h *handler) getLoan(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.findLoan(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loan.ToDTO())
+}
+
+func (h *handler) getOutstanding(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.findLoan(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, outstandingResponse{
+		LoanID:      loan.ID,
+		Outstanding: loan.GetOutstanding(),
+	})
+}
+
+func (h *handler) getDelinquent(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.findLoan(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delinquentResponse{
+		LoanID:       loan.ID,
+		IsDelinquent: loan.IsDelinquent(),
+	})
+}
+
+func (h *handler) makePayment(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.findLoan(w, r)
+	if !ok {
+		return
+	}
+
+	var req paymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var err error
+	if req.WeekNumber == 0 {
+		err = h.svc.MakeNextPayment(r.Context(), loan.ID, req.Amount)
+	} else {
+		err = h.svc.MakePayment(r.Context(), loan.ID, req.Amount, req.WeekNumber)
+	}
+	if err != nil {
+		writeError(w, statusForError(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, outstandingResponse{
+		LoanID:      loan.ID,
+		Outstanding: loan.GetOutstanding(),
+	})
+}
+
+func (h *handler) getSchedule(w http.ResponseWriter, r *http.Request) {
+	loan, ok := h.findLoan(w, r)
+	if !ok {
+		return
+	}
+
+	schedule := loan.GetSchedule()
+	entries := make([]scheduleEntryResponse, 0, len(schedule))
+	for _, entry := range schedule {
+		entries = append(entries, scheduleEntryResponse{
+			WeekNumber: entry.WeekNumber,
+			Amount:     entry.Amount,
+			PaidAmount: entry.PaidAmount,
+			IsPaid:     entry.IsPaid,
+			PaidAt:     entry.PaidAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// findLoan looks up the loan named by the {id} path segment, writing an error
+// response (404 when it doesn't exist) and returning false on failure
+func (h *handler) findLoan(w http.ResponseWriter, r *http.Request) (*domain.Loan, bool) {
+	loan, err := h.svc.GetLoan(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, statusForError(err), err)
+		return nil, false
+	}
+	return loan, true
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/rendikr/billing-engine/service"
+)
+
+func TestCreateLoan(t *testing.T) {
+	router := newTestRouter()
+
+	rec := doRequest(router, http.MethodPost, "/loans", `{"loan_id":"loan-1","borrower_id":"borrower-1","principal":"5000000"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp loanResponse
+	decodeBody(t, rec, &resp)
+	if resp.LoanID != "loan-1" || !resp.TotalAmount.Equals(domain.NewMoney(5500000)) || !resp.WeeklyPayment.Equals(domain.NewMoney(110000)) {
+		t.Errorf("Unexpected loan response %+v", resp)
+	}
+
+	// Same ID again
+	rec = doRequest(router, http.MethodPost, "/loans", `{"loan_id":"loan-1","borrower_id":"borrower-1","principal":"5000000"}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for duplicate loan, got %d", rec.Code)
+	}
+}
+
+func TestCreateLoan_Validation(t *testing.T) {
+	router := newTestRouter()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"Malformed JSON", `{"loan_id":`},
+		{"Missing borrower", `{"loan_id":"loan-1","principal":"5000000"}`},
+		{"Blank borrower", `{"loan_id":"loan-1","borrower_id":"  ","principal":"5000000"}`},
+		{"Zero principal", `{"loan_id":"loan-1","borrower_id":"borrower-1","principal":"0"}`},
+		{"Invalid duration", `{"loan_id":"loan-1","borrower_id":"borrower-1","principal":"5000000","duration_weeks":-1}`},
+		{"Negative rate", `{"loan_id":"loan-1","borrower_id":"borrower-1","principal":"5000000","annual_interest_rate":"-0.1"}`},
+		{"Unknown interest model", `{"loan_id":"loan-1","borrower_id":"borrower-1","principal":"5000000","interest_model":"daily"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(router, http.MethodPost, "/loans", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d: %s", rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestGetLoan(t *testing.T) {
+	router, svc := newTestRouterWithService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	rec := doRequest(router, http.MethodGet, "/loans/loan-1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp domain.LoanDTO
+	decodeBody(t, rec, &resp)
+	if resp.ID != "loan-1" || !resp.Outstanding.Equals(domain.NewMoney(5390000)) || resp.NextDueWeek != 2 || len(resp.Schedule) != 50 {
+		t.Errorf("Unexpected loan response %+v", resp)
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/loans/missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing loan, got %d", rec.Code)
+	}
+}
+
+func TestGetOutstanding(t *testing.T) {
+	router, svc := newTestRouterWithService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	rec := doRequest(router, http.MethodGet, "/loans/loan-1/outstanding", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp outstandingResponse
+	decodeBody(t, rec, &resp)
+	if !resp.Outstanding.Equals(domain.NewMoney(5500000)) {
+		t.Errorf("Expected outstanding IDR 5500000, got %s", resp.Outstanding)
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/loans/missing/outstanding", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing loan, got %d", rec.Code)
+	}
+}
+
+func TestGetDelinquent(t *testing.T) {
+	router, svc := newTestRouterWithService()
+	loan, _ := svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	loan.SetCurrentWeek(3)
+
+	rec := doRequest(router, http.MethodGet, "/loans/loan-1/delinquent", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp delinquentResponse
+	decodeBody(t, rec, &resp)
+	if !resp.IsDelinquent {
+		t.Error("Expected loan to be delinquent at week 3 with no payments")
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/loans/missing/delinquent", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing loan, got %d", rec.Code)
+	}
+}
+
+func TestMakePayment(t *testing.T) {
+	router, svc := newTestRouterWithService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+
+	rec := doRequest(router, http.MethodPost, "/loans/loan-1/payments", `{"amount":"110000","week_number":1}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp outstandingResponse
+	decodeBody(t, rec, &resp)
+	if !resp.Outstanding.Equals(domain.NewMoney(5390000)) {
+		t.Errorf("Expected outstanding IDR 5390000, got %s", resp.Outstanding)
+	}
+
+	// Next due week when week_number is omitted
+	if rec := doRequest(router, http.MethodPost, "/loans/loan-1/payments", `{"amount":110000}`); rec.Code != http.StatusCreated {
+		t.Errorf("Expected 201 for next payment, got %d: %s", rec.Code, rec.Body)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		expected int
+	}{
+		{"Wrong amount", "/loans/loan-1/payments", `{"amount":"100000","week_number":3}`, http.StatusBadRequest},
+		{"Already paid", "/loans/loan-1/payments", `{"amount":"110000","week_number":1}`, http.StatusBadRequest},
+		{"Out of sequence", "/loans/loan-1/payments", `{"amount":"110000","week_number":5}`, http.StatusBadRequest},
+		{"Wrong currency", "/loans/loan-1/payments", `{"amount":{"amount":"110000","currency":"USD"},"week_number":3}`, http.StatusBadRequest},
+		{"Malformed JSON", "/loans/loan-1/payments", `{"amount":`, http.StatusBadRequest},
+		{"Missing loan", "/loans/missing/payments", `{"amount":"110000","week_number":1}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(router, http.MethodPost, tt.path, tt.body)
+			if rec.Code != tt.expected {
+				t.Errorf("Expected %d, got %d: %s", tt.expected, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestGetSchedule(t *testing.T) {
+	router, svc := newTestRouterWithService()
+	svc.CreateLoan(t.Context(), "loan-1", "borrower-1", domain.NewMoney(5000000), domain.DefaultTerms())
+	svc.MakePayment(t.Context(), "loan-1", domain.NewMoney(110000), 1)
+
+	rec := doRequest(router, http.MethodGet, "/loans/loan-1/schedule", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var entries []scheduleEntryResponse
+	decodeBody(t, rec, &entries)
+	if len(entries) != 50 {
+		t.Fatalf("Expected 50 schedule entries, got %d", len(entries))
+	}
+	if !entries[0].IsPaid || entries[0].PaidAt == nil || entries[1].IsPaid || entries[1].PaidAt != nil || !entries[1].Amount.Equals(domain.NewMoney(110000)) {
+		t.Errorf("Unexpected schedule start %+v %+v", entries[0], entries[1])
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/loans/missing/schedule", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing loan, got %d", rec.Code)
+	}
+}
+
+// Helper functions
+func newTestRouter() http.Handler {
+	router, _ := newTestRouterWithService()
+	return router
+}
+
+func newTestRouterWithService() (http.Handler, *service.BillingService) {
+	svc := service.NewBillingService(service.NewInMemoryRepository())
+	return NewRouter(svc), svc
+}
+
+func doRequest(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", rec.Body, err)
+	}
+}
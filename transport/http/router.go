@@ -0,0 +1,95 @@
+// Package http exposes the billing service over a JSON REST API
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rendikr/billing-engine/domain"
+	"github.com/rendikr/billing-engine/service"
+)
+
+// handler serves the REST API on top of a BillingService
+type handler struct {
+	svc *service.BillingService
+}
+
+// NewRouter returns an http.Handler serving the billing API:
+//
+//	POST /loans                    create a loan
+//	GET  /loans/{id}               loan details, see domain.LoanDTO
+//	GET  /loans/{id}/outstanding   outstanding balance
+//	GET  /loans/{id}/delinquent    delinquency status
+//	POST /loans/{id}/payments      pay a week (or the next due week)
+//	GET  /loans/{id}/schedule      payment schedule
+func NewRouter(svc *service.BillingService) http.Handler {
+	h := &handler{svc: svc}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /loans", h.createLoan)
+	mux.HandleFunc("GET /loans/{id}", h.getLoan)
+	mux.HandleFunc("GET /loans/{id}/outstanding", h.getOutstanding)
+	mux.HandleFunc("GET /loans/{id}/delinquent", h.getDelinquent)
+	mux.HandleFunc("POST /loans/{id}/payments", h.makePayment)
+	mux.HandleFunc("GET /loans/{id}/schedule", h.getSchedule)
+
+	return mux
+}
+
+// errorResponse is the body of every non-2xx response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// validationErrors are domain errors caused by the request, reported as 400
+var validationErrors = []error{
+	domain.ErrInvalidPaymentAmount,
+	domain.ErrNegativeAmount,
+	domain.ErrInvalidWeekNumber,
+	domain.ErrLoanFullyPaid,
+	domain.ErrWeekAlreadyPaid,
+	domain.ErrPaymentOutOfSequence,
+	domain.ErrMustCatchUp,
+	domain.ErrInvalidPayoffAmount,
+	domain.ErrInvalidPrincipal,
+	domain.ErrInvalidDuration,
+	domain.ErrInvalidInterestRate,
+	domain.ErrInterestRateOutOfRange,
+	domain.ErrInvalidRateBounds,
+	domain.ErrInvalidDelinquencyThreshold,
+	domain.ErrInvalidStartWeek,
+	domain.ErrInvalidGracePeriod,
+	domain.ErrCurrencyMismatch,
+	domain.ErrWeekNotPaid,
+	domain.ErrReversalOutOfSequence,
+}
+
+// statusForError maps a service or domain error to an HTTP status code
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrLoanNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrLoanAlreadyExists), errors.Is(err, domain.ErrLoanArchived):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidLoanID), errors.Is(err, service.ErrInvalidBorrowerID):
+		return http.StatusBadRequest
+	}
+
+	for _, target := range validationErrors {
+		if errors.Is(err, target) {
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}